@@ -5,9 +5,11 @@ go 1.23
 toolchain go1.23.6
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/Knetic/govaluate v3.0.0+incompatible
 	github.com/jackc/pgx/v5 v5.6.0
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/xuri/excelize/v2 v2.9.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -2,12 +2,21 @@
 package app
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
 
 	"etl-tool/internal/config"
 	etlio "etl-tool/internal/io"
@@ -17,6 +26,7 @@ import (
 	"etl-tool/internal/util"
 
 	"github.com/Knetic/govaluate"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 // Define common application-level errors.
@@ -24,6 +34,8 @@ var (
 	ErrUsage          = errors.New("usage error")
 	ErrConfigNotFound = errors.New("configuration file not found")
 	ErrMissingArgs    = errors.New("missing required arguments")
+	ErrEmptyInput     = errors.New("source produced no records")
+	ErrExecNotAllowed = errors.New("exec transform requires the -allow-exec flag")
 )
 
 // --- Interfaces for Mocking ---
@@ -48,6 +60,7 @@ var (
 	}
 	osMkdirAllFunc = os.MkdirAll
 	osStatFunc     = os.Stat
+	stdoutWriter   io.Writer = os.Stdout
 )
 
 // AppRunner encapsulates the application's execution logic.
@@ -80,6 +93,11 @@ func (a *AppRunner) Run(args []string) error {
 	logLevelStr := fs.String("loglevel", "info", "Logging level")
 	dryRunFlag := fs.Bool("dry-run", false, "Perform dry run")
 	fipsFlag := fs.Bool("fips", false, "Enable FIPS mode")
+	allowExecFlag := fs.Bool("allow-exec", false, "Allow the exec transform to run external commands")
+	validateOutputFlag := fs.String("validate-output", "text", "Config validation error format: text or json")
+	validateOutputFileFlag := fs.String("validate-output-file", "", "Write JSON validation errors to this file instead of stdout")
+	profileFlag := fs.Bool("profile", false, "Print per-column statistics of the source instead of running the ETL")
+	profileFormatFlag := fs.String("profile-format", "text", "Profile report format: text or json")
 	helpFlag := fs.Bool("help", false, "Show help")
 
 	if err := fs.Parse(args); err != nil {
@@ -87,22 +105,40 @@ func (a *AppRunner) Run(args []string) error {
 		logging.Logf(logging.Error, "Failed to parse args: %v", err); return fmt.Errorf("%w: %v", ErrUsage, err)
 	}
 	if *helpFlag || (len(args) == 0 && !anyFlagsSet(fs)) { a.Usage(os.Stderr); return nil }
+	if vo := strings.ToLower(*validateOutputFlag); vo != "text" && vo != "json" { logging.Logf(logging.Error, "Invalid -validate-output '%s' (must be text or json).", *validateOutputFlag); return fmt.Errorf("%w: invalid -validate-output '%s'", ErrUsage, *validateOutputFlag) }
+
+	if pf := strings.ToLower(*profileFormatFlag); pf != "text" && pf != "json" { logging.Logf(logging.Error, "Invalid -profile-format '%s' (must be text or json).", *profileFormatFlag); return fmt.Errorf("%w: invalid -profile-format '%s'", ErrUsage, *profileFormatFlag) }
 
 	logging.SetupLogging(*logLevelStr)
 	if _, err := osStatFunc(*configFile); err != nil {
 		if os.IsNotExist(err) { logging.Logf(logging.Error, "Config file '%s' not found.", *configFile); return ErrConfigNotFound }
 		return fmt.Errorf("failed to stat config file '%s': %w", *configFile, err)
 	}
-	cfg, err := config.LoadConfig(*configFile); if err != nil { logging.Logf(logging.Error, "Error loading/validating config '%s': %v", *configFile, err); return err }
+	loadConfig := config.LoadConfig; if *profileFlag { loadConfig = config.LoadSourceConfig }
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		var verr *config.ValidationError
+		if strings.EqualFold(*validateOutputFlag, "json") && errors.As(err, &verr) {
+			if writeErr := writeValidationJSON(verr, *configFile, *validateOutputFileFlag); writeErr != nil { logging.Logf(logging.Error, "Failed to write validation output: %v", writeErr) }
+		}
+		logging.Logf(logging.Error, "Error loading/validating config '%s': %v", *configFile, err); return err
+	}
 
 	if !isFlagSet(fs, "loglevel") && cfg.Logging.Level != "" { logging.SetupLogging(cfg.Logging.Level) }
 	logging.Logf(logging.Info, "Starting ETL with config: %s", *configFile)
 	fipsEnabled := *fipsFlag; if !isFlagSet(fs, "fips") { fipsEnabled = cfg.FIPSMode }
 	if fipsEnabled { logging.Logf(logging.Info, "FIPS mode enabled."); transform.SetFIPSMode(fipsEnabled) }
+	transform.SetExecEnabled(*allowExecFlag)
+	if !*allowExecFlag && !*profileFlag && mappingsUseTransform(cfg.Mappings, "exec") { logging.Logf(logging.Error, "Config uses the 'exec' transform but -allow-exec was not given."); return ErrExecNotAllowed }
+	if *allowExecFlag { logging.Logf(logging.Warning, "External command execution enabled (-allow-exec).") }
 
-	inputFile := cfg.Source.File; if *flagInputFile != "" { inputFile = *flagInputFile; logging.Logf(logging.Info, "Override input: %s", inputFile) }; inputFile = util.ExpandEnvUniversal(inputFile)
+	inputFile := cfg.Source.File; if inputFile == "" { inputFile = cfg.Source.URL }; if *flagInputFile != "" { inputFile = *flagInputFile; logging.Logf(logging.Info, "Override input: %s", inputFile) }; inputFile = util.ExpandEnvUniversal(inputFile)
 	outputFile := cfg.Destination.File; if *flagOutputFile != "" { outputFile = *flagOutputFile; logging.Logf(logging.Info, "Override output: %s", outputFile) }; outputFile = util.ExpandEnvUniversal(outputFile)
+	cfg.Source.File, cfg.Destination.File = inputFile, outputFile // Reader/writer factories detect '.gz' compression from the resolved paths
 	finalDBConn := *dbConnStr; if finalDBConn == "" { finalDBConn = os.Getenv("DB_CREDENTIALS") }; finalDBConn = util.ExpandEnvUniversal(finalDBConn)
+	if *profileFlag { return runProfile(cfg.Source, inputFile, finalDBConn, strings.ToLower(*profileFormatFlag)) }
+
+	if cfg.Destination.SplitBy != "" && !strings.Contains(outputFile, config.SplitByPlaceholder) { return fmt.Errorf("output file '%s' must contain the %s placeholder when split_by is set", outputFile, config.SplitByPlaceholder) }
 
 	errorFile := ""; errorFileMsg := ""
 	if cfg.ErrorHandling != nil && cfg.ErrorHandling.ErrorFile != "" {
@@ -116,6 +152,7 @@ func (a *AppRunner) Run(args []string) error {
 
 	inputReader, err := newInputReaderFunc(cfg.Source, finalDBConn); if err != nil { return fmt.Errorf("failed to create input reader: %w", err) }
 	outputWriter, err := newOutputWriterFunc(cfg.Destination, finalDBConn); if err != nil { return fmt.Errorf("failed to create output writer: %w", err) }
+	if cfg.OutputSchema != nil { if fo, ok := outputWriter.(etlio.FieldOrderer); ok { fo.SetFieldOrder(outputSchemaFieldNames(cfg.OutputSchema)) } }
 	defer func() { if outputWriter != nil { logging.Logf(logging.Debug, "Closing output writer..."); if closeErr := outputWriter.Close(); closeErr != nil { logging.Logf(logging.Error, "Failed to close output writer: %v", closeErr) } else { logging.Logf(logging.Debug, "Output writer closed.") } } }()
 
 	var errorWriter etlio.ErrorWriter // Stays as interface type
@@ -143,9 +180,21 @@ func (a *AppRunner) Run(args []string) error {
 		}
 	}
 
+	if er, ok := outputWriter.(etlio.ErrorRouter); ok { er.SetErrorRouting(errorWriter, cfg.ErrorHandling != nil && strings.EqualFold(cfg.ErrorHandling.Mode, config.ErrorHandlingModeSkip)) }
+
+	var recordSchema *jsonschema.Schema
+	if cfg.SchemaValidation != nil { recordSchema, err = config.CompileJSONSchema(cfg.SchemaValidation.File); if err != nil { return fmt.Errorf("failed to load JSON Schema '%s': %w", cfg.SchemaValidation.File, err) } }
+
 	proc := newProcessorFunc(cfg.Mappings, cfg.Flattening, cfg.Dedup, cfg.ErrorHandling, errorWriter)
+	if cfg.RangeExpand != nil { if res, ok := proc.(processor.RangeExpandSetter); ok { res.SetRangeExpand(cfg.RangeExpand) } else { logging.Logf(logging.Warning, "Processor does not support rangeExpand; skipping.") } }
+	if cfg.RunningTotal != nil { if rts, ok := proc.(processor.RunningTotalSetter); ok { rts.SetRunningTotal(cfg.RunningTotal) } else { logging.Logf(logging.Warning, "Processor does not support runningTotal; skipping.") } }
+	if cfg.Concurrency > 1 { if cs, ok := proc.(processor.ConcurrencySetter); ok { cs.SetConcurrency(cfg.Concurrency) } else { logging.Logf(logging.Warning, "Processor does not support concurrency; processing sequentially.") } }
+	if cfg.NestFields != nil { if nfs, ok := proc.(processor.NestFieldsSetter); ok { nfs.SetNestFields(cfg.NestFields) } else { logging.Logf(logging.Warning, "Processor does not support nestFields; skipping.") } }
 
 	logging.Logf(logging.Info, "Extracting from %s...", cfg.Source.Type); initialRecords, err := inputReader.Read(inputFile); if err != nil { return fmt.Errorf("failed to read input data: %w", err) }; logging.Logf(logging.Info, "Extracted %d records.", len(initialRecords))
+	if len(cfg.Source.HeaderMap) > 0 { renameHeaders(initialRecords, cfg.Source.HeaderMap) }
+	if cfg.Source.IncludeSourceMetadata { sourceFile := inputFile; if strings.EqualFold(cfg.Source.Type, config.SourceTypePostgres) { sourceFile = "" }; injectSourceMetadata(initialRecords, sourceFile) }
+	if len(initialRecords) == 0 { if err := handleEmptyInput(cfg.ErrorHandling, cfg.Source.Type); err != nil { return err } }
 
 	filteredRecords := initialRecords
 	if cfg.Filter != "" {
@@ -162,11 +211,21 @@ func (a *AppRunner) Run(args []string) error {
 		logging.Logf(logging.Info, "Filter applied: %d kept, %d skipped.", len(keptRecords), skippedCount); filteredRecords = keptRecords
 	}
 	if len(filteredRecords) == 0 { logging.Logf(logging.Info, "No records after filtering."); return nil }
+	var schemaErrorCount int64
+	if recordSchema != nil && strings.EqualFold(cfg.SchemaValidation.Stage, config.SchemaStageInput) {
+		var skipped int64; filteredRecords, skipped, err = validateRecordsAgainstSchema(filteredRecords, recordSchema, config.SchemaStageInput, cfg.ErrorHandling, errorWriter); if err != nil { return err }; schemaErrorCount += skipped
+		if len(filteredRecords) == 0 { logging.Logf(logging.Info, "No records passed input schema validation%s.", errorFileMsg); return nil }
+	}
 
 	logging.Logf(logging.Info, "Processing %d records...", len(filteredRecords))
 	processedRecords, err := proc.ProcessRecords(filteredRecords)
 	if err != nil { return fmt.Errorf("failed during record processing: %w", err) }
-	finalRecordCount := len(processedRecords); errorCount := proc.GetErrorCount()
+	if cfg.OutputSchema != nil { processedRecords = applyOutputSchema(processedRecords, cfg.OutputSchema) }
+	if cfg.RunMetadata != nil { applyRunMetadata(processedRecords, cfg.RunMetadata, *configFile) }
+	if recordSchema != nil && !strings.EqualFold(cfg.SchemaValidation.Stage, config.SchemaStageInput) {
+		var skipped int64; processedRecords, skipped, err = validateRecordsAgainstSchema(processedRecords, recordSchema, config.SchemaStageOutput, cfg.ErrorHandling, errorWriter); if err != nil { return err }; schemaErrorCount += skipped
+	}
+	finalRecordCount := len(processedRecords); errorCount := proc.GetErrorCount() + schemaErrorCount
 	if cfg.Dedup != nil && len(cfg.Dedup.Keys) > 0 { logging.Logf(logging.Info, "Processed %d unique records.", finalRecordCount) } else { logging.Logf(logging.Info, "Processed %d records.", finalRecordCount) }
 	if errorCount > 0 { logging.Logf(logging.Warning, "%d records/parents skipped due to processing errors%s.", errorCount, errorFileMsg) }
 	if finalRecordCount == 0 { logging.Logf(logging.Info, "No records remaining after processing%s.", errorFileMsg); return nil }
@@ -177,12 +236,267 @@ func (a *AppRunner) Run(args []string) error {
 		if sampleSize > 0 { logging.Logf(logging.Debug, "Sample (first %d, masked):", sampleSize); for i := 0; i < sampleSize; i++ { logging.Logf(logging.Debug, "Record %d: %v", i, util.MaskSensitiveData(processedRecords[i])) } }
 	} else {
 		logging.Logf(logging.Info, "Loading %d records to %s...", finalRecordCount, cfg.Destination.Type)
-		if err := outputWriter.Write(processedRecords, outputFile); err != nil { return fmt.Errorf("failed to write output data: %w", err) }
+		if cfg.Destination.SplitBy != "" {
+			var fieldOrder []string; if cfg.OutputSchema != nil { fieldOrder = outputSchemaFieldNames(cfg.OutputSchema) }
+			if err := writeSplitOutput(processedRecords, cfg.Destination, fieldOrder, outputFile, finalDBConn); err != nil { return fmt.Errorf("failed to write output data: %w", err) }
+		} else if err := outputWriter.Write(processedRecords, outputFile); err != nil { return fmt.Errorf("failed to write output data: %w", err) }
 		logging.Logf(logging.Info, "Data loaded successfully.")
 	}
 	return nil
 }
 
 // Helper functions
+
+// renameHeaders replaces each record with a copy whose fields are renamed per headerMap (raw -> canonical).
+// Renamed fields take precedence over an unmapped field that already has the canonical name.
+func renameHeaders(records []map[string]interface{}, headerMap map[string]string) {
+	for i, record := range records {
+		if record == nil { continue }
+		renamed := make(map[string]interface{}, len(record))
+		for k, v := range record { if _, mapped := headerMap[k]; !mapped { renamed[k] = v } }
+		for k, v := range record { if canonical, mapped := headerMap[k]; mapped { if _, clash := renamed[canonical]; clash { logging.Logf(logging.Warning, "Record %d: header '%s' renamed to existing field '%s'; overwriting.", i, k, canonical) }; renamed[canonical] = v } }
+		records[i] = renamed
+	}
+}
+
+// injectSourceMetadata adds the synthetic source file and 1-based row fields to each record in place.
+func injectSourceMetadata(records []map[string]interface{}, sourceFile string) {
+	for i, record := range records {
+		if record == nil { continue }
+		for _, field := range []string{config.SourceFileField, config.SourceRowField} {
+			if _, exists := record[field]; exists { logging.Logf(logging.Warning, "Record %d already has field '%s'; overwriting with source metadata.", i, field) }
+		}
+		record[config.SourceFileField] = sourceFile
+		record[config.SourceRowField] = i + 1
+	}
+}
+
+// applyOutputSchema rebuilds each record with exactly the schema fields: extra fields are dropped and
+// missing or nil fields are set to the field's default.
+func applyOutputSchema(records []map[string]interface{}, schema *config.OutputSchemaConfig) []map[string]interface{} {
+	shaped := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		out := make(map[string]interface{}, len(schema.Fields))
+		for _, field := range schema.Fields {
+			if val, exists := record[field.Name]; exists && val != nil { out[field.Name] = val } else { out[field.Name] = field.Default }
+		}
+		shaped = append(shaped, out)
+	}
+	return shaped
+}
+
+// writeSplitOutput writes one file per distinct value of dest.SplitBy, substituting the value for the placeholder in
+// outputFile. Writers are created lazily as each key is first seen (keys keep first-seen order) and all are closed at the end.
+func writeSplitOutput(records []map[string]interface{}, dest config.DestinationConfig, fieldOrder []string, outputFile, dbConn string) (err error) {
+	groups := make(map[string][]map[string]interface{}); var keys []string
+	rawValues := make(map[string]string) // File name key -> the split_by value that produced it, to detect collisions
+	for _, record := range records {
+		raw := ""; if v := record[dest.SplitBy]; v != nil { raw = strings.TrimSpace(fmt.Sprintf("%v", v)) }
+		key := splitKeyFileName(record[dest.SplitBy])
+		if prev, seen := rawValues[key]; !seen { rawValues[key] = raw; keys = append(keys, key) } else if prev != raw { return fmt.Errorf("split_by values '%s' and '%s' both map to output file name key '%s'", prev, raw, key) }
+		groups[key] = append(groups[key], record)
+	}
+	writers := make([]etlio.OutputWriter, 0, len(keys))
+	defer func() { for _, w := range writers { if closeErr := w.Close(); closeErr != nil && err == nil { err = fmt.Errorf("failed to close split output writer: %w", closeErr) } } }()
+	for _, key := range keys {
+		writer, werr := newOutputWriterFunc(dest, dbConn); if werr != nil { return fmt.Errorf("failed to create output writer for split key '%s': %w", key, werr) }
+		writers = append(writers, writer)
+		if fieldOrder != nil { if fo, ok := writer.(etlio.FieldOrderer); ok { fo.SetFieldOrder(fieldOrder) } }
+		path := strings.ReplaceAll(outputFile, config.SplitByPlaceholder, key)
+		if werr := writer.Write(groups[key], path); werr != nil { return fmt.Errorf("split key '%s' (%s): %w", key, path, werr) }
+		logging.Logf(logging.Info, "Wrote %d records for %s=%s to %s", len(groups[key]), dest.SplitBy, key, path)
+	}
+	return nil
+}
+
+// splitKeyFileName renders a split_by value for use in a file name: path separators and other unsafe characters
+// become "_", "." and ".." become underscores, and nil or empty values become "empty". Distinct values can map to
+// the same name (e.g., "a/b" and "a_b"); writeSplitOutput rejects such collisions rather than merging the groups.
+func splitKeyFileName(value interface{}) string {
+	if value == nil { return "empty" }
+	key := strings.Map(func(r rune) rune { if r == '/' || r == '\\' || r == ':' || r == '*' || r == '?' || r == '"' || r == '<' || r == '>' || r == '|' || r < 32 { return '_' }; return r }, strings.TrimSpace(fmt.Sprintf("%v", value)))
+	if key == "" { return "empty" }; if key == "." || key == ".." { return strings.Repeat("_", len(key)) }
+	return key
+}
+
+// validationReport is the JSON document written by -validate-output json.
+type validationReport struct {
+	Config string                   `json:"config"`
+	Errors []config.ValidationIssue `json:"errors"`
+}
+
+// writeValidationJSON writes the validation issues as JSON to outputFile, or to stdout if outputFile is empty.
+func writeValidationJSON(verr *config.ValidationError, configFile, outputFile string) error {
+	data, err := json.MarshalIndent(validationReport{Config: configFile, Errors: verr.Issues}, "", "  ")
+	if err != nil { return fmt.Errorf("failed to encode validation errors: %w", err) }
+	data = append(data, '\n')
+	if outputFile == "" { _, err = stdoutWriter.Write(data); return err }
+	return os.WriteFile(util.ExpandEnvUniversal(outputFile), data, 0644)
+}
+
+// columnProfile holds the statistics reported by -profile for one source column.
+// Nulls counts records where the column is missing, nil, or a blank string; the other statistics cover the rest.
+type columnProfile struct {
+	Column    string `json:"column"`
+	Type      string `json:"type"`
+	Nulls     int    `json:"nulls"`
+	Distinct  int    `json:"distinct"`
+	MinLength int    `json:"min_length"`
+	MaxLength int    `json:"max_length"`
+}
+
+// profileReport is the document written by -profile.
+type profileReport struct {
+	Source  string          `json:"source"`
+	Records int             `json:"records"`
+	Columns []columnProfile `json:"columns"`
+}
+
+// runProfile reads the source (applying header_map) and writes a per-column statistics report to stdout.
+func runProfile(src config.SourceConfig, inputFile, dbConn, format string) error {
+	inputReader, err := newInputReaderFunc(src, dbConn); if err != nil { return fmt.Errorf("failed to create input reader: %w", err) }
+	logging.Logf(logging.Info, "Profiling %s source...", src.Type)
+	records, err := inputReader.Read(inputFile); if err != nil { return fmt.Errorf("failed to read input data: %w", err) }
+	if len(src.HeaderMap) > 0 { renameHeaders(records, src.HeaderMap) }
+	source := inputFile; if strings.EqualFold(src.Type, config.SourceTypePostgres) { source = "postgres query" }
+	return writeProfile(stdoutWriter, profileReport{Source: source, Records: len(records), Columns: profileRecords(records)}, format)
+}
+
+// profileRecords computes column statistics over all records. Columns are the union of record keys, sorted.
+// The column type is the detectType result shared by all non-null values ("float" for a mix of int and float,
+// "mixed" for other combinations, "null" when there are no non-null values).
+func profileRecords(records []map[string]interface{}) []columnProfile {
+	columnSet := make(map[string]bool)
+	for _, record := range records { for k := range record { columnSet[k] = true } }
+	columns := make([]string, 0, len(columnSet)); for k := range columnSet { columns = append(columns, k) }; sort.Strings(columns)
+
+	profiles := make([]columnProfile, 0, len(columns))
+	for _, column := range columns {
+		p := columnProfile{Column: column, MinLength: -1}
+		distinct := make(map[string]bool); types := make(map[string]bool)
+		for _, record := range records {
+			value := record[column]
+			valueType := transform.DetectType(value)
+			if valueType == "null" { p.Nulls++; continue }
+			types[valueType] = true
+			str := transform.ValueToStringForHash(value); distinct[str] = true
+			length := utf8.RuneCountInString(str)
+			if p.MinLength < 0 || length < p.MinLength { p.MinLength = length }
+			if length > p.MaxLength { p.MaxLength = length }
+		}
+		if p.MinLength < 0 { p.MinLength = 0 }
+		p.Distinct = len(distinct)
+		switch {
+		case len(types) == 0: p.Type = "null"
+		case len(types) == 1: for t := range types { p.Type = t }
+		case len(types) == 2 && types["int"] && types["float"]: p.Type = "float"
+		default: p.Type = "mixed"
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// writeProfile writes the report as indented JSON or as an aligned text table.
+func writeProfile(w io.Writer, report profileReport, format string) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(report, "", "  "); if err != nil { return fmt.Errorf("failed to encode profile: %w", err) }
+		_, err = w.Write(append(data, '\n')); return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Source: %s\nRecords: %d\n\n", report.Source, report.Records)
+	fmt.Fprintln(tw, "COLUMN\tTYPE\tNULLS\tDISTINCT\tMIN_LEN\tMAX_LEN")
+	for _, c := range report.Columns { fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\n", c.Column, c.Type, c.Nulls, c.Distinct, c.MinLength, c.MaxLength) }
+	return tw.Flush()
+}
+
+// Run identity shared by every record written by this process.
+var (
+	runInfoOnce  sync.Once
+	runID        string
+	runStartTime time.Time
+)
+
+// currentRunInfo returns the process-wide run ID and start time, generating them on first use.
+func currentRunInfo() (string, time.Time) {
+	runInfoOnce.Do(func() { runID = newRunID(); runStartTime = time.Now().UTC() })
+	return runID, runStartTime
+}
+
+// newRunID returns a random version 4 UUID.
+func newRunID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil { logging.Logf(logging.Warning, "Failed to generate random run ID: %v. Using timestamp.", err); return fmt.Sprintf("run-%d", time.Now().UnixNano()) }
+	b[6] = (b[6] & 0x0f) | 0x40; b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// applyRunMetadata stamps the configured run ID, run time, and config name fields onto every record in place.
+func applyRunMetadata(records []map[string]interface{}, rm *config.RunMetadataConfig, configFile string) {
+	id, started := currentRunInfo()
+	timeFormat := rm.TimeFormat; if timeFormat == "" { timeFormat = time.RFC3339 }
+	runTime := started.Format(timeFormat); configName := filepath.Base(configFile)
+	for _, record := range records {
+		if record == nil { continue }
+		if rm.RunID != "" { record[rm.RunID] = id }
+		if rm.RunTime != "" { record[rm.RunTime] = runTime }
+		if rm.ConfigName != "" { record[rm.ConfigName] = configName }
+	}
+}
+
+// validateRecordsAgainstSchema checks each record's JSON representation against schema. Failing records halt the run
+// or, in skip mode, are logged, written to the error writer, and dropped. Returns the kept records and the skip count.
+func validateRecordsAgainstSchema(records []map[string]interface{}, schema *jsonschema.Schema, stage string, eh *config.ErrorHandlingConfig, errorWriter etlio.ErrorWriter) ([]map[string]interface{}, int64, error) {
+	skipMode := eh != nil && eh.Mode == config.ErrorHandlingModeSkip; shouldLog := skipMode && (eh.LogErrors == nil || *eh.LogErrors)
+	kept := make([]map[string]interface{}, 0, len(records)); var skipped int64
+	for i, record := range records {
+		err := validateRecordSchema(schema, record)
+		if err == nil { kept = append(kept, record); continue }
+		err = fmt.Errorf("%s schema validation failed: %w", stage, err)
+		if !skipMode { logging.Logf(logging.Error, "Record %d: %v. Halting.", i, err); return nil, skipped, fmt.Errorf("error validating record %d (%s schema, halting): %w", i, stage, err) }
+		skipped++
+		if shouldLog { logging.Logf(logging.Warning, "Record %d: %v. Skipping. Record (masked): %v", i, err, util.MaskSensitiveData(record)) }
+		if errorWriter != nil { if writeErr := errorWriter.Write(record, err); writeErr != nil { logging.Logf(logging.Error, "Failed to write record %d (%s schema) error to error file: %v", i, stage, writeErr) } }
+	}
+	if skipped > 0 { logging.Logf(logging.Warning, "%s schema validation skipped %d of %d records.", stage, skipped, len(records)) }
+	return kept, skipped, nil
+}
+
+// validateRecordSchema validates a record as JSON: it is marshaled and decoded so that Go-specific values
+// (time.Time, typed slices, etc.) are checked the way JSON destinations would write them.
+func validateRecordSchema(schema *jsonschema.Schema, record map[string]interface{}) error {
+	raw, err := json.Marshal(record); if err != nil { return fmt.Errorf("record cannot be represented as JSON: %w", err) }
+	decoder := json.NewDecoder(bytes.NewReader(raw)); decoder.UseNumber()
+	var doc interface{}; if err := decoder.Decode(&doc); err != nil { return fmt.Errorf("record cannot be represented as JSON: %w", err) }
+	return schema.Validate(doc)
+}
+
+// outputSchemaFieldNames returns the schema field names in order.
+func outputSchemaFieldNames(schema *config.OutputSchemaConfig) []string {
+	names := make([]string, len(schema.Fields)); for i, field := range schema.Fields { names[i] = field.Name }; return names
+}
+
+// handleEmptyInput applies ErrorHandling.OnEmptyInput when the source yields no records.
+func handleEmptyInput(eh *config.ErrorHandlingConfig, sourceType string) error {
+	mode := config.DefaultOnEmptyInput; if eh != nil && eh.OnEmptyInput != "" { mode = strings.ToLower(eh.OnEmptyInput) }
+	switch mode {
+	case config.OnEmptyInputError: logging.Logf(logging.Error, "Source '%s' produced no records (on_empty_input: error).", sourceType); return fmt.Errorf("%w: source type '%s'", ErrEmptyInput, sourceType)
+	case config.OnEmptyInputWarn: logging.Logf(logging.Warning, "Source '%s' produced no records; nothing will be written.", sourceType)
+	default: logging.Logf(logging.Info, "Source '%s' produced no records.", sourceType)
+	}
+	return nil
+}
+
 func anyFlagsSet(fs *flag.FlagSet) bool { any := false; fs.Visit(func(*flag.Flag) { any = true }); return any }
 func isFlagSet(fs *flag.FlagSet, name string) bool { set := false; fs.Visit(func(f *flag.Flag) { if f.Name == name { set = true } }); return set }
+
+// mappingsUseTransform reports whether any mapping rule uses the named transform (case-insensitive, ignoring shorthand),
+// including transforms nested as the 'then' of coalesce or applyIf.
+func mappingsUseTransform(mappings []config.MappingRule, name string) bool { for _, m := range mappings { if transformUses(m.Transform, m.Params, name) { return true } }; return false }
+
+// transformUses reports whether transformStr or its nested 'then' transform (recursively, through 'thenParams') is the named transform.
+func transformUses(transformStr string, params map[string]interface{}, name string) bool {
+	if strings.EqualFold(strings.TrimSpace(strings.SplitN(transformStr, ":", 2)[0]), name) { return true }
+	then, ok := params["then"].(string); if !ok || then == "" { return false }
+	thenParams, _ := params["thenParams"].(map[string]interface{}); return transformUses(then, thenParams, name)
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
@@ -203,3 +205,36 @@ mappings: [{source: c, target: c}]
 errorHandling: { mode: skip, errorFile: "bad/dir/e.csv" }`, errFrag: "create directory for error file 'bad/dir/e.csv': mock mkdir fail"}, }; for _, tc := range testCases { t.Run(tc.name, func(t *testing.T) { mIn, mOut, mErr, mProc, _ := setupTestEnv(t); if mIn.readFunc == nil { mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"c": "default"}}, nil } }; if tc.setup != nil { tc.setup(t, mIn, mOut, mErr) }; cp := cfgPath; if tc.cfg != "" { cp = createTempYAML(t, tc.cfg) }; args := []string{"-config", cp}; err := runner.Run(args); if tc.errFrag != "" { if err == nil { t.Fatalf("Expected err %q, got nil", tc.errFrag) }; if !strings.Contains(err.Error(), tc.errFrag) { t.Errorf("Err mismatch: got %q, want %q", err.Error(), tc.errFrag) } } else { if err != nil && tc.name != "OutputCloseErr" { t.Fatalf("Expected no err, got %v", err) } }; if tc.errCnt != mProc.GetErrorCount() { t.Errorf("Processor err count: got %d, want %d", mProc.GetErrorCount(), tc.errCnt) } }) } }
 func Test_anyFlagsSet(t *testing.T) { testCases := []struct { n string; a []string; w bool }{ {"no", []string{}, false}, {"one", []string{"-config=a"}, true}, {"multi", []string{"-input=b", "-dry-run"}, true}, {"help", []string{"-help"}, true} }; for _, tc := range testCases { t.Run(tc.n, func(t *testing.T) { fs := flag.NewFlagSet("t", flag.ContinueOnError); fs.String("config", "", ""); fs.String("input", "", ""); fs.Bool("dry-run", false, ""); fs.Bool("help", false, ""); e := fs.Parse(tc.a); if e != nil && !errors.Is(e, flag.ErrHelp) { t.Fatal(e) }; g := anyFlagsSet(fs); if g != tc.w { t.Errorf("%v=%v,w %v", tc.a, g, tc.w) } }) } }
 func Test_isFlagSet(t *testing.T) { testCases := []struct { n, f string; a []string; w bool }{ {"set", "config", []string{"-config=a"}, true}, {"not", "config", []string{"-input=b"}, false}, {"bool set", "dry-run", []string{"-dry-run"}, true}, {"bool not", "dry-run", []string{"-config=a"}, false}, {"no", "config", []string{}, false}, {"help", "help", []string{"-help"}, true} }; for _, tc := range testCases { t.Run(tc.n, func(t *testing.T) { fs := flag.NewFlagSet("t", flag.ContinueOnError); fs.String("config", "", ""); fs.String("input", "", ""); fs.Bool("dry-run", false, ""); fs.Bool("help", false, ""); e := fs.Parse(tc.a); if e != nil && !errors.Is(e, flag.ErrHelp) { t.Fatal(e) }; g := isFlagSet(fs, tc.f); if g != tc.w { t.Errorf("%s(%q,%v)=%v,w %v", tc.n, tc.f, tc.a, g, tc.w) } }) } }
+func TestAppRunner_Run_SourceMetadata(t *testing.T) { runner := NewAppRunner(); mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = func(p string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"c": "a"}, {"c": "b"}}, nil }; cp := createTempYAML(t, `
+source: { type: csv, file: data/in.csv, includeSourceMetadata: true }
+destination: { type: json, file: o.json }
+mappings: [{ source: c, target: c }, { source: __source_file, target: file }, { source: __source_row, target: row }]`); err := runner.Run([]string{"-config", cp}); if err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"c": "a", "file": "data/in.csv", "row": 1}, {"c": "b", "file": "data/in.csv", "row": 2}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) } }
+func Test_injectSourceMetadata(t *testing.T) { logBuf := &bytes.Buffer{}; origLogLevel := logging.GetLevel(); logging.SetOutput(logBuf); logging.SetLevel(logging.Warning); defer func() { logging.SetOutput(os.Stderr); logging.SetLevel(origLogLevel) }(); recs := []map[string]interface{}{{"a": 1}, nil, {"a": 3, "__source_row": "x"}}; injectSourceMetadata(recs, "f.csv"); if !strings.Contains(logBuf.String(), "Record 2 already has field '__source_row'") { t.Errorf("Expected overwrite warning for __source_row, got log %q", logBuf.String()) }; want := []map[string]interface{}{{"a": 1, "__source_file": "f.csv", "__source_row": 1}, nil, {"a": 3, "__source_file": "f.csv", "__source_row": 3}}; if !reflect.DeepEqual(recs, want) { t.Errorf("got %v, want %v", recs, want) } }
+func TestAppRunner_Run_OnEmptyInput(t *testing.T) { runner := NewAppRunner(); testCases := []struct { name, mode string; wantErr bool }{ {"default ok", "", false}, {"ok", "ok", false}, {"warn", "warn", false}, {"error", "error", true} }; for _, tc := range testCases { t.Run(tc.name, func(t *testing.T) { mIn, mOut, _, mProc, _ := setupTestEnv(t); mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{}, nil }; eh := ""; if tc.mode != "" { eh = fmt.Sprintf("\nerrorHandling: { mode: halt, on_empty_input: %s }", tc.mode) }; cp := createTempYAML(t, "source: { type: csv, file: in.csv }\ndestination: { type: json, file: o.json }\nmappings: [{ source: a, target: a }]"+eh); err := runner.Run([]string{"-config", cp}); if tc.wantErr { if !errors.Is(err, ErrEmptyInput) { t.Fatalf("Expected ErrEmptyInput, got %v", err) } } else if err != nil { t.Fatalf("Expected no err, got %v", err) }; if mOut.writeCalls != 0 || mProc.processCalls != 0 { t.Errorf("Expected no processing/writes for empty input, got process=%d write=%d", mProc.processCalls, mOut.writeCalls) } }) } }
+func TestAppRunner_Run_OutputSchema(t *testing.T) { runner := NewAppRunner(); mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "1", "name": "a", "extra": "x"}, {"id": "2"}}, nil }; cp := createTempYAML(t, `
+source: { type: json, file: in.json }
+destination: { type: json, file: o.json }
+mappings: [{ source: id, target: id }, { source: name, target: name }, { source: extra, target: extra }]
+outputSchema: { fields: [{ name: id }, { name: name, default: unknown }, { name: status, default: new }] }`); err := runner.Run([]string{"-config", cp}); if err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"id": "1", "name": "a", "status": "new"}, {"id": "2", "name": "unknown", "status": "new"}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) } }
+func Test_applyOutputSchema(t *testing.T) { schema := &config.OutputSchemaConfig{Fields: []config.OutputFieldConfig{{Name: "a"}, {Name: "b", Default: 0}}}; got := applyOutputSchema([]map[string]interface{}{{"a": 1, "b": 2, "c": 3}, {"b": nil}, {}}, schema); want := []map[string]interface{}{{"a": 1, "b": 2}, {"a": nil, "b": 0}, {"a": nil, "b": 0}}; if !reflect.DeepEqual(got, want) { t.Errorf("got %v, want %v", got, want) }; if names := outputSchemaFieldNames(schema); !reflect.DeepEqual(names, []string{"a", "b"}) { t.Errorf("field names = %v", names) } }
+func TestAppRunner_Run_HeaderMap(t *testing.T) { runner := NewAppRunner(); mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"Cust ID#": "7", "E-Mail Addr": "a@b.c", "status": "x"}}, nil }; cp := createTempYAML(t, `
+source: { type: csv, file: in.csv, header_map: { "Cust ID#": customer_id, "E-Mail Addr": email } }
+destination: { type: json, file: o.json }
+mappings: [{ source: customer_id, target: id }, { source: email, target: email }, { source: status, target: status }]`); err := runner.Run([]string{"-config", cp}); if err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"id": "7", "email": "a@b.c", "status": "x"}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) } }
+func Test_renameHeaders(t *testing.T) { recs := []map[string]interface{}{{"A": 1, "B": 2, "c": 3}, nil, {"A": 4, "a": "old"}, {"A": 5, "B": 6}}; renameHeaders(recs, map[string]string{"A": "a", "B": "A"}); want := []map[string]interface{}{{"a": 1, "A": 2, "c": 3}, nil, {"a": 4}, {"a": 5, "A": 6}}; if !reflect.DeepEqual(recs, want) { t.Errorf("got %v, want %v", recs, want) } }
+func TestAppRunner_Run_AllowExec(t *testing.T) { runner := NewAppRunner(); defer transform.SetExecEnabled(false); cfgYAML := "source: { type: json, file: in.json }\ndestination: { type: json, file: o.json }\nmappings: [{ source: a, target: b, transform: exec, params: { command: cat } }]"; t.Run("without flag", func(t *testing.T) { _, mOut, _, mProc, _ := setupTestEnv(t); err := runner.Run([]string{"-config", createTempYAML(t, cfgYAML)}); if !errors.Is(err, ErrExecNotAllowed) { t.Fatalf("Expected ErrExecNotAllowed, got %v", err) }; if mProc.processCalls != 0 || mOut.writeCalls != 0 { t.Errorf("Expected no processing/writes, got process=%d write=%d", mProc.processCalls, mOut.writeCalls) } }); t.Run("with flag", func(t *testing.T) { setupTestEnv(t); err := runner.Run([]string{"-config", createTempYAML(t, cfgYAML), "-allow-exec"}); if err != nil { t.Fatalf("Expected no err, got %v", err) }; if !transform.IsExecEnabled() { t.Errorf("Expected exec to be enabled") } }) }
+func Test_mappingsUseTransform(t *testing.T) { m := []config.MappingRule{{Source: "a", Target: "a", Transform: "toUpper"}, {Source: "b", Target: "b", Transform: " EXEC "}}; if !mappingsUseTransform(m, "exec") { t.Errorf("Expected exec to be detected") }; if mappingsUseTransform(m[:1], "exec") { t.Errorf("Expected exec not to be detected") }; nested := []config.MappingRule{{Source: "a", Target: "a", Transform: "applyIf", Params: map[string]interface{}{"condition": "a == 'x'", "then": "coalesce", "thenParams": map[string]interface{}{"fields": []interface{}{"b"}, "then": "exec", "thenParams": map[string]interface{}{"command": "cat"}}}}}; if !mappingsUseTransform(nested, "exec") { t.Errorf("Expected exec nested under applyIf/coalesce to be detected") }; if mappingsUseTransform([]config.MappingRule{{Source: "a", Target: "a", Transform: "coalesce", Params: map[string]interface{}{"then": "toUpper"}}}, "exec") { t.Errorf("Expected nested toUpper not to match exec") } }
+func TestAppRunner_Run_RunMetadata(t *testing.T) { runner := NewAppRunner(); mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "1"}, {"id": "2"}, {"id": "3"}}, nil }; cp := createTempYAML(t, "source: { type: json, file: in.json }\ndestination: { type: json, file: o.json }\nmappings: [{ source: id, target: id }]\nrunMetadata: { run_id: etl_run_id, run_time: etl_run_time, config_name: etl_config }"); if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("Run err: %v", err) }; if len(mOut.lastRecords) != 3 { t.Fatalf("Expected 3 records, got %d", len(mOut.lastRecords)) }; first := mOut.lastRecords[0]; uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`); if id, _ := first["etl_run_id"].(string); !uuidRe.MatchString(id) { t.Errorf("run ID %q is not a v4 UUID", id) }; if rt, _ := first["etl_run_time"].(string); rt == "" { t.Errorf("run time missing") } else if _, err := time.Parse(time.RFC3339, rt); err != nil { t.Errorf("run time %q not RFC3339: %v", rt, err) }; if first["etl_config"] != filepath.Base(cp) { t.Errorf("config name = %v, want %q", first["etl_config"], filepath.Base(cp)) }; for i, rec := range mOut.lastRecords { if rec["etl_run_id"] != first["etl_run_id"] || rec["etl_run_time"] != first["etl_run_time"] { t.Errorf("record %d run metadata differs: %v vs %v", i, rec, first) } }; if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("second Run err: %v", err) }; if mOut.lastRecords[0]["etl_run_id"] != first["etl_run_id"] { t.Errorf("run ID changed within the same process") } }
+func Test_newRunID(t *testing.T) { a, b := newRunID(), newRunID(); if len(a) != 36 || a[14] != '4' { t.Errorf("unexpected run ID format %q", a) }; if a == b { t.Errorf("expected distinct IDs, got %q twice", a) } }
+func TestAppRunner_Run_ValidateOutputJSON(t *testing.T) { runner := NewAppRunner(); badYAML := "source: { type: csv }\ndestination: { type: json, file: o.json }\nmappings: [{ source: a, target: a, transform: shard }]"; decode := func(t *testing.T, data []byte) validationReport { t.Helper(); var report validationReport; if err := json.Unmarshal(data, &report); err != nil { t.Fatalf("Invalid JSON output %q: %v", data, err) }; return report }; check := func(t *testing.T, report validationReport, cp string) { t.Helper(); if report.Config != cp { t.Errorf("config = %q, want %q", report.Config, cp) }; wantPaths := []string{"Config.Source.File", "Config.Mappings[0].Params"}; if len(report.Errors) != len(wantPaths) { t.Fatalf("Expected %d errors, got %d: %+v", len(wantPaths), len(report.Errors), report.Errors) }; for i, want := range wantPaths { if report.Errors[i].Path != want || report.Errors[i].Message == "" { t.Errorf("error %d = %+v, want path %q with a message", i, report.Errors[i], want) } } }; t.Run("stdout", func(t *testing.T) { setupTestEnv(t); var buf bytes.Buffer; orig := stdoutWriter; stdoutWriter = &buf; defer func() { stdoutWriter = orig }(); cp := createTempYAML(t, badYAML); err := runner.Run([]string{"-config", cp, "-validate-output", "json"}); var verr *config.ValidationError; if !errors.As(err, &verr) { t.Fatalf("Expected ValidationError, got %v", err) }; check(t, decode(t, buf.Bytes()), cp) }); t.Run("file", func(t *testing.T) { setupTestEnv(t); cp := createTempYAML(t, badYAML); out := filepath.Join(t.TempDir(), "validation.json"); if err := runner.Run([]string{"-config", cp, "-validate-output", "json", "-validate-output-file", out}); err == nil { t.Fatalf("Expected validation error") }; data, err := os.ReadFile(out); if err != nil { t.Fatalf("Read output: %v", err) }; check(t, decode(t, data), cp) }); t.Run("text default writes nothing", func(t *testing.T) { setupTestEnv(t); var buf bytes.Buffer; orig := stdoutWriter; stdoutWriter = &buf; defer func() { stdoutWriter = orig }(); if err := runner.Run([]string{"-config", createTempYAML(t, badYAML)}); err == nil || !strings.Contains(err.Error(), "- Config.Source.File: is required") { t.Fatalf("Expected human-readable validation error, got %v", err) }; if buf.Len() != 0 { t.Errorf("Expected no stdout output, got %q", buf.String()) } }); t.Run("invalid format", func(t *testing.T) { setupTestEnv(t); if err := runner.Run([]string{"-config", createTempYAML(t, badYAML), "-validate-output", "xml"}); !errors.Is(err, ErrUsage) { t.Fatalf("Expected ErrUsage, got %v", err) } }) }
+func TestAppRunner_Run_CSVCellCleanup(t *testing.T) { runner := NewAppRunner(); _, mOut, _, _, _ := setupTestEnv(t); newInputReaderFunc = etlio.NewInputReader; newProcessorFunc = processor.NewProcessor; in := filepath.Join(t.TempDir(), "in.csv"); if err := os.WriteFile(in, []byte("id,name,note\n 1 ,  Alice  ,  \n"), 0644); err != nil { t.Fatalf("write input: %v", err) }; cp := createTempYAML(t, fmt.Sprintf("source: { type: csv, file: %s, trim_fields: true, treat_empty_as_null: true }\ndestination: { type: json, file: o.json }\nmappings: [{ source: id, target: id }, { source: name, target: name, transform: toUpperCase }, { source: note, target: note, transform: 'coalesce', params: { fields: [note, name] } }]", in)); if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"id": "1", "name": "ALICE", "note": "ALICE"}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) } }
+func TestAppRunner_Run_RunningTotal(t *testing.T) { runner := NewAppRunner(); mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": 1, "amt": 2}, {"id": 2, "amt": 3}, {"id": 3, "amt": 4}}, nil }; cp := createTempYAML(t, "source: { type: json, file: in.json }\ndestination: { type: json, file: o.json }\nmappings: [{ source: id, target: id }, { source: amt, target: amt }]\nrunningTotal: { field: amt, target: total }"); if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"id": 1, "amt": 2, "total": 2.0}, {"id": 2, "amt": 3, "total": 5.0}, {"id": 3, "amt": 4, "total": 9.0}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) } }
+func TestAppRunner_Run_SchemaValidation(t *testing.T) { runner := NewAppRunner(); schemaPath := filepath.Join(t.TempDir(), "record.schema.json"); if err := os.WriteFile(schemaPath, []byte(`{"type": "object", "required": ["id", "age"], "properties": {"id": {"type": "string"}, "age": {"type": "integer"}}}`), 0644); err != nil { t.Fatalf("write schema: %v", err) }; input := func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "1", "age": 30}, {"id": "2"}, {"id": "3", "age": "old"}}, nil }; cfgFor := func(stage, mode string) string { return fmt.Sprintf("source: { type: json, file: in.json }\ndestination: { type: json, file: o.json }\nmappings: [{ source: id, target: id }, { source: age, target: age }]\nschemaValidation: { file: %s, stage: %s }\nerrorHandling: { mode: %s, errorFile: errors.csv }", schemaPath, stage, mode) }; for _, stage := range []string{"input", "output"} { t.Run(stage+" skip", func(t *testing.T) { mIn, mOut, mErr, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; newCSVErrorWriterFunc = func(string) (etlio.ErrorWriter, error) { return mErr, nil }; mIn.readFunc = input; if err := runner.Run([]string{"-config", createTempYAML(t, cfgFor(stage, "skip"))}); err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"id": "1", "age": 30}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) }; if len(mErr.writeCalls) != 2 { t.Fatalf("Expected 2 error records, got %d", len(mErr.writeCalls)) }; wantFrags := []string{"missing properties: 'age'", "expected integer, but got string"}; if stage == "output" { wantFrags[0] = "expected integer, but got null" }; for i, wantFrag := range wantFrags { if msg := mErr.writeCalls[i].Err.Error(); !strings.Contains(msg, stage+" schema validation failed") || !strings.Contains(msg, wantFrag) { t.Errorf("error %d = %q, want %q stage failure containing %q", i, msg, stage, wantFrag) } } }) }; t.Run("halt", func(t *testing.T) { mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = input; err := runner.Run([]string{"-config", createTempYAML(t, cfgFor("output", "halt"))}); if err == nil || !strings.Contains(err.Error(), "error validating record 1 (output schema, halting)") { t.Fatalf("Expected halting schema error, got %v", err) }; if mOut.writeCalls != 0 { t.Errorf("Expected no writes, got %d", mOut.writeCalls) } }) }
+func Test_validateRecordSchema_JSONRepresentation(t *testing.T) { schema, err := config.CompileJSONSchema(writeTempSchema(t, `{"properties": {"when": {"type": "string"}, "tags": {"type": "array"}}}`)); if err != nil { t.Fatalf("compile: %v", err) }; if err := validateRecordSchema(schema, map[string]interface{}{"when": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "tags": []string{"a"}}); err != nil { t.Errorf("Expected time.Time and []string to validate as JSON string/array, got %v", err) } }
+func writeTempSchema(t *testing.T, content string) string { t.Helper(); p := filepath.Join(t.TempDir(), "schema.json"); if err := os.WriteFile(p, []byte(content), 0644); err != nil { t.Fatalf("write schema: %v", err) }; return p }
+func TestAppRunner_Run_RangeExpand(t *testing.T) { runner := NewAppRunner(); mIn, mOut, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "a", "days": "1-2"}, {"id": "b", "days": "5"}}, nil }; cp := createTempYAML(t, "source: { type: json, file: in.json }\ndestination: { type: json, file: o.json }\nmappings: [{ source: id, target: id }, { source: days, target: days }]\nrangeExpand: { source_field: days, target_field: days }"); if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("Run err: %v", err) }; want := []map[string]interface{}{{"id": "a", "days": 1}, {"id": "a", "days": 2}, {"id": "b", "days": 5}}; if !reflect.DeepEqual(mOut.lastRecords, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", mOut.lastRecords, want) } }
+func TestAppRunner_Run_Profile(t *testing.T) { runner := NewAppRunner(); records := func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "1", "name": "Ann", "score": "1.5"}, {"id": "2", "name": "", "score": "2"}, {"id": "3", "name": "Bob", "score": nil}, {"id": "4", "name": "Ann"}}, nil }; cfgYAML := "source: { type: csv, file: in.csv }"; t.Run("json", func(t *testing.T) { mIn, mOut, _, mProc, _ := setupTestEnv(t); mIn.readFunc = records; var buf bytes.Buffer; orig := stdoutWriter; stdoutWriter = &buf; defer func() { stdoutWriter = orig }(); if err := runner.Run([]string{"-config", createTempYAML(t, cfgYAML), "-profile", "-profile-format", "json"}); err != nil { t.Fatalf("Run err: %v", err) }; var report profileReport; if err := json.Unmarshal(buf.Bytes(), &report); err != nil { t.Fatalf("Invalid JSON %q: %v", buf.String(), err) }; want := profileReport{Source: "in.csv", Records: 4, Columns: []columnProfile{{Column: "id", Type: "int", Nulls: 0, Distinct: 4, MinLength: 1, MaxLength: 1}, {Column: "name", Type: "string", Nulls: 1, Distinct: 2, MinLength: 3, MaxLength: 3}, {Column: "score", Type: "float", Nulls: 2, Distinct: 2, MinLength: 1, MaxLength: 3}}}; if !reflect.DeepEqual(report, want) { t.Errorf("Profile mismatch:\ngot:  %+v\nwant: %+v", report, want) }; if mProc.processCalls != 0 || mOut.writeCalls != 0 { t.Errorf("Expected no processing/writes, got process=%d write=%d", mProc.processCalls, mOut.writeCalls) } }); t.Run("text", func(t *testing.T) { mIn, _, _, _, _ := setupTestEnv(t); mIn.readFunc = records; var buf bytes.Buffer; orig := stdoutWriter; stdoutWriter = &buf; defer func() { stdoutWriter = orig }(); if err := runner.Run([]string{"-config", createTempYAML(t, cfgYAML), "-profile"}); err != nil { t.Fatalf("Run err: %v", err) }; out := buf.String(); for _, want := range []string{"Records: 4", "COLUMN  TYPE", "name    string  1      2"} { if !strings.Contains(out, want) { t.Errorf("Expected %q in profile output:\n%s", want, out) } } }); t.Run("invalid format", func(t *testing.T) { setupTestEnv(t); if err := runner.Run([]string{"-config", createTempYAML(t, cfgYAML), "-profile", "-profile-format", "xml"}); !errors.Is(err, ErrUsage) { t.Fatalf("Expected ErrUsage, got %v", err) } }) }
+func TestAppRunner_Run_SplitBy(t *testing.T) { runner := NewAppRunner(); mIn, _, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; newOutputWriterFunc = etlio.NewOutputWriter; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "1", "region": "east"}, {"id": "2", "region": "west"}, {"id": "3", "region": "east"}}, nil }; outDir := t.TempDir(); cp := createTempYAML(t, fmt.Sprintf("source: { type: json, file: in.json }\ndestination: { type: json, file: '%s', split_by: region }\nmappings: [{ source: id, target: id }, { source: region, target: region }]", filepath.Join(outDir, "sales_{key}.json"))); if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("Run err: %v", err) }; want := map[string][]map[string]interface{}{"east": {{"id": "1", "region": "east"}, {"id": "3", "region": "east"}}, "west": {{"id": "2", "region": "west"}}}; entries, _ := os.ReadDir(outDir); if len(entries) != len(want) { t.Fatalf("Expected %d files, got %d", len(want), len(entries)) }; for region, wantRecs := range want { data, err := os.ReadFile(filepath.Join(outDir, "sales_"+region+".json")); if err != nil { t.Fatalf("read %s output: %v", region, err) }; var got []map[string]interface{}; if err := json.Unmarshal(data, &got); err != nil { t.Fatalf("decode %s output: %v", region, err) }; if !reflect.DeepEqual(got, wantRecs) { t.Errorf("%s output mismatch:\ngot:  %v\nwant: %v", region, got, wantRecs) } }; t.Run("override without placeholder", func(t *testing.T) { if err := runner.Run([]string{"-config", cp, "-output", filepath.Join(outDir, "all.json")}); err == nil || !strings.Contains(err.Error(), "must contain the {key} placeholder") { t.Fatalf("Expected placeholder error, got %v", err) } }) }
+func TestAppRunner_Run_NestFields(t *testing.T) { runner := NewAppRunner(); mIn, _, _, _, _ := setupTestEnv(t); newProcessorFunc = processor.NewProcessor; newOutputWriterFunc = etlio.NewOutputWriter; mIn.readFunc = func(string) ([]map[string]interface{}, error) { return []map[string]interface{}{{"id": "1", "city": "Oslo", "zip": "0150"}}, nil }; out := filepath.Join(t.TempDir(), "nested.json"); cp := createTempYAML(t, fmt.Sprintf("source: { type: json, file: in.json }\ndestination: { type: json, file: '%s' }\nmappings: [{ source: id, target: id }, { source: city, target: address.city }, { source: zip, target: address.zip }]\nnestFields: {}", out)); if err := runner.Run([]string{"-config", cp}); err != nil { t.Fatalf("Run err: %v", err) }; data, err := os.ReadFile(out); if err != nil { t.Fatalf("read output: %v", err) }; var got []map[string]interface{}; if err := json.Unmarshal(data, &got); err != nil { t.Fatalf("decode output: %v", err) }; want := []map[string]interface{}{{"id": "1", "address": map[string]interface{}{"city": "Oslo", "zip": "0150"}}}; if !reflect.DeepEqual(got, want) { t.Errorf("Output mismatch:\ngot:  %v\nwant: %v", got, want) } }
+func Test_splitKeyFileName(t *testing.T) { for in, want := range map[interface{}]string{"east": "east", "a/b:c": "a_b_c", " ": "empty", "..": "__", 42: "42"} { if got := splitKeyFileName(in); got != want { t.Errorf("splitKeyFileName(%v) = %q, want %q", in, got, want) } }; if got := splitKeyFileName(nil); got != "empty" { t.Errorf("splitKeyFileName(nil) = %q", got) } }
+func Test_writeSplitOutput_NameCollision(t *testing.T) { origWriter := newOutputWriterFunc; newOutputWriterFunc = etlio.NewOutputWriter; defer func() { newOutputWriterFunc = origWriter }(); outDir := t.TempDir(); dest := config.DestinationConfig{Type: "json", SplitBy: "r"}; err := writeSplitOutput([]map[string]interface{}{{"r": "a/b"}, {"r": "x"}, {"r": "a_b"}}, dest, nil, filepath.Join(outDir, "out_{key}.json"), ""); if err == nil || !strings.Contains(err.Error(), "split_by values 'a/b' and 'a_b' both map to output file name key 'a_b'") { t.Fatalf("Expected name collision error, got %v", err) }; if entries, _ := os.ReadDir(outDir); len(entries) != 0 { t.Errorf("Expected no output files on collision, got %d", len(entries)) }; if err := writeSplitOutput([]map[string]interface{}{{"r": "a"}, {"r": " a "}, {"r": nil}, {"r": ""}}, dest, nil, filepath.Join(outDir, "ok_{key}.json"), ""); err != nil { t.Errorf("Expected equal trimmed values and nil/empty to share a file, got %v", err) } }
@@ -152,18 +152,27 @@ mappings:
 	if cfg.Logging.Level != DefaultLogLevel {
 		t.Errorf("cfg.Logging.Level = %q, want default %q", cfg.Logging.Level, DefaultLogLevel)
 	}
+	if cfg.Concurrency != DefaultConcurrency {
+		t.Errorf("cfg.Concurrency = %d, want default %d", cfg.Concurrency, DefaultConcurrency)
+	}
 	if cfg.ErrorHandling == nil || cfg.ErrorHandling.Mode != ErrorHandlingModeHalt {
 		t.Errorf("cfg.ErrorHandling.Mode = %v, want default %q", cfg.ErrorHandling, ErrorHandlingModeHalt)
 	}
 	if cfg.ErrorHandling != nil && cfg.ErrorHandling.LogErrors != nil && *cfg.ErrorHandling.LogErrors {
 		t.Errorf("cfg.ErrorHandling.LogErrors = true, want nil or false for default halt mode")
 	}
+	if cfg.ErrorHandling != nil && cfg.ErrorHandling.OnEmptyInput != DefaultOnEmptyInput {
+		t.Errorf("cfg.ErrorHandling.OnEmptyInput = %q, want default %q", cfg.ErrorHandling.OnEmptyInput, DefaultOnEmptyInput)
+	}
 	if cfg.FIPSMode {
 		t.Error("cfg.FIPSMode = true, want default false")
 	}
 	if cfg.Source.Type == SourceTypeCSV && cfg.Source.Delimiter != DefaultCSVDelimiter {
 		t.Errorf("CSV Source Delimiter not defaulted correctly")
 	}
+	if cfg.Source.Type == SourceTypeCSV && cfg.Source.DuplicateHeader != DefaultDuplicateHeader {
+		t.Errorf("Default Source.DuplicateHeader mismatch: got %q, want %q", cfg.Source.DuplicateHeader, DefaultDuplicateHeader)
+	}
 	if cfg.Source.Type == SourceTypeXML && cfg.Source.XMLRecordTag != DefaultXMLRecordTag {
 		t.Errorf("XML Source XMLRecordTag not defaulted correctly")
 	}
@@ -211,6 +220,25 @@ dedup:
 	if cfgDedup.Dedup == nil || cfgDedup.Dedup.Strategy != DefaultDedupStrategy {
 		t.Errorf("cfgDedup.Dedup.Strategy = %v, want default %q", cfgDedup.Dedup, DefaultDedupStrategy)
 	}
+	tieBreakerDefaultYAML := `
+source: { type: json, file: in.json }
+destination: { type: json, file: out.json }
+mappings: [{ source: id, target: id }, { source: v, target: v }, { source: seq, target: seq }]
+dedup:
+  keys: [id]
+  strategy: min
+  strategyField: v
+  tie_breaker: seq
+`
+	filePathTie, cleanupTie := createTempConfigFile(t, tieBreakerDefaultYAML)
+	defer cleanupTie()
+	cfgTie, err := LoadConfig(filePathTie)
+	if err != nil {
+		t.Fatalf("LoadConfig() for tie-breaker defaults failed: %v", err)
+	}
+	if cfgTie.Dedup.TieBreaker != "seq" || cfgTie.Dedup.TieBreakerOrder != DefaultTieBreakerOrder {
+		t.Errorf("cfgTie.Dedup tie-breaker = %q/%q, want %q/%q", cfgTie.Dedup.TieBreaker, cfgTie.Dedup.TieBreakerOrder, "seq", DefaultTieBreakerOrder)
+	}
 	errorSkipDefaultYAML := `
 source: { type: json, file: in.json }
 destination: { type: json, file: out.json }
@@ -262,6 +290,27 @@ mappings:
 	}
 }
 
+// TestLoadSourceConfig tests loading a config that only needs a valid source section.
+func TestLoadSourceConfig(t *testing.T) {
+	filePath, cleanup := createTempConfigFile(t, "source:\n  type: csv\n  file: /input.csv\n")
+	defer cleanup()
+	cfg, err := LoadSourceConfig(filePath)
+	if err != nil {
+		t.Fatalf("LoadSourceConfig() error = %v, want nil for config without mappings or destination", err)
+	}
+	if cfg.Source.Delimiter != DefaultCSVDelimiter {
+		t.Errorf("cfg.Source.Delimiter = %q, want default %q", cfg.Source.Delimiter, DefaultCSVDelimiter)
+	}
+
+	badPath, badCleanup := createTempConfigFile(t, "source:\n  type: csv\nmappings: []\n")
+	defer badCleanup()
+	_, err = LoadSourceConfig(badPath)
+	assertValidationError(t, err, "Config.Source.File: is required for source type 'csv'")
+	if strings.Contains(err.Error(), "Mappings") {
+		t.Errorf("LoadSourceConfig() error = %v, want only source errors", err)
+	}
+}
+
 // TestLoadConfig_InvalidConfig tests loading valid YAML that fails schema validation.
 func TestLoadConfig_InvalidConfig(t *testing.T) {
 	invalidConfigYAML := `
@@ -308,6 +357,18 @@ errorHandling:
 func TestValidateConfig_ValidCases(t *testing.T) {
 	boolPtr := func(b bool) *bool { return &b }
 	intPtr := func(i int) *int { return &i }
+	lookupFile := filepath.Join(t.TempDir(), "lookup.yaml")
+	if err := os.WriteFile(lookupFile, []byte("a: b\n"), 0644); err != nil {
+		t.Fatalf("Failed to write lookup file: %v", err)
+	}
+	referenceFile := filepath.Join(t.TempDir(), "customers.csv")
+	if err := os.WriteFile(referenceFile, []byte("customer_id\nC1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write reference file: %v", err)
+	}
+	schemaFile := filepath.Join(t.TempDir(), "record.schema.json")
+	if err := os.WriteFile(schemaFile, []byte(`{"type": "object", "required": ["id"]}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
 
 	testCases := []struct {
 		name string
@@ -414,6 +475,78 @@ func TestValidateConfig_ValidCases(t *testing.T) {
 				Mappings:    []MappingRule{{Source: "id", Target: "id"}},
 			},
 		},
+		{
+			name: "Running total",
+			cfg: &ETLConfig{
+				Source:       SourceConfig{Type: "json", File: "in.json"},
+				Destination:  DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:     []MappingRule{{Source: "acct", Target: "acct"}, {Source: "amount", Target: "amount"}},
+				RunningTotal: &RunningTotalConfig{Field: "amount", Target: "balance", PartitionBy: []string{"acct"}},
+			},
+		},
+		{
+			name: "Range expand",
+			cfg: &ETLConfig{
+				Source:      SourceConfig{Type: "json", File: "in.json"},
+				Destination: DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:    []MappingRule{{Source: "id", Target: "id"}, {Source: "seats", Target: "seats"}},
+				RangeExpand: &RangeExpandConfig{SourceField: "seats", TargetField: "seat"},
+			},
+		},
+		{
+			name: "Run metadata",
+			cfg: &ETLConfig{
+				Source:      SourceConfig{Type: "json", File: "in.json"},
+				Destination: DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:    []MappingRule{{Source: "id", Target: "id"}},
+				RunMetadata: &RunMetadataConfig{RunID: "etl_run_id", RunTime: "etl_run_time", TimeFormat: "2006-01-02 15:04:05"},
+			},
+		},
+		{
+			name: "Schema validation",
+			cfg: &ETLConfig{
+				Source:           SourceConfig{Type: "json", File: "in.json"},
+				Destination:      DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:         []MappingRule{{Source: "id", Target: "id"}},
+				SchemaValidation: &SchemaValidationConfig{File: schemaFile, Stage: SchemaStageInput},
+			},
+		},
+		{
+			name: "HTTP source",
+			cfg: &ETLConfig{
+				Source: SourceConfig{
+					Type: "http", URL: "https://api.example.com/v1/items?active=true", Method: "get",
+					Headers: map[string]string{"Authorization": "Bearer ${API_TOKEN}"}, Timeout: "10s", RecordsPath: "data.items",
+				},
+				Destination: DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:    []MappingRule{{Source: "id", Target: "id"}},
+			},
+		},
+		{
+			name: "JSON records path",
+			cfg: &ETLConfig{
+				Source:      SourceConfig{Type: "json", File: "in.json", RecordsPath: "data.items"},
+				Destination: DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:    []MappingRule{{Source: "id", Target: "id"}},
+			},
+		},
+		{
+			name: "Concurrency with chained mappings",
+			cfg: &ETLConfig{
+				Source:      SourceConfig{Type: "json", File: "in.json"},
+				Destination: DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:    []MappingRule{{Source: "amount", Target: "amount", Transform: "toFloat"}, {Source: "amount", Target: "amount_fmt", Transform: "numberFormat"}},
+				Concurrency: 8,
+			},
+		},
+		{
+			name: "HTTP source URL in File",
+			cfg: &ETLConfig{
+				Source:      SourceConfig{Type: "http", File: "http://localhost:8080/export"},
+				Destination: DestinationConfig{Type: "json", File: "out.json"},
+				Mappings:    []MappingRule{{Source: "id", Target: "id"}},
+			},
+		},
 		{
 			name: "Deduplication Min Strategy",
 			cfg: &ETLConfig{
@@ -451,6 +584,86 @@ func TestValidateConfig_ValidCases(t *testing.T) {
 					{Source: "value", Target: "value", Transform: "validateNumericRange", Params: map[string]interface{}{"min": 0}},
 					{Source: "status", Target: "status", Transform: "validateAllowedValues", Params: map[string]interface{}{"values": []interface{}{"A", "B"}}},
 					{Source: "pwd", Target: "hash", Transform: "hash", Params: map[string]interface{}{"algorithm": "sha256", "fields": []interface{}{"pwd"}}},
+					{Source: "flag", Target: "flagBool", Transform: "toBoolCustom", Params: map[string]interface{}{"truthy": []interface{}{"Y", "ON"}, "falsy": []interface{}{"N", "OFF"}, "caseInsensitive": true}},
+					{Source: "pct", Target: "pctFraction", Transform: "parsePercent", Params: map[string]interface{}{"scale": "whole"}},
+					{Source: "price", Target: "priceAmount", Transform: "parseCurrency", Params: map[string]interface{}{"decimalSeparator": ",", "groupSeparator": ".", "symbols": []interface{}{"€"}}},
+					{Source: "price", Target: "priceCurrency", Transform: "parseCurrency", Params: map[string]interface{}{"output": "currency"}},
+					{Source: "country", Target: "countryIso", Transform: "countryCode", Params: map[string]interface{}{"direction": "toAlpha2"}},
+					{Source: "notes", Target: "notesRedacted", Transform: `regexRedact:\S+@\S+`},
+					{Source: "notes", Target: "notesMasked", Transform: "regexRedact", Params: map[string]interface{}{"pattern": "[0-9]", "replacement": "#"}},
+					{Source: "slug", Target: "slugClean", Transform: "collapseRepeats", Params: map[string]interface{}{"chars": "-"}},
+					{Source: "company", Target: "isAcme", Transform: "fuzzyMatch", Params: map[string]interface{}{"reference": "Acme Corp", "threshold": 0.85}},
+					{Source: "tags", Target: "uniqueTags", Transform: "dedupList", Params: map[string]interface{}{"separator": ";", "trim": true, "caseInsensitive": true}},
+					{Source: "ids", Target: "sortedIds", Transform: "sortList", Params: map[string]interface{}{"numeric": true}},
+					{Source: "tags", Target: "tag_count", Transform: "listCount", Params: map[string]interface{}{"separator": ";", "ignoreEmpty": true}},
+					{Source: "path", Target: "lastSegment", Transform: "nthField", Params: map[string]interface{}{"separator": "/", "index": -1, "default": ""}},
+					{Source: "serial", Target: "orderDate", Transform: "excelSerialToDate", Params: map[string]interface{}{"outputFormat": "2006-01-02"}},
+					{Source: "qty", Target: "qtyInt", Transform: "castType", Params: map[string]interface{}{"type": "int"}},
+					{Source: "shipped", Target: "shippedDate", Transform: "castType", Params: map[string]interface{}{"type": "date", "inputFormat": "01/02/2006", "outputFormat": "2006-01-02"}},
+					{Source: "scores", Target: "total", Transform: "mustSumDelimited", Params: map[string]interface{}{"separator": ";"}},
+					{Source: "region", Target: "region_name", Transform: "configLookup", Params: map[string]interface{}{"file": lookupFile, "default": "Unknown"}},
+					{Source: "customer_id", Target: "partition", Transform: "shard", Params: map[string]interface{}{"buckets": 16}},
+					{Source: "event_time", Target: "bucket", Transform: "truncateTime", Params: map[string]interface{}{"interval": "15m", "timezone": "UTC"}},
+					{Source: "payload", Target: "enriched", Transform: "exec", Params: map[string]interface{}{"command": "./enrich.sh", "args": []interface{}{"--mode", "fast"}, "timeout": "5s"}},
+					{Source: "landing_url", Target: "query", Transform: "parseQueryString", Params: map[string]interface{}{"repeated": "join", "separator": ";"}},
+					{Source: "page_url", Target: "page_host", Transform: "urlParse", Params: map[string]interface{}{"component": "host"}},
+					{Source: "phone", Target: "phone_fmt", Transform: "applyMask", Params: map[string]interface{}{"mask": "(###) ###-####", "extra": "append"}},
+					{Source: "amount", Target: "amount_delta", Transform: "lagField", Params: map[string]interface{}{"field": "amount", "partitionBy": "account", "delta": true}},
+					{Source: "event_ts", Target: "event_ts_utc", Transform: "mustNormalizeTimestamp", Params: map[string]interface{}{"precision": "millis"}},
+					{Source: "id", Target: "is_complete", Transform: "completeness", Params: map[string]interface{}{"fields": []interface{}{"id", "email"}}},
+					{Source: "email", Target: "email_token", Transform: "pseudonymize", Params: map[string]interface{}{"key": "${PSEUDO_KEY}", "length": 12}},
+					{Source: "name", Target: "fake_name", Transform: "pseudonymize", Params: map[string]interface{}{"key": "k", "format": "name"}},
+					{Source: "raw", Target: "raw_type", Transform: "detectType"},
+					{Source: "customer", Target: "customer", Transform: "validateInFile", Params: map[string]interface{}{"file": referenceFile, "keyField": "customer_id"}},
+					{Source: "phone", Target: "phone_digits", Transform: "digitsOnly", Params: map[string]interface{}{"keepLeadingPlus": true}},
+					{Source: "code", Target: "code_clean", Transform: "alphaNumericOnly"},
+					{Source: "event_ts", Target: "event_ts", Transform: "validateMonotonic", Params: map[string]interface{}{"field": "event_ts", "keyField": "device_id", "strict": true}},
+					{Source: "status", Target: "is_active", Transform: "bitFlag", Params: map[string]interface{}{"bit": 0}},
+					{Source: "temp_f", Target: "temp_c", Transform: "convertUnit", Params: map[string]interface{}{"from": "F", "to": "c"}},
+					{Source: "barcode", Target: "barcode_ok", Transform: "mustCheckDigit", Params: map[string]interface{}{"scheme": "ean13", "mode": "verify"}},
+					{Source: "event_date", Target: "event_tz_offset", Transform: "timezoneInfo", Params: map[string]interface{}{"timezone": "America/Chicago", "output": "abbreviation"}},
+					{Source: "name", Target: "display_name", Transform: "coalesce", Params: map[string]interface{}{"fields": []interface{}{"nickname", "name"}, "then": "replaceAll", "thenParams": map[string]interface{}{"old": "_", "new": " "}}},
+					{Source: "comment", Target: "comment_clean", Transform: "validatePrintable", Params: map[string]interface{}{"asciiOnly": true}},
+					{Source: "notes", Target: "notes_clean", Transform: "stripControl", Params: map[string]interface{}{"keepWhitespace": true, "asciiOnly": true}},
+					{Source: "body", Target: "word_count", Transform: "wordCount"},
+					{Source: "body", Target: "char_count", Transform: "charCount"},
+					{Source: "key", Target: "reversed_key", Transform: "reverseString"},
+					{Source: "first_name", Target: "greeting", Transform: "template", Params: map[string]interface{}{"template": "{{.first_name}} {{.last_name}}"}},
+					{Source: "metric", Target: "metric_value", Transform: "dynamicField"},
+					{Source: "checksum", Target: "checksum_ok", Transform: "validateHash", Params: map[string]interface{}{"fields": []interface{}{"id", "amount"}, "algorithm": "sha256", "expectedField": "checksum"}},
+					{Source: "address", Target: "address_flat", Transform: "flattenMap", Params: map[string]interface{}{"prefix": "address", "separator": "."}},
+					{Source: "company", Target: "companyDistance", Transform: "editDistance", Params: map[string]interface{}{"field": "legal_name", "caseInsensitive": true}},
+					{Source: "mac", Target: "macNormalized", Transform: "normalizeMAC", Params: map[string]interface{}{"separator": "-", "uppercase": true}},
+					{Source: "mac", Target: "macChecked", Transform: "validateMAC"},
+					{Source: "note", Target: "noteB32", Transform: "base32Encode"},
+					{Source: "noteB32", Target: "noteDecoded", Transform: "mustBase32Decode"},
+					{Source: "note", Target: "noteRot13", Transform: "rot13"},
+					{Source: "tags", Target: "tagsJSON", Transform: "toJSONString"},
+					{Source: "tags", Target: "tagsPretty", Transform: "toJSONString", Params: map[string]interface{}{"indent": "  "}},
+					{Source: "level", Target: "levelLabel", Transform: "severityMap", Params: map[string]interface{}{"codes": map[interface{}]interface{}{3: "ERROR", 4: "WARN"}, "bands": []interface{}{map[string]interface{}{"min": 5, "label": "INFO"}}, "default": "UNKNOWN"}},
+					{Source: "birth_date", Target: "birthDateChecked", Transform: "validateDateRange", Params: map[string]interface{}{"min": "1900-01-01", "max": "today"}},
+					{Source: "id", Target: "paddedId", Transform: "padLeft", Params: map[string]interface{}{"length": 10, "pad": "0"}},
+					{Source: "name", Target: "paddedName", Transform: "padRight", Params: map[string]interface{}{"length": 20}},
+					{Source: "rate", Target: "ratePct", Transform: "normalizePercent", Params: map[string]interface{}{"scale": "whole", "clamp": true}},
+					{Source: "rate", Target: "rateFraction", Transform: "mustNormalizePercent"},
+					{Source: "name", Target: "nameInitials", Transform: "initials", Params: map[string]interface{}{"count": 3, "separator": "."}},
+					{Source: "rank", Target: "rankOrdinal", Transform: "ordinal"},
+					{Source: "amount", Target: "amountWords", Transform: "numberToWords", Params: map[string]interface{}{"currency": true}},
+					{Source: "payload", Target: "payloadParsed", Transform: "jsonParse"},
+					{Source: "payload", Target: "payloadStrict", Transform: "mustJsonParse"},
+					{Source: "active", Target: "activeLabel", Transform: "formatBool", Params: map[string]interface{}{"trueValue": "Yes", "falseValue": "No"}},
+					{Source: "attrs", Target: "attrsJSON", Transform: "jsonStringify", Params: map[string]interface{}{"indent": "  "}},
+					{Source: "code", Target: "codeChecked", Transform: "validateCharset", Params: map[string]interface{}{"charset": "A-Z0-9_"}},
+					{Source: "category", Target: "categoryGated", Transform: "applyIf", Params: map[string]interface{}{"condition": "type == 'X'", "then": "padLeft", "thenParams": map[string]interface{}{"length": 5, "pad": "0"}}},
+					{Source: "amount", Target: "amountEU", Transform: "numberFormat", Params: map[string]interface{}{"decimals": 2, "thousandsSep": ".", "decimalSep": ","}},
+					{Target: "batchLabel", Transform: "constant", Params: map[string]interface{}{"value": "batch-${BATCH_ID}"}},
+					{Target: "runEnv", Transform: "env", Params: map[string]interface{}{"name": "DEPLOY_ENV", "default": "dev"}},
+					{Source: "shift_date", Target: "shiftIsWeekend", Transform: "isWeekend", Params: map[string]interface{}{"timezone": "America/New_York"}},
+					{Source: "shift_date", Target: "shiftDay", Transform: "weekdayName", Params: map[string]interface{}{"locale": "fr-CA", "inputFormat": "2006-01-02"}},
+					{Source: "customer_id", Target: "customerKey", Transform: "ensurePrefix", Params: map[string]interface{}{"value": "ID-", "ignoreCase": true}},
+					{Source: "file_name", Target: "fileBase", Transform: "stripSuffix", Params: map[string]interface{}{"value": ".csv"}},
+					{Source: "price", Target: "priceEU", Transform: "toFloat", Params: map[string]interface{}{"thousandsSep": ".", "decimalSep": ","}},
+					{Source: "quantity", Target: "quantityChecked", Transform: "validateInteger"},
 				},
 				FIPSMode: false,
 			},
@@ -523,6 +736,69 @@ func TestValidateConfig_InvalidCases(t *testing.T) {
 			},
 			expectedErrStrings: []string{"Config.Source.Delimiter: '\",,\"' must be a single character"},
 		},
+		{
+			name: "Negative source row options",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv", SkipHeaderRows: -1, SkipFooterRows: -2, MaxRows: -3}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Source.SkipHeaderRows: cannot be negative", "Config.Source.SkipFooterRows: cannot be negative", "Config.Source.MaxRows: cannot be negative"},
+		},
+		{
+			name: "Invalid source header map",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv", HeaderMap: map[string]string{"Cust ID": "id", "Customer": "id", "Notes": " "}}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Source.HeaderMap[Customer]: canonical name 'id' is already used by 'Cust ID'", "Config.Source.HeaderMap[Notes]: canonical name cannot be empty"},
+		},
+		{
+			name: "Invalid CSV multi-char delimiter",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv", MultiCharDelimiter: "|\n|"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Source.MultiCharDelimiter: cannot contain line breaks"},
+		},
+		{
+			name: "Split by without placeholder",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv"}, Destination: DestinationConfig{Type: "json", File: "out.json", SplitBy: "region"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Destination.File: must contain the {key} placeholder when split_by is set"},
+		},
+		{
+			name: "Split by with postgres",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv"}, Destination: DestinationConfig{Type: "postgres", TargetTable: "t", SplitBy: "region"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Destination.SplitBy: is not supported for destination type 'postgres'"},
+		},
+		{
+			name: "Invalid XML attribute fields",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv"}, Destination: DestinationConfig{Type: "xml", File: "out.xml", AttributeFields: []string{"id", "1bad", "id"}}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Destination.AttributeFields[1]:", "Config.Destination.AttributeFields[2]: duplicate field 'id'"},
+		},
+		{
+			name: "Invalid XML attribute filter",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "xml", File: "in.xml", XMLAttributeFilter: &XMLAttributeFilterConfig{Value: "A"}}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Source.XMLAttributeFilter.Name: is required"},
+		},
+		{
+			name: "Invalid XML attribute filter name",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "xml", File: "in.xml", XMLAttributeFilter: &XMLAttributeFilterConfig{Name: "1type", Value: "A"}}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Source.XMLAttributeFilter.Name:"},
+		},
+		{
+			name: "Invalid CSV duplicate header mode",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv", DuplicateHeader: "rename"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Source.DuplicateHeader: invalid mode 'rename'"},
+		},
 		{
 			name: "Invalid XLSX sheet index",
 			cfg: &ETLConfig{
@@ -537,6 +813,13 @@ func TestValidateConfig_InvalidCases(t *testing.T) {
 			},
 			expectedErrStrings: []string{"Config.Source.SheetName: 'My*Sheet' contains invalid characters"},
 		},
+		{
+			name: "Invalid XLSX number format",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "xlsx", File: "out.xlsx", NumberFormat: map[string]string{"amount": "$#,##0.00", "shipped": " "}}, Mappings: []MappingRule{{Source: "a", Target: "b"}},
+			},
+			expectedErrStrings: []string{"Config.Destination.NumberFormat[shipped]: format string cannot be empty"},
+		},
 		{
 			name: "Invalid XLSX sheet name length",
 			cfg: &ETLConfig{
@@ -671,6 +954,370 @@ func TestValidateConfig_InvalidCases(t *testing.T) {
 			},
 			expectedErrStrings: []string{"Config.Mappings[0].Params: hash algorithm 'md5' is not allowed in FIPS mode"},
 		},
+		{
+			name: "Mapping toBoolCustom empty falsy",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "mustToBoolCustom", Params: map[string]interface{}{"truthy": []interface{}{"Y"}, "falsy": []interface{}{}}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: parameter 'falsy' cannot be an empty slice/array for transform 'musttoboolcustom'"},
+		},
+		{
+			name: "Mapping toBoolCustom missing truthy",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "toBoolCustom", Params: map[string]interface{}{"falsy": []interface{}{"N"}, "caseInsensitive": "yes"}}},
+			},
+			expectedErrStrings: []string{"missing required parameter 'truthy' for transform 'toboolcustom'", "parameter 'caseInsensitive' must be a boolean"},
+		},
+		{
+			name: "Mapping parsePercent invalid scale",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "parsePercent", Params: map[string]interface{}{"scale": "basis"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid scale 'basis' for 'parsepercent'"},
+		},
+		{
+			name: "Mapping parseCurrency same separators",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "parseCurrency", Params: map[string]interface{}{"decimalSeparator": ",", "output": "code"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: 'decimalSeparator' and 'groupSeparator' cannot both be ',' for 'parsecurrency'", "invalid output 'code' for 'parsecurrency'"},
+		},
+		{
+			name: "Mapping countryCode invalid direction",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "countryCode", Params: map[string]interface{}{"direction": "toNumeric"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid direction 'toNumeric' for 'countrycode'"},
+		},
+		{
+			name: "Mapping countryCode missing direction",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "countryCode"}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: missing required parameter 'direction' for transform 'countrycode'"},
+		},
+		{
+			name: "Mapping regexRedact invalid shorthand pattern",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "regexRedact:("}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid regex pattern '(' (from shorthand) for 'regexredact'"},
+		},
+		{
+			name: "Mapping regexRedact missing pattern",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "regexRedact", Params: map[string]interface{}{"replacement": 5}}},
+			},
+			expectedErrStrings: []string{"missing required parameter 'pattern' for transform 'regexredact' (and not provided via shorthand)", "parameter 'replacement' must be a string"},
+		},
+		{
+			name: "Mapping fuzzyMatch threshold out of range",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "fuzzyMatch", Params: map[string]interface{}{"reference": "x", "threshold": 1.2}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: 'threshold' (1.2) must be between 0 and 1 for 'fuzzymatch'"},
+		},
+		{
+			name: "Mapping nthField missing index",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "nthField", Params: map[string]interface{}{"separator": "|"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: missing required parameter 'index' for transform 'nthfield'"},
+		},
+		{
+			name: "Mapping castType invalid type",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "castType", Params: map[string]interface{}{"type": "decimal"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid type 'decimal' for 'casttype', must be one of [int float bool string date]"},
+		},
+		{
+			name: "configLookup missing file",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "configLookup", Params: map[string]interface{}{"file": "/nonexistent/lookup.yaml"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: lookup file '/nonexistent/lookup.yaml' for 'configlookup' is not accessible"},
+		},
+		{
+			name: "configLookup without file",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "configLookup"}},
+			},
+			expectedErrStrings: []string{"missing required parameter 'file' for transform 'configlookup'"},
+		},
+		{
+			name: "shard non-positive buckets",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "shard", Params: map[string]interface{}{"buckets": 0}}},
+			},
+			expectedErrStrings: []string{"'buckets' (0) must be a positive integer for 'shard'"},
+		},
+		{
+			name: "truncateTime invalid interval",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "truncateTime", Params: map[string]interface{}{"interval": "-5m", "timezone": "Mars/Base"}}},
+			},
+			expectedErrStrings: []string{"'interval' '-5m' must be a positive duration", "invalid timezone 'Mars/Base' for 'truncatetime'"},
+		},
+		{
+			name: "exec invalid params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "exec", Params: map[string]interface{}{"command": "cat", "args": []interface{}{"ok", 1}, "timeout": "never"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params.args[1]: item must be a string", "'timeout' 'never' must be a positive duration for 'exec'"},
+		},
+		{
+			name: "parseQueryString invalid repeated mode",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "parseQueryString", Params: map[string]interface{}{"repeated": "last"}}},
+			},
+			expectedErrStrings: []string{"invalid repeated mode 'last' for 'parsequerystring'"},
+		},
+		{
+			name: "urlParse invalid component",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "urlParse", Params: map[string]interface{}{"component": "domain"}}},
+			},
+			expectedErrStrings: []string{"invalid component 'domain' for 'urlparse'"},
+		},
+		{
+			name: "applyMask empty mask",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "applyMask", Params: map[string]interface{}{"mask": "", "extra": "keep"}}},
+			},
+			expectedErrStrings: []string{"parameter 'mask' cannot be an empty string for transform 'applymask'", "invalid extra mode 'keep' for 'applymask'"},
+		},
+		{
+			name: "lagField missing field",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "lagField", Params: map[string]interface{}{"delta": "yes"}}},
+			},
+			expectedErrStrings: []string{"missing required parameter 'field' for transform 'lagfield'", "parameter 'delta' must be a boolean for transform 'lagfield'"},
+		},
+		{
+			name: "normalizeTimestamp invalid precision",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "normalizeTimestamp", Params: map[string]interface{}{"precision": "hours"}}},
+			},
+			expectedErrStrings: []string{"invalid precision 'hours' for 'normalizetimestamp'"},
+		},
+		{
+			name: "completeness empty fields",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "completeness", Params: map[string]interface{}{"fields": []interface{}{}}}},
+			},
+			expectedErrStrings: []string{"parameter 'fields' cannot be an empty slice/array for transform 'completeness'"},
+		},
+		{
+			name: "pseudonymize bad format and length",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "pseudonymize", Params: map[string]interface{}{"key": "k", "format": "uuid", "length": 0}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: invalid 'format' 'uuid' for 'pseudonymize', must be one of [token name]", "Mappings[0].Params: 'length' (0) must be between 1 and 64 for 'pseudonymize'"},
+		},
+		{
+			name: "validateInFile missing file and keyField",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "validateInFile", Params: map[string]interface{}{"file": "non-existent-ref.csv"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: missing required parameter 'keyField' for transform 'validateinfile'", "Mappings[0].Params: reference file 'non-existent-ref.csv' for 'validateinfile' is not accessible"},
+		},
+		{
+			name: "digitsOnly keepLeadingPlus not bool",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "digitsOnly", Params: map[string]interface{}{"keepLeadingPlus": "yes"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: parameter 'keepLeadingPlus' must be a boolean"},
+		},
+		{
+			name: "validateMonotonic missing keyField",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "validateMonotonic", Params: map[string]interface{}{"field": "a", "strict": "yes"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: missing required parameter 'keyField' for transform 'validatemonotonic'", "parameter 'strict' must be a boolean for transform 'validatemonotonic'"},
+		},
+		{
+			name: "bitFlag negative bit",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "bitFlag", Params: map[string]interface{}{"bit": -1}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: 'bit' (-1) must be between 0 and 63 for 'bitflag'"},
+		},
+		{
+			name: "convertUnit unsupported pair",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "convertUnit", Params: map[string]interface{}{"from": "kg", "to": "km"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: unsupported unit conversion from 'kg' to 'km' for 'convertunit'"},
+		},
+		{
+			name: "checkDigit invalid scheme and mode",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "checkDigit", Params: map[string]interface{}{"scheme": "upc", "mode": "fix"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: invalid 'scheme' 'upc' for 'checkdigit'", "Mappings[0].Params: invalid 'mode' 'fix' for 'checkdigit'"},
+		},
+		{
+			name: "timezoneInfo invalid zone",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "timezoneInfo", Params: map[string]interface{}{"timezone": "Mars/Olympus", "output": "name"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: invalid timezone 'Mars/Olympus' for 'timezoneinfo'", "Mappings[0].Params: invalid 'output' 'name' for 'timezoneinfo'"},
+		},
+		{
+			name: "coalesce invalid then",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "coalesce", Params: map[string]interface{}{"fields": []interface{}{"a"}, "then": "upperCase"}}, {Source: "a", Target: "c", Transform: "coalesce", Params: map[string]interface{}{"fields": []interface{}{"a"}, "then": "replaceAll", "thenParams": map[string]interface{}{"old": "x"}}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params.then: unknown base transformation function 'uppercase'", "Mappings[1].Params.then.Params: missing required parameter 'new' for transform 'replaceall'"},
+		},
+		{
+			name: "validatePrintable bad asciiOnly",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "validatePrintable", Params: map[string]interface{}{"asciiOnly": "yes"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: parameter 'asciiOnly' must be a boolean for transform 'validateprintable'"},
+		},
+		{
+			name: "template syntax error",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "template", Params: map[string]interface{}{"template": "{{.a"}}, {Source: "a", Target: "c", Transform: "template"}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params.template: invalid template: template: template:1: unclosed action", "Mappings[1].Params: missing required parameter 'template'"},
+		},
+		{
+			name: "validateHash invalid params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "validateHash", Params: map[string]interface{}{"fields": []interface{}{"a"}, "algorithm": "crc32"}}},
+			},
+			expectedErrStrings: []string{"Mappings[0].Params: missing required parameter 'expectedField' for transform 'validatehash'", "Mappings[0].Params: unknown hash algorithm 'crc32'"},
+		},
+		{
+			name: "Mapping editDistance missing field",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "editDistance"}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: missing required parameter 'field' for transform 'editdistance'"},
+		},
+		{
+			name: "Mapping normalizeMAC invalid separator",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "normalizeMAC", Params: map[string]interface{}{"separator": "/"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid separator '/' for 'normalizemac'"},
+		},
+		{
+			name: "Mapping severityMap invalid config",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "severityMap"}, {Source: "a", Target: "c", Transform: "severityMap", Params: map[string]interface{}{"codes": "3=ERROR", "bands": []interface{}{map[string]interface{}{"min": 5, "max": 2}, "x"}}}, {Source: "a", Target: "d", Transform: "severityMap", Params: map[string]interface{}{"bands": []interface{}{map[string]interface{}{"label": "L"}}, "reverse": true}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: at least one of 'codes' or 'bands' is required for transform 'severitymap'", "Config.Mappings[1].Params: 'codes' must be a map of code to label for 'severitymap'", "Config.Mappings[1].Params.bands[0]: missing required key 'label'", "Config.Mappings[1].Params.bands[0]: 'min' (5) must be less than 'max' (2)", "Config.Mappings[1].Params.bands[1]: must be a map", "Config.Mappings[2].Params: 'reverse' requires 'codes' for 'severitymap'"},
+		},
+		{
+			name: "Mapping validateDateRange invalid bounds",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "validateDateRange"}, {Source: "a", Target: "c", Transform: "validateDateRange", Params: map[string]interface{}{"min": "2020-01-01", "max": "1900-01-01"}}, {Source: "a", Target: "d", Transform: "validateDateRange", Params: map[string]interface{}{"max": "soon"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: requires at least 'min' or 'max' for 'validatedaterange'", "Config.Mappings[1].Params: 'min' value (2020-01-01) cannot be later than 'max' value (1900-01-01)", "Config.Mappings[2].Params: 'max' value 'soon' is not a valid date for 'validatedaterange'"},
+		},
+		{
+			name: "Mapping padLeft/padRight invalid params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "padLeft"}, {Source: "a", Target: "c", Transform: "padRight", Params: map[string]interface{}{"length": -1, "pad": "00"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: missing required parameter 'length' for transform 'padleft'", "Config.Mappings[1].Params: 'length' (-1) cannot be negative for 'padright'", "Config.Mappings[1].Params: 'pad' must be a single character for 'padright', got '00'"},
+		},
+		{
+			name: "Mapping normalizePercent invalid params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "normalizePercent", Params: map[string]interface{}{"scale": "basis", "clamp": "yes"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid scale 'basis' for 'normalizepercent'", "Config.Mappings[0].Params: parameter 'clamp' must be a boolean for transform 'normalizepercent'"},
+		},
+		{
+			name: "Mapping initials invalid count",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "initials", Params: map[string]interface{}{"count": 0}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: 'count' (0) must be a positive integer for 'initials'"},
+		},
+		{
+			name: "Mapping numberToWords invalid currency",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "numberToWords", Params: map[string]interface{}{"currency": "yes"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: parameter 'currency' must be a boolean for transform 'numbertowords'"},
+		},
+		{
+			name: "Mapping formatBool missing falseValue",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "formatBool", Params: map[string]interface{}{"trueValue": "Y"}}},
+			},
+			expectedErrStrings: []string{"falseValue"},
+		},
+		{
+			name: "Mapping jsonStringify invalid indent",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "jsonStringify", Params: map[string]interface{}{"indent": 2}}},
+			},
+			expectedErrStrings: []string{"'indent' must be a string"},
+		},
+		{
+			name: "Mapping validateCharset reversed range",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "validateCharset", Params: map[string]interface{}{"charset": "z-a0-9"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: invalid range 'z-a' in 'charset' for 'validatecharset'"},
+		},
+		{
+			name: "Mapping applyIf invalid condition and then",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "applyIf", Params: map[string]interface{}{"condition": "type ==", "then": "noSuchTransform"}}, {Source: "a", Target: "c", Transform: "applyIf", Params: map[string]interface{}{"then": "padLeft", "thenParams": map[string]interface{}{"length": -1}}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params.condition: invalid condition syntax", "Config.Mappings[0].Params.then: unknown base transformation function 'nosuchtransform'", "Config.Mappings[1].Params: missing required parameter 'condition'", "Config.Mappings[1].Params.then.Params: 'length'"},
+		},
+		{
+			name: "Mapping numberFormat invalid params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "numberFormat", Params: map[string]interface{}{"decimals": -1, "decimalSep": ","}}, {Source: "a", Target: "c", Transform: "numberFormat", Params: map[string]interface{}{"decimals": "two", "thousandsSep": 1}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: 'decimals' (-1) must be between 0 and 20 for 'numberformat'", "Config.Mappings[0].Params: 'thousandsSep' and 'decimalSep' must differ for 'numberformat' (both are ',')", "Config.Mappings[1].Params: parameter 'decimals' must be a valid integer for transform 'numberformat'", "Config.Mappings[1].Params: parameter 'thousandsSep' must be a string for transform 'numberformat'"},
+		},
+		{
+			name: "Mapping constant and env invalid",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Target: "b", Transform: "constant"}, {Source: "a", Target: "c", Transform: "env", Params: map[string]interface{}{"name": "A=B"}}, {Target: "d", Transform: "toUpperCase"}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Params: missing required parameter 'value'", "Config.Mappings[1].Params: 'name' must be a valid environment variable name for 'env', got 'A=B'", "Config.Mappings[2].Source: is required"},
+		},
+		{
+			name: "Invalid weekday params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "d", Target: "w", Transform: "isWeekend", Params: map[string]interface{}{"timezone": "Mars/Olympus"}}, {Source: "d", Target: "n", Transform: "weekdayName", Params: map[string]interface{}{"locale": "klingon", "inputFormat": 5}}},
+			},
+			expectedErrStrings: []string{"invalid timezone 'Mars/Olympus' for 'isweekend'", "unsupported 'locale' 'klingon' for 'weekdayname'", "parameter 'inputFormat' must be a string for transform 'weekdayname'"},
+		},
+		{
+			name: "Invalid affix params",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b", Transform: "ensurePrefix"}, {Source: "a", Target: "c", Transform: "stripSuffix", Params: map[string]interface{}{"value": "", "ignoreCase": "yes"}}},
+			},
+			expectedErrStrings: []string{"missing required parameter 'value'", "parameter 'ignoreCase' must be a boolean for transform 'stripsuffix'"},
+		},
+		{
+			name: "tofloat same separators",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a", Transform: "toFloat", Params: map[string]interface{}{"thousandsSep": ",", "decimalSep": ","}}},
+			},
+			expectedErrStrings: []string{"'thousandsSep' and 'decimalSep' must differ for 'tofloat' (both are ',')"},
+		},
+		{
+			name: "toint separator types",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a", Transform: "mustToInt", Params: map[string]interface{}{"thousandsSep": 1, "decimalSep": "-"}}},
+			},
+			expectedErrStrings: []string{"parameter 'thousandsSep' must be a string for transform 'musttoint'", "'decimalSep' ('-') must not contain digits or signs for 'musttoint'"},
+		},
 		{
 			name: "Dedup missing keys",
 			cfg: &ETLConfig{
@@ -706,6 +1353,213 @@ func TestValidateConfig_InvalidCases(t *testing.T) {
 			},
 			expectedErrStrings: []string{"Config.ErrorHandling.ErrorFile: path '/some/path/' appears to be a directory"},
 		},
+		{
+			name: "Output schema empty fields",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}}, OutputSchema: &OutputSchemaConfig{},
+			},
+			expectedErrStrings: []string{"Config.OutputSchema.Fields: at least one field is required"},
+		},
+		{
+			name: "Output schema invalid field names",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}}, OutputSchema: &OutputSchemaConfig{Fields: []OutputFieldConfig{{Name: "b"}, {Name: " "}, {Name: "b", Default: 0}}},
+			},
+			expectedErrStrings: []string{"Config.OutputSchema.Fields[1].Name: is required", "Config.OutputSchema.Fields[2].Name: duplicate field 'b'"},
+		},
+		{
+			name: "Invalid on_empty_input",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "b"}}, ErrorHandling: &ErrorHandlingConfig{Mode: "halt", OnEmptyInput: "ignore"},
+			},
+			expectedErrStrings: []string{"Config.ErrorHandling.OnEmptyInput: invalid value 'ignore', must be one of [ok warn error]"},
+		},
+		{
+			name: "Dedup invalid tie-breaker",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, Dedup: &DedupConfig{Keys: []string{"a"}, Strategy: "min", StrategyField: "a", TieBreaker: "a", TieBreakerOrder: "sideways"},
+			},
+			expectedErrStrings: []string{"Config.Dedup.TieBreaker: field 'a' must differ from StrategyField", "Config.Dedup.TieBreakerOrder: invalid order 'sideways'"},
+		},
+		{
+			name: "Dedup invalid strategy field type",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}, {Source: "ts", Target: "ts"}}, Dedup: &DedupConfig{Keys: []string{"a"}, Strategy: "max", StrategyField: "ts", StrategyFieldType: "datetime"},
+			},
+			expectedErrStrings: []string{"Config.Dedup.StrategyFieldType: invalid type 'datetime', must be one of [auto timestamp]"},
+		},
+		{
+			name: "RunMetadata without fields",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, RunMetadata: &RunMetadataConfig{TimeFormat: "2006"},
+			},
+			expectedErrStrings: []string{"Config.RunMetadata: at least one of run_id, run_time, or config_name must name a target field"},
+		},
+		{
+			name: "RunMetadata duplicate field names",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, RunMetadata: &RunMetadataConfig{RunID: "run", RunTime: "run"},
+			},
+			expectedErrStrings: []string{"Config.RunMetadata.RunTime: field name 'run' is already used by RunID"},
+		},
+		{
+			name: "RunningTotal missing fields",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, RunningTotal: &RunningTotalConfig{PartitionBy: []string{""}},
+			},
+			expectedErrStrings: []string{"Config.RunningTotal.Field: is required", "Config.RunningTotal.Target: is required", "Config.RunningTotal.PartitionBy[0]: key cannot be empty"},
+		},
+		{
+			name: "RangeExpand missing fields",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, RangeExpand: &RangeExpandConfig{Separator: " ", MaxValues: -1},
+			},
+			expectedErrStrings: []string{"Config.RangeExpand.SourceField: is required", "Config.RangeExpand.TargetField: is required", "Config.RangeExpand.Separator: cannot be whitespace only", "Config.RangeExpand.MaxValues: must be a positive integer, got -1"},
+		},
+		{
+			name: "RangeExpand target conflicts with mapping",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}, {Source: "b", Target: "b"}}, RangeExpand: &RangeExpandConfig{SourceField: "a", TargetField: "b"},
+			},
+			expectedErrStrings: []string{"Config.RangeExpand.TargetField: 'b' conflicts with a target field defined in mappings"},
+		},
+		{
+			name: "SchemaValidation missing file and invalid stage",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, SchemaValidation: &SchemaValidationConfig{Stage: "load"},
+			},
+			expectedErrStrings: []string{"Config.SchemaValidation.Stage: invalid stage 'load', must be one of [input output]", "Config.SchemaValidation.File: is required"},
+		},
+		{
+			name: "SchemaValidation unloadable schema",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, SchemaValidation: &SchemaValidationConfig{File: "non-existent.schema.json"},
+			},
+			expectedErrStrings: []string{"Config.SchemaValidation.File: failed to load JSON Schema 'non-existent.schema.json'"},
+		},
+		{
+			name: "NestFields target conflict",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "address"}, {Source: "b", Target: "address.city"}, {Source: "c", Target: "geo..lat"}}, NestFields: &NestFieldsConfig{},
+			},
+			expectedErrStrings: []string{"Config.NestFields: mapping target 'address' conflicts with nested target 'address.city'", "Config.NestFields: mapping target 'geo..lat' has an empty name segment"},
+		},
+		{
+			name: "Destination negative FloatPrecision",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "csv", File: "out.csv", FloatPrecision: func() *int { p := -1; return &p }()}, Mappings: []MappingRule{{Source: "a", Target: "a"}},
+			},
+			expectedErrStrings: []string{"Config.Destination.FloatPrecision: cannot be negative"},
+		},
+		{
+			name: "Invalid compression",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "csv", File: "in.csv.gz", Compression: "zip"}, Destination: DestinationConfig{Type: "json", File: "out.json.gz", Compression: "bzip2"},
+			},
+			expectedErrStrings: []string{"Source.Compression: invalid compression 'zip'", "Destination.Compression: invalid compression 'bzip2'"},
+		},
+		{
+			name: "Invalid JSON records path",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json", RecordsPath: "$.data[*]"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}},
+			},
+			expectedErrStrings: []string{"Config.Source.RecordsPath: '$.data[*]' must be a plain dotted path of object keys"},
+		},
+		{
+			name: "JSON records path with lines format",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.jsonl", Format: "lines", RecordsPath: "data"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}},
+			},
+			expectedErrStrings: []string{"Config.Source.RecordsPath: cannot be used with format 'lines'"},
+		},
+		{
+			name: "HTTP source missing URL",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "http"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}},
+			},
+			expectedErrStrings: []string{"Config.Source.URL: is required for source type 'http'"},
+		},
+		{
+			name: "HTTP source URL and File",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "http", URL: "https://a.example.com", File: "https://b.example.com"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}},
+			},
+			expectedErrStrings: []string{"Config.Source.URL: cannot be combined with File"},
+		},
+		{
+			name: "HTTP source invalid options",
+			cfg: &ETLConfig{
+				Source: SourceConfig{
+					Type: "http", File: "ftp://files.example.com/export.json", Method: "DELETE", Timeout: "-5s", RecordsPath: "data..items",
+					Headers: map[string]string{"Bad Name": "x", "X-Inject": "a\r\nHost: evil"},
+				},
+				Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}},
+			},
+			expectedErrStrings: []string{
+				"Config.Source.File: URL 'ftp://files.example.com/export.json' must use the http or https scheme",
+				"Config.Source.Method: invalid HTTP method 'DELETE'",
+				"Config.Source.Headers: header name 'Bad Name' cannot contain whitespace or ':'",
+				"Config.Source.Headers: value of header 'X-Inject' cannot contain line breaks",
+				"Config.Source.Timeout: invalid timeout '-5s'",
+				"Config.Source.RecordsPath: 'data..items' cannot have empty path segments",
+			},
+		},
+		{
+			name: "HTTP source URL without host",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "http", URL: "https:///items"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}},
+			},
+			expectedErrStrings: []string{"Config.Source.URL: URL 'https:///items' is missing a host"},
+		},
+		{
+			name: "Invalid JSON format",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.jsonl", Format: "ndjson"}, Destination: DestinationConfig{Type: "json", File: "out.json", Format: "stream"},
+			},
+			expectedErrStrings: []string{"Source.Format: invalid JSON format 'ndjson'", "Destination.Format: invalid JSON format 'stream'"},
+		},
+		{
+			name: "Dedup count field",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, Dedup: &DedupConfig{Keys: []string{"a"}, Strategy: "count"},
+			},
+			expectedErrStrings: []string{"Config.Dedup.CountField: is required when strategy is 'count'"},
+		},
+		{
+			name: "Dedup count field misuse",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, Dedup: &DedupConfig{Keys: []string{"a"}, Strategy: "last", CountField: "n"},
+			},
+			expectedErrStrings: []string{"Config.Dedup.CountField: is only allowed when strategy is 'count'"},
+		},
+		{
+			name: "Dedup count field is key",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "a", Target: "a"}}, Dedup: &DedupConfig{Keys: []string{"a"}, Strategy: "count", CountField: "a"},
+			},
+			expectedErrStrings: []string{"Config.Dedup.CountField: field 'a' cannot also be a dedup key"},
+		},
+		{
+			name: "Negative concurrency",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Mappings: []MappingRule{{Source: "id", Target: "id"}}, Concurrency: -2,
+			},
+			expectedErrStrings: []string{"Config.Concurrency: cannot be negative (got -2)"},
+		},
+		{
+			name: "Concurrency with stateful transforms",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Concurrency: 4, Mappings: []MappingRule{{Source: "amount", Target: "prev", Transform: "lagField", Params: map[string]interface{}{"field": "amount"}}, {Source: "seq", Target: "seq", Transform: "coalesce", Params: map[string]interface{}{"fields": []interface{}{"seq"}, "then": "validateMonotonic"}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Transform: 'lagfield' depends on record order and requires concurrency 1 (got 4)", "Config.Mappings[1].Transform: 'validatemonotonic' depends on record order"},
+		},
+		{
+			name: "Concurrency with nested stateful then",
+			cfg: &ETLConfig{
+				Source: SourceConfig{Type: "json", File: "in.json"}, Destination: DestinationConfig{Type: "json", File: "out.json"}, Concurrency: 4, Mappings: []MappingRule{{Source: "amount", Target: "prev", Transform: "applyIf", Params: map[string]interface{}{"condition": "amount > 0", "then": "coalesce", "thenParams": map[string]interface{}{"fields": []interface{}{"amount"}, "then": "lagField", "thenParams": map[string]interface{}{"field": "amount"}}}}},
+			},
+			expectedErrStrings: []string{"Config.Mappings[0].Transform: 'lagfield' depends on record order and requires concurrency 1 (got 4)"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -749,6 +1603,29 @@ func TestIsValidEnumValue(t *testing.T) {
 	}
 }
 
+// TestValidateConfig_StructuredErrors verifies ValidateConfig returns a *ValidationError whose issues carry
+// each error's path and message.
+func TestValidateConfig_StructuredErrors(t *testing.T) {
+	cfg := &ETLConfig{
+		Source:      SourceConfig{Type: "csv"},
+		Destination: DestinationConfig{Type: "json", File: "out.json"},
+	}
+	applyDefaults(cfg)
+	err := ValidateConfig(cfg)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("ValidateConfig() error type = %T, want *ValidationError", err)
+	}
+	want := []ValidationIssue{
+		{Path: "Config.Source.File", Message: "is required for source type 'csv'"},
+		{Path: "Config.Mappings", Message: "at least one mapping rule is required"},
+	}
+	if !reflect.DeepEqual(verr.Issues, want) {
+		t.Errorf("Issues = %+v, want %+v", verr.Issues, want)
+	}
+	assertValidationError(t, err, "configuration validation failed:", "- Config.Source.File: is required for source type 'csv'", "- Config.Mappings: at least one mapping rule is required")
+}
+
 // TestValidateSingleRuneString tests the single character validation helper.
 func TestValidateSingleRuneString(t *testing.T) {
 	testCases := []struct {
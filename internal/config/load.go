@@ -4,12 +4,26 @@ import (
 	"fmt"
 	"os"
 
+	"etl-tool/internal/util"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 	"gopkg.in/yaml.v3"
 )
 
 // LoadConfig reads, parses, and validates the YAML configuration file.
 // It applies defaults before returning the validated configuration.
 func LoadConfig(filename string) (*ETLConfig, error) {
+	return loadConfig(filename, ValidateConfig)
+}
+
+// LoadSourceConfig reads and parses the configuration file like LoadConfig, but validates only the
+// logging and source sections. It is used by modes that only read the source (e.g., profiling),
+// so mappings and the destination may be incomplete.
+func LoadSourceConfig(filename string) (*ETLConfig, error) {
+	return loadConfig(filename, ValidateSourceOnly)
+}
+
+// loadConfig reads and parses the YAML configuration file, applies defaults, and checks the result with validate.
+func loadConfig(filename string, validate func(*ETLConfig) error) (*ETLConfig, error) {
 	// Read the configuration file content.
 	fileBytes, err := os.ReadFile(filename)
 	if err != nil {
@@ -25,10 +39,9 @@ func LoadConfig(filename string) (*ETLConfig, error) {
 	}
 
 	// Apply defaults before validation.
-	applyDefaults(&config) // Ensure applyDefaults exists and is called
+	applyDefaults(&config)
 
-	// Perform comprehensive validation of the loaded configuration.
-	if err := ValidateConfig(&config); err != nil { // Ensure ValidateConfig exists and is called
+	if err := validate(&config); err != nil {
 		return nil, err // Return validation errors directly.
 	}
 
@@ -46,13 +59,19 @@ func applyDefaults(cfg *ETLConfig) {
 		// Allow 0 to mean 'no batching', treat negative as unset
 		cfg.Destination.Loader.BatchSize = DefaultLoaderBatchSize
 	}
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
 	// Error handling defaults
 	if cfg.ErrorHandling == nil {
-		cfg.ErrorHandling = &ErrorHandlingConfig{Mode: ErrorHandlingModeHalt}
+		cfg.ErrorHandling = &ErrorHandlingConfig{Mode: ErrorHandlingModeHalt, OnEmptyInput: DefaultOnEmptyInput}
 	} else {
 		if cfg.ErrorHandling.Mode == "" {
 			cfg.ErrorHandling.Mode = ErrorHandlingModeHalt
 		}
+		if cfg.ErrorHandling.OnEmptyInput == "" {
+			cfg.ErrorHandling.OnEmptyInput = DefaultOnEmptyInput
+		}
 		if cfg.ErrorHandling.Mode == ErrorHandlingModeSkip && cfg.ErrorHandling.LogErrors == nil {
 			trueVal := true
 			cfg.ErrorHandling.LogErrors = &trueVal
@@ -62,6 +81,20 @@ func applyDefaults(cfg *ETLConfig) {
 	if cfg.Dedup != nil && cfg.Dedup.Strategy == "" {
 		cfg.Dedup.Strategy = DefaultDedupStrategy
 	}
+	if cfg.Dedup != nil && cfg.Dedup.TieBreaker != "" && cfg.Dedup.TieBreakerOrder == "" {
+		cfg.Dedup.TieBreakerOrder = DefaultTieBreakerOrder
+	}
+	if cfg.RangeExpand != nil {
+		if cfg.RangeExpand.Separator == "" {
+			cfg.RangeExpand.Separator = DefaultRangeSeparator
+		}
+		if cfg.RangeExpand.MaxValues == 0 {
+			cfg.RangeExpand.MaxValues = DefaultRangeMaxValues
+		}
+	}
+	if cfg.SchemaValidation != nil && cfg.SchemaValidation.Stage == "" {
+		cfg.SchemaValidation.Stage = DefaultSchemaStage
+	}
 
 	// Flattening Defaults ---
 	if cfg.Flattening != nil {
@@ -79,6 +112,11 @@ func applyDefaults(cfg *ETLConfig) {
 	applyFormatDefaults(&cfg.Source, &cfg.Destination)
 }
 
+// CompileJSONSchema compiles the JSON Schema file at path after expanding environment variables.
+func CompileJSONSchema(path string) (*jsonschema.Schema, error) {
+	return jsonschema.Compile(util.ExpandEnvUniversal(path))
+}
+
 // applyFormatDefaults sets defaults for format-specific options in source and destination.
 func applyFormatDefaults(src *SourceConfig, dest *DestinationConfig) {
 	// CSV Source Defaults
@@ -86,6 +124,9 @@ func applyFormatDefaults(src *SourceConfig, dest *DestinationConfig) {
 		if src.Delimiter == "" {
 			src.Delimiter = DefaultCSVDelimiter
 		}
+		if src.DuplicateHeader == "" {
+			src.DuplicateHeader = DefaultDuplicateHeader
+		}
 	}
 	// CSV Destination Defaults
 	if dest.Type == DestinationTypeCSV {
@@ -7,7 +7,9 @@ const (
 	SourceTypeXLSX     = "xlsx"
 	SourceTypeXML      = "xml"
 	SourceTypeYAML     = "yaml"
+	SourceTypeTOML     = "toml"
 	SourceTypePostgres = "postgres"
+	SourceTypeHTTP     = "http"
 
 	DestinationTypeJSON     = "json"
 	DestinationTypeCSV      = "csv"
@@ -21,10 +23,43 @@ const (
 	ErrorHandlingModeHalt = "halt" // Stop processing on first record error
 	ErrorHandlingModeSkip = "skip" // Skip records with errors and continue
 
+	OnEmptyInputOK    = "ok"    // Treat an empty source as a successful no-op
+	OnEmptyInputWarn  = "warn"  // Log a warning and finish without writing
+	OnEmptyInputError = "error" // Fail the run when the source yields no records
+
 	DedupStrategyFirst = "first" // Keep the first record encountered
 	DedupStrategyLast  = "last"  // Keep the last record encountered
 	DedupStrategyMin   = "min"   // Keep the record with the minimum value in StrategyField
 	DedupStrategyMax   = "max"   // Keep the record with the maximum value in StrategyField
+	DedupStrategyCount = "count" // Keep the first record and store the number of duplicates in CountField
+
+	TieBreakerOrderAsc  = "asc"  // On a strategy tie, keep the record with the smaller TieBreaker value
+	TieBreakerOrderDesc = "desc" // On a strategy tie, keep the record with the larger TieBreaker value
+
+	StrategyFieldTypeAuto      = "auto"      // Compare StrategyField values as numbers when both parse, else by type
+	StrategyFieldTypeTimestamp = "timestamp" // Parse StrategyField values as dates/times and compare chronologically
+
+	DuplicateHeaderLast   = "last"   // Later columns with a repeated header overwrite earlier ones
+	DuplicateHeaderFirst  = "first"  // The first column with a repeated header wins; later ones are ignored
+	DuplicateHeaderError  = "error"  // A repeated header fails the read
+	DuplicateHeaderSuffix = "suffix" // Repeated headers are renamed name_2, name_3, ...
+
+	SchemaStageInput  = "input"  // Validate records as read (after header mapping and filtering)
+	SchemaStageOutput = "output" // Validate records just before they are written
+
+	SplitByPlaceholder = "{key}" // Replaced by the split_by field value in the destination file name
+
+	JSONFormatArray = "array" // A single JSON array of records (or a single object when reading)
+	JSONFormatLines = "lines" // JSON Lines (NDJSON): one JSON object per line
+
+	CompressionAuto = ""     // Detect gzip from a ".gz" file extension
+	CompressionGzip = "gzip" // Always read/write gzip-compressed files
+	CompressionNone = "none" // Never decompress/compress, even for ".gz" files
+
+	HTTPMethodGet  = "GET"  // Default HTTP source request method
+	HTTPMethodPost = "POST" // HTTP source request method for endpoints that only accept POST (sent without a body)
+
+	DefaultNestFieldsSeparator = "." // Default nestFields separator between nesting levels
 
 	DefaultLogLevel        = "info"
 	DefaultLoaderBatchSize = 0 // 0 or less means no batching for custom SQL
@@ -33,6 +68,19 @@ const (
 	DefaultCSVDelimiter    = ","
 	DefaultSheetName       = "Sheet1" // Default sheet name for XLSX writer
 	DefaultDedupStrategy   = DedupStrategyFirst
+	DefaultTieBreakerOrder = TieBreakerOrderAsc
+	DefaultOnEmptyInput    = OnEmptyInputOK
+	DefaultSchemaStage     = SchemaStageOutput
+	DefaultDuplicateHeader = DuplicateHeaderLast
+	DefaultRangeSeparator  = "-"
+	DefaultRangeMaxValues  = 10000 // Upper bound on records generated from one range
+	DefaultHTTPMethod      = HTTPMethodGet
+	DefaultConcurrency     = 1     // Apply mappings sequentially
+	DefaultHTTPTimeout     = "30s" // Default time limit for an HTTP source request, including reading the body
+
+	// Synthetic fields injected into each input record when Source.IncludeSourceMetadata is true.
+	SourceFileField = "__source_file" // Path of the input file (empty for postgres sources)
+	SourceRowField  = "__source_row"  // 1-based position of the record within the source
 )
 
 // ETLConfig defines the overall structure for the ETL configuration YAML file.
@@ -54,10 +102,30 @@ type ETLConfig struct {
 	// This occurs *after* mapping/transformation and *before* deduplication.
 	Flattening *FlatteningConfig `yaml:"flattening,omitempty"`
 	// --- END ADDED ---
+	// RangeExpand optionally explodes a range string field (e.g., "1-5") into one record per value.
+	// This occurs *after* flattening and *before* running totals and deduplication.
+	RangeExpand *RangeExpandConfig `yaml:"rangeExpand,omitempty"`
+	// RunningTotal optionally adds a cumulative-sum column, computed in input order after flattening and *before* deduplication.
+	RunningTotal *RunningTotalConfig `yaml:"runningTotal,omitempty"`
 	// Dedup specifies optional deduplication settings based on key fields, applied *after* transformations (and flattening).
 	Dedup *DedupConfig `yaml:"dedup,omitempty"`
+	// NestFields optionally groups dotted output fields (e.g., "address.city") into nested maps,
+	// applied at the end of processing (*after* deduplication) so JSON, YAML, and XML output is nested.
+	NestFields *NestFieldsConfig `yaml:"nestFields,omitempty"`
+	// OutputSchema optionally fixes the exact set and order of fields in every output record,
+	// applied *after* mapping, flattening, and deduplication.
+	OutputSchema *OutputSchemaConfig `yaml:"outputSchema,omitempty"`
+	// RunMetadata optionally stamps lineage fields (run ID, run time, config name) onto every output record,
+	// applied just before writing (after OutputSchema).
+	RunMetadata *RunMetadataConfig `yaml:"runMetadata,omitempty"`
+	// SchemaValidation optionally validates every input or output record against a JSON Schema file.
+	// Records that fail are handled according to ErrorHandling (halt, or skip and write to the error file).
+	SchemaValidation *SchemaValidationConfig `yaml:"schemaValidation,omitempty"`
 	// ErrorHandling defines how record-level processing errors (transformations, validations, flattening) are handled.
 	ErrorHandling *ErrorHandlingConfig `yaml:"errorHandling,omitempty"`
+	// Concurrency is the number of workers applying mappings to records in parallel (default 1, sequential).
+	// Output order is preserved. Transforms that depend on record order (lagField, validateMonotonic) require 1.
+	Concurrency int `yaml:"concurrency,omitempty"`
 	// FIPSMode indicates if FIPS compliance restrictions should be enforced (e.g., allowed crypto algorithms).
 	// Can be overridden by the -fips command-line flag.
 	FIPSMode bool `yaml:"fipsMode,omitempty"`
@@ -73,30 +141,83 @@ type LoggingConfig struct {
 // SourceConfig details the input source properties.
 type SourceConfig struct {
 	// Type indicates the format of the input source.
-	// Supported types: "json", "csv", "xlsx", "xml", "yaml", "postgres". Required.
+	// Supported types: "json", "csv", "xlsx", "xml", "yaml", "toml", "postgres", "http". Required.
 	Type string `yaml:"type"`
 	// File specifies the path to the input file for file-based sources (json, csv, xlsx, xml, yaml).
 	// Ignored for "postgres" type. Environment variables are expanded. Required for file types.
+	// For "http", File may hold the request URL instead of URL.
 	File string `yaml:"file,omitempty"`
 	// Query specifies the SQL query for "postgres" input source. Required for "postgres".
 	// Ignored for file-based types.
 	Query string `yaml:"query,omitempty"`
+	// Compression selects how File is decompressed: "gzip" always, "none" never, or empty (default) to detect
+	// gzip from a ".gz" file extension. Ignored for "postgres" and "http".
+	Compression string `yaml:"compression,omitempty"`
+
+	// --- HTTP Source Options ---
+	// URL is the http(s) endpoint to request for "http" sources (alternatively set File). Environment
+	// variables are expanded.
+	URL string `yaml:"url,omitempty"`
+	// Method is the HTTP request method: "GET" (default) or "POST".
+	Method string `yaml:"method,omitempty"`
+	// Headers are sent with the request (e.g., Authorization). Environment variables in values are expanded
+	// when the request is made, so secrets can stay out of the config file.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Timeout is a Go duration (e.g., "10s", "2m") limiting the whole request. Defaults to "30s".
+	Timeout string `yaml:"timeout,omitempty"`
 
 	// --- Format Specific Options ---
+	// JSON Format is "array" (default; a single top-level array or object) or "lines" (JSON Lines/NDJSON:
+	// one object per line, blank lines ignored).
+	Format string `yaml:"format,omitempty"`
+	// JSON and HTTP RecordsPath is a dotted path (e.g., "results" or "data.items") to the records array when it is
+	// nested inside the document or response, like {"meta": {...}, "results": [...]}. An object at the path is read
+	// as a single record. Not supported with JSON Format "lines".
+	RecordsPath string `yaml:"records_path,omitempty"`
 	// CSV Delimiter character (default: ","). Use '\t' for tab.
 	Delimiter string `yaml:"delimiter,omitempty"`
 	// CSV Comment character (e.g., "#"). Lines starting with this char are ignored. Default is disabled.
 	CommentChar string `yaml:"commentChar,omitempty"`
+	// CSV MultiCharDelimiter splits each line on this string (e.g., "||", "~|~") instead of Delimiter.
+	// Lines are split literally: quoting and escaping are NOT supported in this mode.
+	MultiCharDelimiter string `yaml:"multi_char_delimiter,omitempty"`
+	// CSV TrimFields, if true, trims leading and trailing whitespace from every cell on read.
+	TrimFields bool `yaml:"trim_fields,omitempty"`
+	// CSV TreatEmptyAsNull, if true, reads empty cells (after trimming, if enabled) as nil instead of "".
+	TreatEmptyAsNull bool `yaml:"treat_empty_as_null,omitempty"`
+	// CSV DuplicateHeader selects how repeated header names are handled: "last" (default, the last column wins),
+	// "first" (the first column wins), "error" (fail the read), or "suffix" (rename repeats to name_2, name_3, ...).
+	DuplicateHeader string `yaml:"duplicate_header,omitempty"`
 	// XLSX Sheet name to read from. Takes precedence over SheetIndex if both are set.
 	// Defaults to the first/active sheet if neither is specified.
 	SheetName string `yaml:"sheetName,omitempty"`
 	// XLSX Sheet index (0-based) to read from. Used if SheetName is not set.
 	// Defaults to the first/active sheet (index 0) if neither is specified.
 	SheetIndex *int `yaml:"sheetIndex,omitempty"` // Use pointer to distinguish 0 from unset
+	// XLSX TypedCells, if true, returns numeric cells as float64, date-formatted cells as time.Time,
+	// and boolean cells as bool instead of their formatted display strings. Defaults to false.
+	TypedCells bool `yaml:"typed_cells,omitempty"`
 	// XML Tag name of the repeating elements that represent records (e.g., "item", "transaction").
 	// Defaults to "record".
 	XMLRecordTag string `yaml:"xmlRecordTag,omitempty"`
+	// XML XMLAttributeFilter keeps only record elements carrying the given attribute value
+	// (e.g., <record type="A">). Other record elements are skipped before they become records.
+	XMLAttributeFilter *XMLAttributeFilterConfig `yaml:"xmlAttributeFilter,omitempty"`
+	// CSV/XLSX SkipHeaderRows is the number of rows (e.g., banners) to discard before the header row.
+	SkipHeaderRows int `yaml:"skip_header_rows,omitempty"`
+	// CSV/XLSX SkipFooterRows is the number of trailing rows (e.g., totals) to discard after the data.
+	SkipFooterRows int `yaml:"skip_footer_rows,omitempty"`
+	// CSV/XLSX MaxRows caps the number of data rows read (after skipping footer rows). 0 means no limit.
+	MaxRows int `yaml:"max_rows,omitempty"`
 	// YAML specific options could be added here if needed (e.g., document index)
+
+	// IncludeSourceMetadata, if true, adds the synthetic fields "__source_file" and "__source_row"
+	// to every input record so mappings can reference them as a source. Disabled by default
+	// to avoid colliding with real input fields of the same name.
+	IncludeSourceMetadata bool `yaml:"includeSourceMetadata,omitempty"`
+	// HeaderMap renames raw input field names (e.g., CSV headers) to canonical names right after reading,
+	// before filtering and mappings, so rules can reference the clean names. Unmapped fields keep their names.
+	HeaderMap map[string]string `yaml:"header_map,omitempty"`
 }
 
 // DestinationConfig details the output destination properties.
@@ -110,19 +231,53 @@ type DestinationConfig struct {
 	// File specifies the path to the output file for file-based destinations (json, csv, xlsx, xml, yaml).
 	// Required for file-based types. Ignored for "postgres". Environment variables are expanded.
 	File string `yaml:"file,omitempty"`
+	// SplitBy names a record field used to route records into one output file per distinct value
+	// (e.g., one file per region). File must then contain the "{key}" placeholder, which is replaced by the value.
+	// Only applicable for file-based types.
+	SplitBy string `yaml:"split_by,omitempty"`
+	// Atomic, if true, writes the output to a temporary file in the same directory and renames it to File
+	// only after the write completes, so consumers never see a partial file. Only applicable for file-based types.
+	Atomic bool `yaml:"atomic,omitempty"`
+	// Compression selects how File is compressed: "gzip" always, "none" never, or empty (default) to compress
+	// with gzip when File ends in ".gz". Only applicable for file-based types.
+	Compression string `yaml:"compression,omitempty"`
 	// Loader provides specific configuration for PostgreSQL loading (e.g., custom SQL, batching).
 	// Only applicable for "postgres" type.
 	Loader *LoaderConfig `yaml:"loader,omitempty"`
+	// VerifyCount, if true, checks after loading that the number of rows written matches the number
+	// of records sent (COPY row count, or committed rows for custom SQL) and fails the run if they differ.
+	// Only applicable for "postgres" type.
+	VerifyCount bool `yaml:"verify_count,omitempty"`
+	// TruncateToColumnLength, if true, looks up the target table's character_maximum_length per column and
+	// truncates longer string values (with a warning) instead of letting the load fail.
+	// Only applicable for "postgres" type.
+	TruncateToColumnLength bool `yaml:"truncate_to_column_length,omitempty"`
 
 	// --- Format Specific Options ---
+	// JSON Format is "array" (default; an indented JSON array) or "lines" (JSON Lines/NDJSON: one compact
+	// object per line).
+	Format string `yaml:"format,omitempty"`
 	// CSV Delimiter character (default: ","). Use '\t' for tab.
 	Delimiter string `yaml:"delimiter,omitempty"`
+	// CSV/JSON FloatPrecision, if set, writes float values with exactly this many decimals (e.g., 2 renders
+	// 3.14159 as 3.14). Unset keeps Go's shortest representation. Must be non-negative.
+	FloatPrecision *int `yaml:"float_precision,omitempty"` // Use pointer to distinguish 0 from unset
 	// XLSX Sheet name to write to. Defaults to "Sheet1".
 	SheetName string `yaml:"sheetName,omitempty"`
+	// XLSX BoldHeader, if true, renders the header row in bold.
+	BoldHeader bool `yaml:"bold_header,omitempty"`
+	// XLSX FreezeHeader, if true, freezes the header row so it stays visible while scrolling.
+	FreezeHeader bool `yaml:"freeze_header,omitempty"`
+	// XLSX NumberFormat maps output column names to Excel number format codes (e.g., "$#,##0.00", "yyyy-mm-dd")
+	// applied to that column's data cells. Columns not listed are written without a number format.
+	NumberFormat map[string]string `yaml:"number_format,omitempty"`
 	// XML Tag name for the repeating elements representing records. Defaults to "record".
 	XMLRecordTag string `yaml:"xmlRecordTag,omitempty"`
 	// XML Tag name for the root element. Defaults to "records".
 	XMLRootTag string `yaml:"xmlRootTag,omitempty"`
+	// XML AttributeFields lists record fields written as attributes of the record element
+	// (e.g., <record id="1">) instead of child elements. Fields missing from a record are omitted.
+	AttributeFields []string `yaml:"attribute_fields,omitempty"`
 	// YAML specific options could be added here if needed (e.g., indentation)
 }
 
@@ -174,9 +329,103 @@ type DedupConfig struct {
 	// "last": Keeps the last record encountered with the key.
 	// "min": Keeps the record with the minimum value in the 'strategyField'.
 	// "max": Keeps the record with the maximum value in the 'strategyField'.
+	// "count": Keeps the first record encountered and sets 'countField' to the number of records with the key.
 	Strategy string `yaml:"strategy,omitempty"`
 	// StrategyField is the target field name used for comparison when strategy is "min" or "max". Required for those strategies.
 	StrategyField string `yaml:"strategyField,omitempty"`
+	// StrategyFieldType controls how StrategyField values are compared: "auto" (default) or "timestamp", which parses
+	// date strings like dateConvert does so they compare chronologically instead of lexicographically.
+	StrategyFieldType string `yaml:"strategy_field_type,omitempty"`
+	// TieBreaker is the target field used to choose between records whose StrategyField values are equal
+	// (strategies "min" and "max" only). Without it, ties keep the record encountered first.
+	TieBreaker string `yaml:"tie_breaker,omitempty"`
+	// TieBreakerOrder selects which record wins a tie: "asc" (smallest TieBreaker value, default) or "desc" (largest).
+	TieBreakerOrder string `yaml:"tie_breaker_order,omitempty"`
+	// CountField is the output field that receives the number of records collapsed into each kept record
+	// (an int, 1 for unique keys). Required for strategy "count" and not allowed otherwise.
+	CountField string `yaml:"countField,omitempty"`
+}
+
+// RangeExpandConfig defines how a range string field is expanded into one record per integer value.
+// Each generated record is a copy of the parent record with the value placed in TargetField.
+type RangeExpandConfig struct {
+	// SourceField is the field holding the inclusive range, e.g., "1-5" or "7" (a single value). Required.
+	// Supports dot-notation for nested fields. Integer values are also accepted and produce one record.
+	SourceField string `yaml:"source_field"`
+	// TargetField receives each integer value (int) of the range. Required. May equal SourceField to replace it.
+	TargetField string `yaml:"target_field"`
+	// Separator splits the start and end of the range. Defaults to "-".
+	Separator string `yaml:"separator,omitempty"`
+	// MaxValues caps the number of records generated from a single range; larger ranges are errors. Defaults to 10000.
+	MaxValues int `yaml:"max_values,omitempty"`
+}
+
+// RunningTotalConfig defines a cumulative sum of a numeric field across records in input order.
+// Records must already be in the desired order (e.g., sorted by the source query); processing is single-threaded.
+type RunningTotalConfig struct {
+	// Field is the numeric target field (after mappings) to accumulate. Required.
+	// Nil or missing values add nothing; non-numeric values add nothing and log a warning.
+	Field string `yaml:"field"`
+	// Target is the field that receives the running total (float64). Required.
+	Target string `yaml:"target"`
+	// PartitionBy optionally lists fields whose combined value resets the total whenever it changes
+	// from the previous record (so input should be grouped by these fields).
+	PartitionBy []string `yaml:"partition_by,omitempty"`
+}
+
+// NestFieldsConfig defines how dotted output field names are grouped into nested maps.
+// For example, "address.city" and "address.zip" become {"address": {"city": ..., "zip": ...}}.
+type NestFieldsConfig struct {
+	// Separator splits field names into nesting levels. Defaults to ".".
+	Separator string `yaml:"separator,omitempty"`
+}
+
+// OutputSchemaConfig defines the exact fields (and their order) of every output record.
+// Fields not listed are dropped; listed fields that are missing or nil are set to their Default.
+// File writers that order columns (CSV, XLSX, XML) use the listed order instead of alphabetical order.
+type OutputSchemaConfig struct {
+	// Fields lists the output fields in order. Required (at least one).
+	Fields []OutputFieldConfig `yaml:"fields"`
+}
+
+// OutputFieldConfig describes a single field of the output schema.
+type OutputFieldConfig struct {
+	// Name is the output field name. Required.
+	Name string `yaml:"name"`
+	// Default is the value used when the field is missing or nil. Defaults to nil.
+	Default interface{} `yaml:"default,omitempty"`
+}
+
+// RunMetadataConfig names the synthetic lineage fields added to every output record.
+// Each field value is the target field name to write; leave it empty to omit that field.
+// The run ID and run time are generated once per process, so every record of a run shares them.
+type RunMetadataConfig struct {
+	// RunID is the target field for a random UUID (version 4) identifying the run.
+	RunID string `yaml:"run_id,omitempty"`
+	// RunTime is the target field for the run start time (UTC).
+	RunTime string `yaml:"run_time,omitempty"`
+	// ConfigName is the target field for the base name of the configuration file.
+	ConfigName string `yaml:"config_name,omitempty"`
+	// TimeFormat is the Go layout used for RunTime. Defaults to RFC3339.
+	TimeFormat string `yaml:"time_format,omitempty"`
+}
+
+// SchemaValidationConfig points at a JSON Schema that each record must satisfy.
+// Records are validated as their JSON representation (e.g., timestamps are checked as strings).
+type SchemaValidationConfig struct {
+	// File is the path to the JSON Schema file (environment variables are expanded). Required.
+	// The schema must compile when the configuration is loaded.
+	File string `yaml:"file"`
+	// Stage selects which records are validated: "input" (before mappings) or "output" (default, just before writing).
+	Stage string `yaml:"stage,omitempty"`
+}
+
+// XMLAttributeFilterConfig selects XML record elements by an attribute on the record tag.
+type XMLAttributeFilterConfig struct {
+	// Name is the local name of the attribute on the record element (e.g., "type"). Required.
+	Name string `yaml:"name"`
+	// Value is the exact attribute value a record must have to be kept. Elements missing the attribute are skipped.
+	Value string `yaml:"value"`
 }
 
 // LoaderConfig holds settings specific to PostgreSQL loading mechanisms.
@@ -212,4 +461,8 @@ type ErrorHandlingConfig struct {
 	// If provided and mode is "skip", failed records (original data + error message) are appended.
 	// The format is typically CSV. Environment variables are expanded.
 	ErrorFile string `yaml:"errorFile,omitempty"`
+	// OnEmptyInput controls what happens when the source yields no records, regardless of Mode.
+	// "ok" (default): finish successfully without writing. "warn": same, but log a warning.
+	// "error": fail the run so pipelines notice an unexpectedly empty source.
+	OnEmptyInput string `yaml:"on_empty_input,omitempty"`
 }
\ No newline at end of file
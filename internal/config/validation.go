@@ -3,13 +3,20 @@ package config
 import (
 	"fmt"
 	"math"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 	"unicode/utf8"
 
 	"etl-tool/internal/logging"
+	"etl-tool/internal/util"
 
 	"github.com/Knetic/govaluate"
 )
@@ -17,26 +24,128 @@ import (
 // Define known valid enum values for configuration fields.
 var (
 	knownLogLevels          = []string{"none", "error", "warn", "warning", "info", "debug"}
-	knownSourceTypes        = []string{SourceTypeJSON, SourceTypeCSV, SourceTypeXLSX, SourceTypeXML, SourceTypeYAML, SourceTypePostgres}
+	knownSourceTypes        = []string{SourceTypeJSON, SourceTypeCSV, SourceTypeXLSX, SourceTypeXML, SourceTypeYAML, SourceTypeTOML, SourceTypePostgres, SourceTypeHTTP}
 	knownDestinationTypes   = []string{DestinationTypeJSON, DestinationTypeCSV, DestinationTypeXLSX, DestinationTypeXML, DestinationTypeYAML, DestinationTypePostgres}
 	knownLoaderModes        = []string{"", LoaderModeSQL}
 	knownErrorModes         = []string{ErrorHandlingModeHalt, ErrorHandlingModeSkip}
-	knownDedupStrategies    = []string{DedupStrategyFirst, DedupStrategyLast, DedupStrategyMin, DedupStrategyMax}
+	knownOnEmptyInputModes  = []string{OnEmptyInputOK, OnEmptyInputWarn, OnEmptyInputError}
+	knownDedupStrategies    = []string{DedupStrategyFirst, DedupStrategyLast, DedupStrategyMin, DedupStrategyMax, DedupStrategyCount}
+	knownTieBreakerOrders   = []string{TieBreakerOrderAsc, TieBreakerOrderDesc}
+	knownStrategyFieldTypes = []string{StrategyFieldTypeAuto, StrategyFieldTypeTimestamp}
+	knownQueryRepeatModes   = []string{"first", "join"}
+	knownURLComponents      = []string{"scheme", "host", "path", "query", "fragment", "port"}
+	knownMaskExtraModes     = []string{"drop", "append"}
+	knownTimePrecisions     = []string{"seconds", "millis", "micros", "nanos"}
+	knownSchemaStages       = []string{SchemaStageInput, SchemaStageOutput}
+	knownPseudonymFormats   = []string{"token", "name"}
+	knownDuplicateHeaders   = []string{DuplicateHeaderLast, DuplicateHeaderFirst, DuplicateHeaderError, DuplicateHeaderSuffix}
+	knownCompressions       = []string{CompressionGzip, CompressionNone}
+	knownJSONFormats        = []string{JSONFormatArray, JSONFormatLines}
+	knownHTTPMethods        = []string{HTTPMethodGet, HTTPMethodPost}
+	knownWeekdayLocales     = []string{"en", "de", "es", "fr", "it", "nl", "pt"}
 	knownHashAlgorithms     = []string{"sha256", "sha512", "md5"} // FIPS mode check happens during validation logic
+	knownPercentScales      = []string{"fraction", "whole"}
+	knownCurrencyOutputs    = []string{"amount", "currency"}
+	knownCountryDirections  = []string{"toAlpha2", "toName", "alpha2ToAlpha3"}
+	knownCastTypes          = []string{"int", "float", "bool", "string", "date"}
+	knownCheckDigitSchemes  = []string{"ean13", "isbn10", "luhn"}
+	knownCheckDigitModes    = []string{"append", "verify"}
+	knownTimezoneOutputs    = []string{"offset", "abbreviation", "seconds"}
+	knownMACSeparators      = []string{":", "-", ".", ""}
+	knownCharsetClasses     = []string{"alnum", "alpha", "digit", "upper", "lower", "hex"}
+	sourcelessTransforms    = []string{"constant", "env"}               // Transforms that ignore the input, so Source may be omitted
+	statefulTransforms      = []string{"lagfield", "validatemonotonic"} // Carry state across records, so need concurrency 1
 	knownTransformBaseFuncs = []string{
 		// Permissive transformations
 		"epochToDate", "calculateAge", "regexExtract", "trim", "toUpperCase",
 		"toLowerCase", "branch", "dateConvert", "multiDateConvert", "toInt",
 		"toFloat", "toBool", "toString", "replaceAll", "substring", "coalesce",
+		"toJSONString",
+		"padLeft", "padRight",
+		"ensurePrefix", "ensureSuffix", "stripPrefix", "stripSuffix",
 		"hash",
+		"toBoolCustom",
+		"parsePercent",
+		"normalizePercent",
+		"parseCurrency",
+		"countryCode",
+		"regexRedact",
+		"collapseRepeats",
+		"fuzzyMatch",
+		"editDistance",
+		"dedupList",
+		"sortList",
+		"listCount",
+		"nthField",
+		"excelSerialToDate",
+		"castType",
+		"sumDelimited",
+		"configLookup",
+		"severityMap",
+		"shard",
+		"truncateTime",
+		"exec",
+		"parseQueryString",
+		"urlParse",
+		"applyMask",
+		"lagField",
+		"normalizeTimestamp",
+		"completeness",
+		"pseudonymize",
+		"detectType",
+		"digitsOnly", "alphaNumericOnly",
+		"stripControl",
+		"wordCount", "charCount",
+		"reverseString",
+		"initials",
+		"normalizeMAC",
+		"base32Encode", "base32Decode", "rot13",
+		"bitFlag",
+		"convertUnit",
+		"checkDigit",
+		"timezoneInfo",
+		"template",
+		"dynamicField",
+		"flattenMap",
+		"ordinal",
+		"numberToWords",
+		"jsonParse",
+		"formatBool",
+		"jsonStringify",
+		"applyIf",
+		"numberFormat",
+		"constant", "env",
+		"isWeekend", "weekdayName",
 		// Strict transformations
 		"musttoint", "musttofloat", "musttobool", "mustepochtodate", "mustdateconvert",
+		"musttoboolcustom",
+		"mustsumdelimited",
+		"mustnormalizetimestamp",
+		"mustcheckdigit",
+		"mustbase32decode",
+		"mustnormalizepercent",
+		"mustjsonparse",
 		// Validations
 		"validateRequired", "validateRegex", "validateNumericRange",
+		"validateDateRange",
 		"validateAllowedValues",
+		"validateInFile",
+		"validateMonotonic",
+		"validatePrintable",
+		"validateMAC",
+		"validateHash",
+		"validateCharset",
+		"validateInteger",
 	}
 )
 
+// knownUnitDimensions maps each convertUnit unit to its dimension; conversions are only valid within a dimension.
+var knownUnitDimensions = map[string]string{
+	"mm": "length", "cm": "length", "m": "length", "km": "length", "in": "length", "ft": "length", "yd": "length", "mi": "length", "nmi": "length",
+	"mg": "weight", "g": "weight", "kg": "weight", "t": "weight", "oz": "weight", "lb": "weight", "st": "weight",
+	"c": "temperature", "f": "temperature", "k": "temperature",
+}
+
 // isValidEnumValue checks if a value is present in a list of allowed string values (case-insensitive).
 func isValidEnumValue(value string, allowedValues []string) bool {
 	lowerValue := strings.ToLower(value)
@@ -89,27 +198,96 @@ func ValidateConfig(cfg *ETLConfig) error {
 		}
 	}
 
+	allErrors = append(allErrors, validateConcurrency(cfg.Concurrency, cfg.Mappings)...)
+
 	// Flattening Validation ---
 	if cfg.Flattening != nil {
 		allErrors = append(allErrors, validateFlatteningConfig("Config.Flattening", cfg.Flattening, mappingTargetFields)...)
 	}
 
+	if cfg.RangeExpand != nil {
+		allErrors = append(allErrors, validateRangeExpandConfig("Config.RangeExpand", cfg.RangeExpand, mappingTargetFields)...)
+	}
+
+	if cfg.RunningTotal != nil {
+		allErrors = append(allErrors, validateRunningTotalConfig("Config.RunningTotal", cfg.RunningTotal, mappingTargetFields)...)
+	}
+
+	if cfg.NestFields != nil {
+		allErrors = append(allErrors, validateNestFieldsConfig("Config.NestFields", cfg.NestFields, mappingTargetFields)...)
+	}
+
 	if cfg.Dedup != nil {
 		// Pass mapping targets for dedup field validation
 		allErrors = append(allErrors, validateDedupConfig("Config.Dedup", cfg.Dedup, mappingTargetFields)...)
 	}
 
+	if cfg.OutputSchema != nil {
+		allErrors = append(allErrors, validateOutputSchemaConfig("Config.OutputSchema", cfg.OutputSchema)...)
+	}
+	if cfg.RunMetadata != nil {
+		allErrors = append(allErrors, validateRunMetadataConfig("Config.RunMetadata", cfg.RunMetadata, cfg.OutputSchema)...)
+	}
+	if cfg.SchemaValidation != nil {
+		allErrors = append(allErrors, validateSchemaValidationConfig("Config.SchemaValidation", cfg.SchemaValidation)...)
+	}
+
 	if cfg.ErrorHandling != nil {
 		allErrors = append(allErrors, validateErrorHandlingConfig("Config.ErrorHandling", cfg.ErrorHandling)...)
 	}
 
 	if len(allErrors) > 0 {
-		return fmt.Errorf("configuration validation failed:\n%s", strings.Join(allErrors, "\n"))
+		return newValidationError(allErrors)
 	}
 	logging.Logf(logging.Debug, "Configuration validation successful.")
 	return nil
 }
 
+// ValidationIssue is a single configuration problem: the config path it refers to and what is wrong.
+type ValidationIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidateSourceOnly checks only the logging and source sections of the configuration.
+func ValidateSourceOnly(cfg *ETLConfig) error {
+	var allErrors []string
+	if !isValidEnumValue(cfg.Logging.Level, knownLogLevels) {
+		allErrors = append(allErrors, fmt.Sprintf("- Config.Logging.Level: invalid log level '%s', must be one of %v", cfg.Logging.Level, knownLogLevels))
+	}
+	allErrors = append(allErrors, validateSourceConfig("Config.Source", &cfg.Source)...)
+	if len(allErrors) > 0 {
+		return newValidationError(allErrors)
+	}
+	return nil
+}
+
+// ValidationError is returned by ValidateConfig when one or more checks fail.
+// Its Error() text is the human-readable, newline-joined list; Issues holds the same list in structured form.
+type ValidationError struct {
+	Issues []ValidationIssue
+	lines  []string
+}
+
+// Error returns all validation problems, one per line.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("configuration validation failed:\n%s", strings.Join(e.lines, "\n"))
+}
+
+// newValidationError builds a ValidationError from "- Path: message" lines.
+func newValidationError(lines []string) *ValidationError {
+	verr := &ValidationError{Issues: make([]ValidationIssue, 0, len(lines)), lines: lines}
+	for _, line := range lines {
+		text := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+		issue := ValidationIssue{Message: text}
+		if path, msg, found := strings.Cut(text, ": "); found && !strings.ContainsAny(path, " '") {
+			issue = ValidationIssue{Path: path, Message: msg}
+		}
+		verr.Issues = append(verr.Issues, issue)
+	}
+	return verr
+}
+
 // validateSourceConfig validates the Source section of the configuration.
 func validateSourceConfig(prefix string, cfg *SourceConfig) []string {
 	var errs []string
@@ -122,9 +300,15 @@ func validateSourceConfig(prefix string, cfg *SourceConfig) []string {
 
 	lcType := strings.ToLower(cfg.Type)
 	isPostgres := lcType == SourceTypePostgres
-	isFileBased := !isPostgres // JSON, CSV, XLSX, XML, YAML
+	isHTTP := lcType == SourceTypeHTTP
+	isFileBased := !isPostgres && !isHTTP // JSON, CSV, XLSX, XML, YAML, TOML
 
-	if isFileBased {
+	if isHTTP {
+		errs = append(errs, validateHTTPSource(prefix, cfg)...)
+		if cfg.Query != "" {
+			logging.Logf(logging.Warning, "Validation: %s.Query is specified but will be ignored for source type '%s'", prefix, cfg.Type)
+		}
+	} else if isFileBased {
 		if cfg.File == "" {
 			errs = append(errs, fmt.Sprintf("- %s.File: is required for source type '%s'", prefix, cfg.Type))
 		}
@@ -140,6 +324,24 @@ func validateSourceConfig(prefix string, cfg *SourceConfig) []string {
 		}
 	}
 
+	if cfg.Compression != CompressionAuto && !isValidEnumValue(cfg.Compression, knownCompressions) {
+		errs = append(errs, fmt.Sprintf("- %s.Compression: invalid compression '%s', must be one of %v (or empty to detect '.gz')", prefix, cfg.Compression, knownCompressions))
+	} else if cfg.Compression != CompressionAuto && (isPostgres || isHTTP) {
+		logging.Logf(logging.Warning, "Validation: %s.Compression is specified but will be ignored for source type '%s'", prefix, cfg.Type)
+	}
+
+	errs = append(errs, validateHeaderMap(prefix+".HeaderMap", cfg.HeaderMap)...)
+	for _, rowOpt := range []struct {
+		name  string
+		value int
+	}{{"SkipHeaderRows", cfg.SkipHeaderRows}, {"SkipFooterRows", cfg.SkipFooterRows}, {"MaxRows", cfg.MaxRows}} {
+		if rowOpt.value < 0 {
+			errs = append(errs, fmt.Sprintf("- %s.%s: cannot be negative", prefix, rowOpt.name))
+		} else if rowOpt.value > 0 && lcType != SourceTypeCSV && lcType != SourceTypeXLSX {
+			logging.Logf(logging.Warning, "Validation: %s.%s is specified but will be ignored for type '%s'", prefix, rowOpt.name, cfg.Type)
+		}
+	}
+
 	// Format-specific checks
 	switch lcType {
 	case SourceTypeCSV:
@@ -150,6 +352,16 @@ func validateSourceConfig(prefix string, cfg *SourceConfig) []string {
 		if err := validateSingleRuneString(cfg.CommentChar, fmt.Sprintf("%s.CommentChar", prefix), true); err != nil {
 			errs = append(errs, err.Error())
 		}
+		if cfg.MultiCharDelimiter != "" {
+			if strings.ContainsAny(cfg.MultiCharDelimiter, "\r\n") {
+				errs = append(errs, fmt.Sprintf("- %s.MultiCharDelimiter: cannot contain line breaks", prefix))
+			} else {
+				logging.Logf(logging.Info, "Validation: %s.MultiCharDelimiter '%s' is set; Delimiter is ignored and quoted fields are not supported.", prefix, cfg.MultiCharDelimiter)
+			}
+		}
+		if cfg.DuplicateHeader != "" && !isValidEnumValue(cfg.DuplicateHeader, knownDuplicateHeaders) {
+			errs = append(errs, fmt.Sprintf("- %s.DuplicateHeader: invalid mode '%s', must be one of %v", prefix, cfg.DuplicateHeader, knownDuplicateHeaders))
+		}
 	case SourceTypeXLSX:
 		if cfg.SheetName != "" {
 			if err := validateSheetName(cfg.SheetName, fmt.Sprintf("%s.SheetName", prefix)); err != nil {
@@ -169,8 +381,24 @@ func validateSourceConfig(prefix string, cfg *SourceConfig) []string {
 				errs = append(errs, fmt.Sprintf("- %s.XMLRecordTag: %v", prefix, err))
 			}
 		}
-	case SourceTypeYAML, SourceTypeJSON, SourceTypePostgres:
-		// No specific format options to validate currently
+		if cfg.XMLAttributeFilter != nil {
+			if cfg.XMLAttributeFilter.Name == "" {
+				errs = append(errs, fmt.Sprintf("- %s.XMLAttributeFilter.Name: is required", prefix))
+			} else if err := validateXMLName(cfg.XMLAttributeFilter.Name); err != nil {
+				errs = append(errs, fmt.Sprintf("- %s.XMLAttributeFilter.Name: %v", prefix, err))
+			}
+		}
+	case SourceTypeJSON:
+		if cfg.Format != "" && !isValidEnumValue(cfg.Format, knownJSONFormats) {
+			errs = append(errs, fmt.Sprintf("- %s.Format: invalid JSON format '%s', must be one of %v", prefix, cfg.Format, knownJSONFormats))
+		}
+		if err := validateRecordsPath(prefix, cfg.RecordsPath); err != nil {
+			errs = append(errs, err.Error())
+		} else if cfg.RecordsPath != "" && strings.EqualFold(cfg.Format, JSONFormatLines) {
+			errs = append(errs, fmt.Sprintf("- %s.RecordsPath: cannot be used with format '%s'; each line is already one record", prefix, JSONFormatLines))
+		}
+	case SourceTypeYAML, SourceTypeTOML, SourceTypePostgres, SourceTypeHTTP:
+		// No specific format options to validate currently (HTTP options are checked by validateHTTPSource)
 	}
 
 	// Check for unused options specific to other formats
@@ -178,6 +406,127 @@ func validateSourceConfig(prefix string, cfg *SourceConfig) []string {
 	return errs
 }
 
+// validateConcurrency checks the worker count and rejects parallel processing when a mapping (directly or as a
+// 'then' of coalesce/applyIf, at any nesting depth) uses a transform that carries state from one record to the next.
+func validateConcurrency(concurrency int, mappings []MappingRule) []string {
+	if concurrency < 0 {
+		return []string{fmt.Sprintf("- Config.Concurrency: cannot be negative (got %d)", concurrency)}
+	}
+	if concurrency <= 1 {
+		return nil
+	}
+	var errs []string
+	for i, rule := range mappings {
+		name, params := rule.Transform, rule.Params
+		for name != "" {
+			base := strings.ToLower(strings.TrimSpace(strings.SplitN(name, ":", 2)[0]))
+			if isValidEnumValue(base, statefulTransforms) {
+				errs = append(errs, fmt.Sprintf("- Config.Mappings[%d].Transform: '%s' depends on record order and requires concurrency 1 (got %d)", i, base, concurrency))
+			}
+			// Follow nested 'then' transforms through 'thenParams' (e.g., applyIf -> coalesce -> lagField)
+			name, _ = params["then"].(string)
+			params, _ = params["thenParams"].(map[string]interface{})
+		}
+	}
+	return errs
+}
+
+// validateHTTPSource checks the request options of an "http" source: exactly one of URL and File naming an
+// absolute http(s) URL (after environment expansion), a supported method, well-formed headers, and a positive timeout.
+func validateHTTPSource(prefix string, cfg *SourceConfig) []string {
+	var errs []string
+	target, targetField := cfg.URL, "URL"
+	switch {
+	case cfg.URL != "" && cfg.File != "":
+		errs = append(errs, fmt.Sprintf("- %s.URL: cannot be combined with File for source type 'http'; set only one", prefix))
+	case cfg.URL == "" && cfg.File == "":
+		errs = append(errs, fmt.Sprintf("- %s.URL: is required for source type 'http' (or set File to the URL)", prefix))
+	case cfg.URL == "":
+		target, targetField = cfg.File, "File"
+	}
+	if target != "" {
+		expanded := util.ExpandEnvUniversal(target)
+		if parsed, err := url.Parse(expanded); err != nil {
+			errs = append(errs, fmt.Sprintf("- %s.%s: invalid URL '%s': %v", prefix, targetField, expanded, err))
+		} else if scheme := strings.ToLower(parsed.Scheme); scheme != "http" && scheme != "https" {
+			errs = append(errs, fmt.Sprintf("- %s.%s: URL '%s' must use the http or https scheme", prefix, targetField, expanded))
+		} else if parsed.Host == "" {
+			errs = append(errs, fmt.Sprintf("- %s.%s: URL '%s' is missing a host", prefix, targetField, expanded))
+		}
+	}
+
+	if cfg.Method != "" && !isValidEnumValue(cfg.Method, knownHTTPMethods) {
+		errs = append(errs, fmt.Sprintf("- %s.Method: invalid HTTP method '%s', must be one of %v", prefix, cfg.Method, knownHTTPMethods))
+	}
+	names := make([]string, 0, len(cfg.Headers))
+	for name := range cfg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" {
+			errs = append(errs, fmt.Sprintf("- %s.Headers: header name cannot be empty", prefix))
+		} else if strings.ContainsAny(name, " \t\r\n:") {
+			errs = append(errs, fmt.Sprintf("- %s.Headers: header name '%s' cannot contain whitespace or ':'", prefix, name))
+		}
+		if strings.ContainsAny(cfg.Headers[name], "\r\n") {
+			errs = append(errs, fmt.Sprintf("- %s.Headers: value of header '%s' cannot contain line breaks", prefix, name))
+		}
+	}
+	if cfg.Timeout != "" {
+		if timeout, err := time.ParseDuration(cfg.Timeout); err != nil || timeout <= 0 {
+			errs = append(errs, fmt.Sprintf("- %s.Timeout: invalid timeout '%s', must be a positive duration (e.g., '30s')", prefix, cfg.Timeout))
+		}
+	}
+	if err := validateRecordsPath(prefix, cfg.RecordsPath); err != nil {
+		errs = append(errs, err.Error())
+	}
+	return errs
+}
+
+// validateRecordsPath checks a RecordsPath (JSON and HTTP sources): a plain list of object keys separated by '.',
+// without empty segments or JSONPath syntax. An empty path is valid.
+func validateRecordsPath(prefix, path string) error {
+	if path == "" {
+		return nil
+	}
+	if strings.Contains("."+path+".", "..") {
+		return fmt.Errorf("- %s.RecordsPath: '%s' cannot have empty path segments", prefix, path)
+	}
+	if strings.ContainsAny(path, "$[]*") {
+		return fmt.Errorf("- %s.RecordsPath: '%s' must be a plain dotted path of object keys (e.g., 'data.items'); JSONPath syntax is not supported", prefix, path)
+	}
+	return nil
+}
+
+// validateHeaderMap checks that header renames have non-empty names and no two raw headers share a canonical name.
+func validateHeaderMap(prefix string, headerMap map[string]string) []string {
+	var errs []string
+	rawNames := make([]string, 0, len(headerMap))
+	for raw := range headerMap {
+		rawNames = append(rawNames, raw)
+	}
+	sort.Strings(rawNames)
+	canonicalOwner := make(map[string]string, len(headerMap))
+	for _, raw := range rawNames {
+		canonical := headerMap[raw]
+		if raw == "" {
+			errs = append(errs, fmt.Sprintf("- %s: raw header name cannot be empty", prefix))
+			continue
+		}
+		if strings.TrimSpace(canonical) == "" {
+			errs = append(errs, fmt.Sprintf("- %s[%s]: canonical name cannot be empty", prefix, raw))
+			continue
+		}
+		if owner, exists := canonicalOwner[canonical]; exists {
+			errs = append(errs, fmt.Sprintf("- %s[%s]: canonical name '%s' is already used by '%s'", prefix, raw, canonical, owner))
+			continue
+		}
+		canonicalOwner[canonical] = raw
+	}
+	return errs
+}
+
 // validateDestinationConfig validates the Destination section of the configuration.
 func validateDestinationConfig(prefix string, cfg *DestinationConfig) []string {
 	var errs []string
@@ -192,6 +541,12 @@ func validateDestinationConfig(prefix string, cfg *DestinationConfig) []string {
 	lcType := strings.ToLower(cfg.Type)
 	isPostgres := lcType == DestinationTypePostgres
 
+	if cfg.Compression != CompressionAuto && !isValidEnumValue(cfg.Compression, knownCompressions) {
+		errs = append(errs, fmt.Sprintf("- %s.Compression: invalid compression '%s', must be one of %v (or empty to detect '.gz')", prefix, cfg.Compression, knownCompressions))
+	} else if cfg.Compression != CompressionAuto && isPostgres {
+		logging.Logf(logging.Warning, "Validation: %s.Compression is specified but will be ignored for destination type 'postgres'", prefix)
+	}
+
 	if isPostgres {
 		if cfg.TargetTable == "" {
 			errs = append(errs, fmt.Sprintf("- %s.TargetTable: is required for destination type 'postgres'", prefix))
@@ -199,12 +554,20 @@ func validateDestinationConfig(prefix string, cfg *DestinationConfig) []string {
 		if cfg.File != "" {
 			logging.Logf(logging.Warning, "Validation: %s.File is specified but will be ignored for destination type 'postgres'", prefix)
 		}
+		if cfg.SplitBy != "" {
+			errs = append(errs, fmt.Sprintf("- %s.SplitBy: is not supported for destination type 'postgres'", prefix))
+		}
+		if cfg.Atomic {
+			logging.Logf(logging.Warning, "Validation: %s.Atomic is specified but will be ignored for destination type 'postgres'", prefix)
+		}
 		if cfg.Loader != nil {
 			errs = append(errs, validateLoaderConfig(prefix+".Loader", cfg.Loader)...)
 		}
 	} else { // isFileBased
 		if cfg.File == "" {
 			errs = append(errs, fmt.Sprintf("- %s.File: is required for destination type '%s'", prefix, cfg.Type))
+		} else if cfg.SplitBy != "" && !strings.Contains(cfg.File, SplitByPlaceholder) {
+			errs = append(errs, fmt.Sprintf("- %s.File: must contain the %s placeholder when split_by is set", prefix, SplitByPlaceholder))
 		}
 		if cfg.TargetTable != "" {
 			logging.Logf(logging.Warning, "Validation: %s.TargetTable is specified but will be ignored for destination type '%s'", prefix, cfg.Type)
@@ -212,6 +575,16 @@ func validateDestinationConfig(prefix string, cfg *DestinationConfig) []string {
 		if cfg.Loader != nil {
 			logging.Logf(logging.Warning, "Validation: %s.Loader is specified but will be ignored for destination type '%s'", prefix, cfg.Type)
 		}
+		if cfg.VerifyCount {
+			logging.Logf(logging.Warning, "Validation: %s.VerifyCount is specified but will be ignored for destination type '%s'", prefix, cfg.Type)
+		}
+		if cfg.TruncateToColumnLength {
+			logging.Logf(logging.Warning, "Validation: %s.TruncateToColumnLength is specified but will be ignored for destination type '%s'", prefix, cfg.Type)
+		}
+	}
+
+	if cfg.FloatPrecision != nil && *cfg.FloatPrecision < 0 {
+		errs = append(errs, fmt.Sprintf("- %s.FloatPrecision: cannot be negative", prefix))
 	}
 
 	// Format-specific checks
@@ -227,6 +600,18 @@ func validateDestinationConfig(prefix string, cfg *DestinationConfig) []string {
 				errs = append(errs, err.Error())
 			}
 		}
+		columns := make([]string, 0, len(cfg.NumberFormat))
+		for column := range cfg.NumberFormat {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if strings.TrimSpace(column) == "" {
+				errs = append(errs, fmt.Sprintf("- %s.NumberFormat: column name cannot be empty", prefix))
+			} else if strings.TrimSpace(cfg.NumberFormat[column]) == "" {
+				errs = append(errs, fmt.Sprintf("- %s.NumberFormat[%s]: format string cannot be empty", prefix, column))
+			}
+		}
 	case DestinationTypeXML:
 		// Default is applied if empty, so only validate if *set* to something invalid
 		if cfg.XMLRecordTag != "" {
@@ -239,7 +624,20 @@ func validateDestinationConfig(prefix string, cfg *DestinationConfig) []string {
 				errs = append(errs, fmt.Sprintf("- %s.XMLRootTag: %v", prefix, err))
 			}
 		}
-	case DestinationTypeYAML, DestinationTypeJSON, DestinationTypePostgres:
+		seenAttrs := make(map[string]bool, len(cfg.AttributeFields))
+		for i, field := range cfg.AttributeFields {
+			if err := validateXMLName(field); err != nil {
+				errs = append(errs, fmt.Sprintf("- %s.AttributeFields[%d]: %v", prefix, i, err))
+			} else if seenAttrs[field] {
+				errs = append(errs, fmt.Sprintf("- %s.AttributeFields[%d]: duplicate field '%s'", prefix, i, field))
+			}
+			seenAttrs[field] = true
+		}
+	case DestinationTypeJSON:
+		if cfg.Format != "" && !isValidEnumValue(cfg.Format, knownJSONFormats) {
+			errs = append(errs, fmt.Sprintf("- %s.Format: invalid JSON format '%s', must be one of %v", prefix, cfg.Format, knownJSONFormats))
+		}
+	case DestinationTypeYAML, DestinationTypePostgres:
 		// No specific format options to validate currently
 	}
 
@@ -287,7 +685,7 @@ func validateLoaderConfig(prefix string, cfg *LoaderConfig) []string {
 // hasShorthandValue indicates if rule.Transform contained a non-empty value after ':'.
 func validateMappingRule(prefix string, rule *MappingRule, fipsEnabled bool, hasShorthandValue bool) []string {
 	var errs []string
-	if rule.Source == "" {
+	if rule.Source == "" && !isValidEnumValue(strings.SplitN(rule.Transform, ":", 2)[0], sourcelessTransforms) {
 		errs = append(errs, fmt.Sprintf("- %s.Source: is required", prefix))
 	}
 	if rule.Target == "" {
@@ -342,7 +740,7 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 			_, explicitParamExists := params[key]
 
 			// Determine if shorthand *can* satisfy this specific key
-			canUseShorthandForKey := (funcName == "regexextract" || funcName == "validateregex") && key == "pattern"
+			canUseShorthandForKey := (funcName == "regexextract" || funcName == "validateregex" || funcName == "regexredact") && key == "pattern"
 
 			// Report missing parameter only if it's not present explicitly AND
 			// (shorthand wasn't used OR shorthand cannot satisfy this key)
@@ -402,9 +800,19 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 		}
 	}
 
+	expectBoolParam := func(key string) {
+		if params != nil {
+			if val, ok := params[key]; ok {
+				if _, isBool := val.(bool); !isBool {
+					errs = append(errs, fmt.Sprintf("- %s.Params: parameter '%s' must be a boolean for transform '%s'", prefix, key, funcName))
+				}
+			}
+		}
+	}
+
 	// --- Function-specific validations ---
 	switch funcName {
-	case "regexextract", "validateregex":
+	case "regexextract", "validateregex", "regexredact":
 		// Use expectParams to report error if pattern is missing and wasn't provided via shorthand.
 		expectParams("pattern")
 
@@ -433,6 +841,9 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 				}
 			}
 		}
+		if funcName == "regexredact" {
+			expectStringParam("replacement", true) // Allow empty replacement (delete matches)
+		}
 
 	case "dateconvert", "mustdateconvert":
 		// Params are optional; check type only if provided
@@ -467,6 +878,22 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 		expectParams("start", "length")
 		expectIntParam("start")
 		expectIntParam("length")
+	case "padleft", "padright":
+		expectParams("length")
+		expectIntParam("length")
+		expectStringParam("pad", false)
+		if params != nil {
+			if length, isInt := parseParamAsInt(params["length"]); isInt && length < 0 {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'length' (%d) cannot be negative for '%s'", prefix, length, funcName))
+			}
+			if pad, ok := params["pad"].(string); ok && pad != "" && utf8.RuneCountInString(pad) != 1 {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'pad' must be a single character for '%s', got '%s'", prefix, funcName, pad))
+			}
+		}
+	case "ensureprefix", "ensuresuffix", "stripprefix", "stripsuffix":
+		expectParams("value")
+		expectStringParam("value", false)
+		expectBoolParam("ignoreCase")
 	case "coalesce":
 		expectParams("fields")
 		expectSliceParam("fields", false)
@@ -480,6 +907,11 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 					}
 				}
 			}
+			if _, hasThen := params["then"]; hasThen {
+				errs = append(errs, validateThenTransform(prefix, funcName, params, fipsEnabled)...)
+			} else if _, hasThenParams := params["thenParams"]; hasThenParams {
+				logging.Logf(logging.Warning, "Validation: %s.Params.thenParams is specified without 'then' and will be ignored", prefix)
+			}
 		}
 	case "branch":
 		expectParams("branches")
@@ -511,8 +943,25 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 				}
 			}
 		}
-	case "hash":
+	case "applyif":
+		expectParams("condition", "then")
+		expectStringParam("condition", false)
+		if params != nil {
+			if condStr, isStr := params["condition"].(string); isStr && condStr != "" {
+				if _, err := govaluate.NewEvaluableExpression(condStr); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params.condition: invalid condition syntax: %v", prefix, err))
+				}
+			}
+			if _, hasThen := params["then"]; hasThen {
+				errs = append(errs, validateThenTransform(prefix, funcName, params, fipsEnabled)...)
+			}
+		}
+	case "hash", "validatehash":
 		expectParams("fields", "algorithm")
+		if funcName == "validatehash" {
+			expectParams("expectedField")
+			expectStringParam("expectedField", false)
+		}
 		expectStringParam("algorithm", false)
 		expectSliceParam("fields", false)
 		if params != nil {
@@ -538,6 +987,24 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 	case "validaterequired":
 		// No parameters needed
 		break
+	case "validatedaterange":
+		expectStringParam("inputFormat", false)
+		minRaw, minExists := params["min"]
+		maxRaw, maxExists := params["max"]
+		if !minExists && !maxExists {
+			errs = append(errs, fmt.Sprintf("- %s.Params: requires at least 'min' or 'max' for '%s'", prefix, funcName))
+		}
+		minDate, minOK := parseDateRangeBound(minRaw)
+		if minExists && !minOK {
+			errs = append(errs, fmt.Sprintf("- %s.Params: 'min' value '%v' is not a valid date for '%s'", prefix, minRaw, funcName))
+		}
+		maxDate, maxOK := parseDateRangeBound(maxRaw)
+		if maxExists && !maxOK {
+			errs = append(errs, fmt.Sprintf("- %s.Params: 'max' value '%v' is not a valid date for '%s'", prefix, maxRaw, funcName))
+		}
+		if minOK && maxOK && minDate.After(maxDate) {
+			errs = append(errs, fmt.Sprintf("- %s.Params: 'min' value (%v) cannot be later than 'max' value (%v)", prefix, minRaw, maxRaw))
+		}
 	case "validatenumericrange":
 		minExists, maxExists := false, false
 		if params != nil {
@@ -565,10 +1032,483 @@ func validateTransformParams(prefix, funcName, transformString string, params ma
 	case "validateallowedvalues":
 		expectParams("values")
 		expectSliceParam("values", false)
+	case "tojsonstring", "jsonstringify":
+		expectStringParam("indent", true)
+	case "formatbool":
+		expectParams("trueValue", "falseValue")
+		expectStringParam("trueValue", true)
+		expectStringParam("falseValue", true)
+	case "toboolcustom", "musttoboolcustom":
+		expectParams("truthy", "falsy")
+		expectSliceParam("truthy", false)
+		expectSliceParam("falsy", false)
+		expectBoolParam("caseInsensitive")
+	case "parsepercent", "normalizepercent", "mustnormalizepercent":
+		expectStringParam("scale", false)
+		if funcName != "parsepercent" {
+			expectBoolParam("clamp")
+		}
+		if params != nil {
+			if scale, ok := params["scale"].(string); ok && scale != "" && !isValidEnumValue(scale, knownPercentScales) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid scale '%s' for '%s', must be one of %v", prefix, scale, funcName, knownPercentScales))
+			}
+		}
+	case "parsecurrency":
+		expectStringParam("decimalSeparator", false)
+		expectStringParam("groupSeparator", false)
+		expectSliceParam("symbols", true)
+		expectStringParam("output", false)
+		if params != nil {
+			decimalSep, decOK := params["decimalSeparator"].(string)
+			groupSep, groupOK := params["groupSeparator"].(string)
+			if !decOK || decimalSep == "" {
+				decimalSep = "."
+			}
+			if !groupOK || groupSep == "" {
+				groupSep = ","
+			}
+			if decimalSep == groupSep {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'decimalSeparator' and 'groupSeparator' cannot both be '%s' for '%s'", prefix, decimalSep, funcName))
+			}
+			if output, ok := params["output"].(string); ok && output != "" && !isValidEnumValue(output, knownCurrencyOutputs) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid output '%s' for '%s', must be one of %v", prefix, output, funcName, knownCurrencyOutputs))
+			}
+		}
+	case "countrycode":
+		expectParams("direction")
+		expectStringParam("direction", false)
+		if params != nil {
+			if direction, ok := params["direction"].(string); ok && direction != "" && !isValidEnumValue(direction, knownCountryDirections) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid direction '%s' for '%s', must be one of %v", prefix, direction, funcName, knownCountryDirections))
+			}
+		}
+	case "collapserepeats":
+		expectStringParam("chars", false)
+	case "fuzzymatch":
+		expectParams("reference")
+		expectStringParam("reference", true)
+		expectNumberParam("threshold")
+		expectBoolParam("caseInsensitive")
+		if params != nil {
+			if thresholdRaw, ok := params["threshold"]; ok {
+				if threshold, isNum := parseParamAsNumber(thresholdRaw); isNum && (threshold < 0 || threshold > 1) {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'threshold' (%v) must be between 0 and 1 for '%s'", prefix, threshold, funcName))
+				}
+			}
+		}
+	case "editdistance":
+		expectParams("field")
+		expectStringParam("field", false)
+		expectBoolParam("caseInsensitive")
+	case "deduplist":
+		expectStringParam("separator", false)
+		expectBoolParam("trim")
+		expectBoolParam("caseInsensitive")
+	case "sortlist":
+		expectStringParam("separator", false)
+		expectBoolParam("numeric")
+	case "listcount":
+		expectStringParam("separator", false)
+		expectBoolParam("ignoreEmpty")
+	case "nthfield":
+		expectParams("index")
+		expectIntParam("index")
+		expectStringParam("separator", false)
+	case "excelserialtodate":
+		expectStringParam("outputFormat", false)
+		expectBoolParam("date1904")
+	case "casttype":
+		expectParams("type")
+		expectStringParam("type", false)
+		if params != nil {
+			if castTo, ok := params["type"].(string); ok && castTo != "" && !isValidEnumValue(castTo, knownCastTypes) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid type '%s' for '%s', must be one of %v", prefix, castTo, funcName, knownCastTypes))
+			}
+			if _, ok := params["inputFormat"]; ok {
+				expectStringParam("inputFormat", false)
+			}
+			if _, ok := params["outputFormat"]; ok {
+				expectStringParam("outputFormat", true)
+			}
+		}
+	case "sumdelimited", "mustsumdelimited":
+		expectStringParam("separator", false)
+	case "configlookup":
+		expectParams("file")
+		expectStringParam("file", false)
+		if params != nil {
+			if filePath, ok := params["file"].(string); ok && filePath != "" {
+				expanded := util.ExpandEnvUniversal(filePath)
+				if info, err := os.Stat(expanded); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params: lookup file '%s' for '%s' is not accessible: %v", prefix, expanded, funcName, err))
+				} else if info.IsDir() {
+					errs = append(errs, fmt.Sprintf("- %s.Params: lookup file '%s' for '%s' is a directory", prefix, expanded, funcName))
+				}
+			}
+		}
+	case "severitymap":
+		expectBoolParam("reverse")
+		codesRaw, hasCodes := params["codes"]
+		bandsRaw, hasBands := params["bands"]
+		if !hasCodes && !hasBands {
+			errs = append(errs, fmt.Sprintf("- %s.Params: at least one of 'codes' or 'bands' is required for transform '%s'", prefix, funcName))
+		}
+		if hasCodes {
+			switch codes := codesRaw.(type) {
+			case map[string]interface{}:
+				if len(codes) == 0 {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'codes' must not be empty for '%s'", prefix, funcName))
+				}
+			case map[interface{}]interface{}:
+				if len(codes) == 0 {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'codes' must not be empty for '%s'", prefix, funcName))
+				}
+			default:
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'codes' must be a map of code to label for '%s'", prefix, funcName))
+			}
+		}
+		if reverse, _ := params["reverse"].(bool); reverse && !hasCodes {
+			errs = append(errs, fmt.Sprintf("- %s.Params: 'reverse' requires 'codes' for '%s'", prefix, funcName))
+		}
+		if hasBands {
+			bands, isSlice := bandsRaw.([]interface{})
+			if !isSlice || len(bands) == 0 {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'bands' must be a non-empty list for '%s'", prefix, funcName))
+			}
+			for i, bandRaw := range bands {
+				bandPrefix := fmt.Sprintf("%s.Params.bands[%d]", prefix, i)
+				band, isMap := bandRaw.(map[string]interface{})
+				if !isMap {
+					errs = append(errs, fmt.Sprintf("- %s: must be a map with 'label' and optional 'min'/'max' keys", bandPrefix))
+					continue
+				}
+				if _, hasLabel := band["label"]; !hasLabel {
+					errs = append(errs, fmt.Sprintf("- %s: missing required key 'label'", bandPrefix))
+				}
+				minVal, minOk := parseParamAsNumber(band["min"])
+				if _, hasMin := band["min"]; hasMin && !minOk {
+					errs = append(errs, fmt.Sprintf("- %s: 'min' must be a number", bandPrefix))
+				}
+				maxVal, maxOk := parseParamAsNumber(band["max"])
+				if _, hasMax := band["max"]; hasMax && !maxOk {
+					errs = append(errs, fmt.Sprintf("- %s: 'max' must be a number", bandPrefix))
+				}
+				if minOk && maxOk && minVal >= maxVal {
+					errs = append(errs, fmt.Sprintf("- %s: 'min' (%v) must be less than 'max' (%v)", bandPrefix, minVal, maxVal))
+				}
+			}
+		}
+	case "shard":
+		expectParams("buckets")
+		expectIntParam("buckets")
+		if params != nil {
+			if bucketsRaw, ok := params["buckets"]; ok {
+				if buckets, isInt := parseParamAsInt(bucketsRaw); isInt && buckets <= 0 {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'buckets' (%d) must be a positive integer for '%s'", prefix, buckets, funcName))
+				}
+			}
+		}
+	case "truncatetime":
+		expectParams("interval")
+		expectStringParam("interval", false)
+		expectStringParam("inputFormat", false)
+		expectStringParam("outputFormat", false)
+		expectStringParam("timezone", false)
+		if params != nil {
+			if intervalStr, ok := params["interval"].(string); ok && intervalStr != "" {
+				if interval, err := time.ParseDuration(intervalStr); err != nil || interval <= 0 {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'interval' '%s' must be a positive duration (e.g. \"15m\", \"1h\") for '%s'", prefix, intervalStr, funcName))
+				}
+			}
+			if tzName, ok := params["timezone"].(string); ok && tzName != "" {
+				if _, err := time.LoadLocation(tzName); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params: invalid timezone '%s' for '%s': %v", prefix, tzName, funcName, err))
+				}
+			}
+		}
+	case "exec":
+		expectParams("command")
+		expectStringParam("command", false)
+		expectSliceParam("args", true)
+		expectStringParam("timeout", false)
+		if params != nil {
+			if argsRaw, ok := params["args"].([]interface{}); ok {
+				for i, a := range argsRaw {
+					if _, isStr := a.(string); !isStr {
+						errs = append(errs, fmt.Sprintf("- %s.Params.args[%d]: item must be a string", prefix, i))
+					}
+				}
+			}
+			if timeoutStr, ok := params["timeout"].(string); ok && timeoutStr != "" {
+				if timeout, err := time.ParseDuration(timeoutStr); err != nil || timeout <= 0 {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'timeout' '%s' must be a positive duration for '%s'", prefix, timeoutStr, funcName))
+				}
+			}
+		}
+	case "parsequerystring":
+		expectStringParam("repeated", false)
+		expectStringParam("separator", true)
+		if params != nil {
+			if repeated, ok := params["repeated"].(string); ok && repeated != "" && !isValidEnumValue(repeated, knownQueryRepeatModes) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid repeated mode '%s' for '%s', must be one of %v", prefix, repeated, funcName, knownQueryRepeatModes))
+			}
+		}
+	case "urlparse":
+		expectParams("component")
+		expectStringParam("component", false)
+		if params != nil {
+			if component, ok := params["component"].(string); ok && component != "" && !isValidEnumValue(component, knownURLComponents) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid component '%s' for '%s', must be one of %v", prefix, component, funcName, knownURLComponents))
+			}
+		}
+	case "applymask":
+		expectParams("mask")
+		expectStringParam("mask", false)
+		expectStringParam("extra", false)
+		if params != nil {
+			if mask, ok := params["mask"].(string); ok && mask != "" && !strings.Contains(mask, "#") {
+				logging.Logf(logging.Warning, "Validation: %s.Params: mask '%s' for '%s' contains no '#' placeholders; output will be the literal mask", prefix, mask, funcName)
+			}
+			if extra, ok := params["extra"].(string); ok && extra != "" && !isValidEnumValue(extra, knownMaskExtraModes) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid extra mode '%s' for '%s', must be one of %v", prefix, extra, funcName, knownMaskExtraModes))
+			}
+		}
+	case "lagfield":
+		expectParams("field")
+		expectStringParam("field", false)
+		expectStringParam("partitionBy", false)
+		expectBoolParam("delta")
+	case "normalizetimestamp", "mustnormalizetimestamp":
+		expectStringParam("inputFormat", false)
+		expectStringParam("precision", false)
+		if params != nil {
+			if precision, ok := params["precision"].(string); ok && precision != "" && !isValidEnumValue(precision, knownTimePrecisions) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid precision '%s' for '%s', must be one of %v", prefix, precision, funcName, knownTimePrecisions))
+			}
+		}
+	case "completeness":
+		expectParams("fields")
+		expectSliceParam("fields", false)
+		if params != nil {
+			if fields, ok := params["fields"].([]interface{}); ok {
+				for i, fieldInterface := range fields {
+					if strField, isStr := fieldInterface.(string); !isStr || strField == "" {
+						errs = append(errs, fmt.Sprintf("- %s.Params.fields[%d]: item must be a non-empty string field name", prefix, i))
+					}
+				}
+			}
+		}
+	case "pseudonymize":
+		expectParams("key")
+		expectStringParam("key", false)
+		expectStringParam("format", false)
+		expectIntParam("length")
+		if params != nil {
+			if format, ok := params["format"].(string); ok && format != "" && !isValidEnumValue(format, knownPseudonymFormats) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid 'format' '%s' for '%s', must be one of %v", prefix, format, funcName, knownPseudonymFormats))
+			}
+			if lengthRaw, ok := params["length"]; ok {
+				if length, isInt := parseParamAsInt(lengthRaw); isInt && (length < 1 || length > 64) {
+					errs = append(errs, fmt.Sprintf("- %s.Params: 'length' (%d) must be between 1 and 64 for '%s'", prefix, length, funcName))
+				}
+				if format, _ := params["format"].(string); strings.EqualFold(format, "name") {
+					logging.Logf(logging.Warning, "Validation: %s.Params: 'length' is ignored by '%s' with format 'name'", prefix, funcName)
+				}
+			}
+		}
+	case "validateinfile":
+		expectParams("file", "keyField")
+		expectStringParam("file", false)
+		expectStringParam("keyField", false)
+		if params != nil {
+			if filePath, ok := params["file"].(string); ok && filePath != "" {
+				expanded := util.ExpandEnvUniversal(filePath)
+				if info, err := os.Stat(expanded); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params: reference file '%s' for '%s' is not accessible: %v", prefix, expanded, funcName, err))
+				} else if info.IsDir() {
+					errs = append(errs, fmt.Sprintf("- %s.Params: reference file '%s' for '%s' is a directory", prefix, expanded, funcName))
+				} else if ext := strings.ToLower(filepath.Ext(expanded)); ext != ".csv" && ext != ".json" && ext != ".yaml" && ext != ".yml" {
+					errs = append(errs, fmt.Sprintf("- %s.Params: reference file '%s' for '%s' must have a .csv, .json, .yaml, or .yml extension", prefix, expanded, funcName))
+				}
+			}
+		}
+	case "digitsonly":
+		expectBoolParam("keepLeadingPlus")
+	case "validatemonotonic":
+		expectParams("field", "keyField")
+		expectStringParam("field", false)
+		expectStringParam("keyField", false)
+		expectBoolParam("strict")
+	case "validatecharset":
+		expectParams("charset")
+		expectStringParam("charset", false)
+		if params != nil {
+			if charset, ok := params["charset"].(string); ok && charset != "" && !isValidEnumValue(charset, knownCharsetClasses) {
+				if err := checkCharsetRanges(charset); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params: %v for '%s'", prefix, err, funcName))
+				}
+			}
+		}
+	case "validateprintable":
+		expectBoolParam("asciiOnly")
+	case "stripcontrol":
+		expectBoolParam("keepWhitespace")
+		expectBoolParam("asciiOnly")
+	case "constant":
+		expectParams("value")
+	case "env":
+		expectParams("name")
+		expectStringParam("name", false)
+		if name, isStr := params["name"].(string); isStr && strings.ContainsAny(name, "=\x00") {
+			errs = append(errs, fmt.Sprintf("- %s.Params: 'name' must be a valid environment variable name for '%s', got '%s'", prefix, funcName, name))
+		}
+	case "numberformat":
+		expectIntParam("decimals")
+		expectStringParam("thousandsSep", true)
+		expectStringParam("decimalSep", false)
+		if params != nil {
+			if decimals, isInt := parseParamAsInt(params["decimals"]); isInt && (decimals < 0 || decimals > 20) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'decimals' (%d) must be between 0 and 20 for '%s'", prefix, decimals, funcName))
+			}
+			thousandsSep, decimalSep := ",", "."
+			if sep, isStr := params["thousandsSep"].(string); isStr {
+				thousandsSep = sep
+			}
+			if sep, isStr := params["decimalSep"].(string); isStr && sep != "" {
+				decimalSep = sep
+			}
+			if thousandsSep == decimalSep {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'thousandsSep' and 'decimalSep' must differ for '%s' (both are '%s')", prefix, funcName, decimalSep))
+			}
+		}
+	case "numbertowords":
+		expectBoolParam("currency")
+	case "initials":
+		expectIntParam("count")
+		expectStringParam("separator", true)
+		if params != nil {
+			if count, isInt := parseParamAsInt(params["count"]); isInt && count <= 0 {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'count' (%d) must be a positive integer for '%s'", prefix, count, funcName))
+			}
+		}
+	case "normalizemac":
+		expectStringParam("separator", true)
+		expectBoolParam("uppercase")
+		if params != nil {
+			if separator, ok := params["separator"].(string); ok && !isValidEnumValue(separator, knownMACSeparators) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid separator '%s' for '%s', must be one of %q", prefix, separator, funcName, knownMACSeparators))
+			}
+		}
+	case "template":
+		expectParams("template")
+		expectStringParam("template", false)
+		if text, ok := params["template"].(string); ok && text != "" {
+			if _, err := template.New("template").Parse(text); err != nil {
+				errs = append(errs, fmt.Sprintf("- %s.Params.template: invalid template: %v", prefix, err))
+			}
+		}
+	case "flattenmap":
+		expectStringParam("prefix", true)
+		expectStringParam("separator", true)
+	case "bitflag":
+		expectParams("bit")
+		expectIntParam("bit")
+		if params != nil {
+			if bit, isInt := parseParamAsInt(params["bit"]); isInt && (bit < 0 || bit > 63) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'bit' (%d) must be between 0 and 63 for '%s'", prefix, bit, funcName))
+			}
+		}
+	case "convertunit":
+		expectParams("from", "to")
+		expectStringParam("from", false)
+		expectStringParam("to", false)
+		if params != nil {
+			from, fromIsStr := params["from"].(string)
+			to, toIsStr := params["to"].(string)
+			if fromIsStr && toIsStr && from != "" && to != "" {
+				fromDim, fromOk := knownUnitDimensions[strings.ToLower(from)]
+				toDim, toOk := knownUnitDimensions[strings.ToLower(to)]
+				if !fromOk || !toOk || fromDim != toDim {
+					errs = append(errs, fmt.Sprintf("- %s.Params: unsupported unit conversion from '%s' to '%s' for '%s'", prefix, from, to, funcName))
+				}
+			}
+		}
+	case "checkdigit", "mustcheckdigit":
+		expectParams("scheme")
+		expectStringParam("scheme", false)
+		expectStringParam("mode", false)
+		if params != nil {
+			if scheme, ok := params["scheme"].(string); ok && scheme != "" && !isValidEnumValue(scheme, knownCheckDigitSchemes) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid 'scheme' '%s' for '%s', must be one of %v", prefix, scheme, funcName, knownCheckDigitSchemes))
+			}
+			if mode, ok := params["mode"].(string); ok && mode != "" && !isValidEnumValue(mode, knownCheckDigitModes) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid 'mode' '%s' for '%s', must be one of %v", prefix, mode, funcName, knownCheckDigitModes))
+			}
+		}
+	case "timezoneinfo":
+		expectParams("timezone")
+		expectStringParam("timezone", false)
+		expectStringParam("inputFormat", false)
+		expectStringParam("output", false)
+		if params != nil {
+			if tzName, ok := params["timezone"].(string); ok && tzName != "" {
+				if _, err := time.LoadLocation(tzName); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params: invalid timezone '%s' for '%s': %v", prefix, tzName, funcName, err))
+				}
+			}
+			if output, ok := params["output"].(string); ok && output != "" && !isValidEnumValue(output, knownTimezoneOutputs) {
+				errs = append(errs, fmt.Sprintf("- %s.Params: invalid 'output' '%s' for '%s', must be one of %v", prefix, output, funcName, knownTimezoneOutputs))
+			}
+		}
+	case "isweekend", "weekdayname":
+		expectStringParam("inputFormat", false)
+		expectStringParam("timezone", false)
+		if params != nil {
+			if tzName, ok := params["timezone"].(string); ok && tzName != "" {
+				if _, err := time.LoadLocation(tzName); err != nil {
+					errs = append(errs, fmt.Sprintf("- %s.Params: invalid timezone '%s' for '%s': %v", prefix, tzName, funcName, err))
+				}
+			}
+		}
+		if funcName == "weekdayname" {
+			expectStringParam("locale", false)
+			if locale, ok := params["locale"].(string); ok && locale != "" {
+				if !isValidEnumValue(util.LocaleLanguage(locale), knownWeekdayLocales) {
+					errs = append(errs, fmt.Sprintf("- %s.Params: unsupported 'locale' '%s' for '%s', must be one of %v (optionally with a region, e.g. 'en-US')", prefix, locale, funcName, knownWeekdayLocales))
+				}
+			}
+		}
+	case "toint", "tofloat", "musttoint", "musttofloat":
+		expectStringParam("thousandsSep", true)
+		expectStringParam("decimalSep", false)
+		if params != nil {
+			thousandsSep, _ := params["thousandsSep"].(string)
+			decimalSep, _ := params["decimalSep"].(string)
+			if decimalSep == "" {
+				decimalSep = "."
+			}
+			if thousandsSep == decimalSep {
+				errs = append(errs, fmt.Sprintf("- %s.Params: 'thousandsSep' and 'decimalSep' must differ for '%s' (both are '%s')", prefix, funcName, decimalSep))
+			}
+			for _, key := range []string{"thousandsSep", "decimalSep"} {
+				if sep, isStr := params[key].(string); isStr && strings.ContainsAny(sep, "0123456789+-") {
+					errs = append(errs, fmt.Sprintf("- %s.Params: '%s' ('%s') must not contain digits or signs for '%s'", prefix, key, sep, funcName))
+				}
+			}
+		}
 	// Functions without parameters
 	case "epochtodate", "calculateage", "trim", "touppercase", "tolowercase",
-		"toint", "tofloat", "tobool", "tostring",
-		"musttoint", "musttofloat", "musttobool", "mustepochtodate":
+		"tobool", "tostring",
+		"musttobool", "mustepochtodate",
+		"detecttype",
+		"alphanumericonly",
+		"wordcount", "charcount",
+		"reversestring",
+		"dynamicfield",
+		"validatemac", "validateinteger",
+		"base32encode", "base32decode", "mustbase32decode", "rot13",
+		"ordinal",
+		"jsonparse", "mustjsonparse":
 		if len(params) > 0 {
 			logging.Logf(logging.Warning, "Validation: %s.Params are specified but ignored for transform '%s'", prefix, funcName)
 		}
@@ -609,6 +1549,148 @@ func validateFlatteningConfig(prefix string, cfg *FlatteningConfig, mappingTarge
 	return errs
 }
 
+// validateRangeExpandConfig validates the RangeExpand section.
+func validateRangeExpandConfig(prefix string, cfg *RangeExpandConfig, mappingTargets map[string]bool) []string {
+	var errs []string
+	if cfg.SourceField == "" {
+		errs = append(errs, fmt.Sprintf("- %s.SourceField: is required", prefix))
+	} else if !mappingTargets[strings.SplitN(cfg.SourceField, ".", 2)[0]] {
+		logging.Logf(logging.Warning, "Validation: %s.SourceField: field '%s' is not an explicit target field in mappings. Ensure it exists in the processed record.", prefix, cfg.SourceField)
+	}
+	if cfg.TargetField == "" {
+		errs = append(errs, fmt.Sprintf("- %s.TargetField: is required", prefix))
+	} else if cfg.TargetField != cfg.SourceField && mappingTargets[cfg.TargetField] {
+		errs = append(errs, fmt.Sprintf("- %s.TargetField: '%s' conflicts with a target field defined in mappings", prefix, cfg.TargetField))
+	}
+	if strings.TrimSpace(cfg.Separator) == "" && cfg.Separator != "" {
+		errs = append(errs, fmt.Sprintf("- %s.Separator: cannot be whitespace only", prefix))
+	}
+	if cfg.MaxValues < 0 {
+		errs = append(errs, fmt.Sprintf("- %s.MaxValues: must be a positive integer, got %d", prefix, cfg.MaxValues))
+	}
+	return errs
+}
+
+// validateRunningTotalConfig validates the RunningTotal section.
+func validateRunningTotalConfig(prefix string, cfg *RunningTotalConfig, mappingTargets map[string]bool) []string {
+	var errs []string
+	if cfg.Field == "" {
+		errs = append(errs, fmt.Sprintf("- %s.Field: is required", prefix))
+	} else if !mappingTargets[cfg.Field] {
+		logging.Logf(logging.Warning, "Validation: %s.Field: field '%s' is not an explicit target field in mappings. Ensure it exists in the processed record.", prefix, cfg.Field)
+	}
+	if cfg.Target == "" {
+		errs = append(errs, fmt.Sprintf("- %s.Target: is required", prefix))
+	} else if mappingTargets[cfg.Target] {
+		logging.Logf(logging.Warning, "Validation: %s.Target: field '%s' is also a mapping target and will be overwritten by the running total", prefix, cfg.Target)
+	}
+	for i, key := range cfg.PartitionBy {
+		if key == "" {
+			errs = append(errs, fmt.Sprintf("- %s.PartitionBy[%d]: key cannot be empty", prefix, i))
+		} else if !mappingTargets[key] {
+			logging.Logf(logging.Warning, "Validation: %s.PartitionBy[%d]: key '%s' is not an explicit target field in mappings. Ensure it exists in the processed record.", prefix, i, key)
+		}
+	}
+	return errs
+}
+
+// validateNestFieldsConfig validates the NestFields section and checks that no mapping target is both a value
+// and the parent of another target (e.g., "address" and "address.city"), which cannot be nested.
+func validateNestFieldsConfig(prefix string, cfg *NestFieldsConfig, mappingTargets map[string]bool) []string {
+	var errs []string
+	separator := cfg.Separator
+	if separator == "" {
+		separator = DefaultNestFieldsSeparator
+	}
+	targets := make([]string, 0, len(mappingTargets))
+	for target := range mappingTargets {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		parts := strings.Split(target, separator)
+		for i := 1; i < len(parts); i++ {
+			parent := strings.Join(parts[:i], separator)
+			if mappingTargets[parent] {
+				errs = append(errs, fmt.Sprintf("- %s: mapping target '%s' conflicts with nested target '%s'", prefix, parent, target))
+			}
+		}
+		for _, part := range parts {
+			if part == "" && len(parts) > 1 {
+				errs = append(errs, fmt.Sprintf("- %s: mapping target '%s' has an empty name segment", prefix, target))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// validateOutputSchemaConfig validates the OutputSchema section.
+func validateOutputSchemaConfig(prefix string, cfg *OutputSchemaConfig) []string {
+	var errs []string
+	if len(cfg.Fields) == 0 {
+		errs = append(errs, fmt.Sprintf("- %s.Fields: at least one field is required", prefix))
+		return errs
+	}
+	seen := make(map[string]bool, len(cfg.Fields))
+	for i, field := range cfg.Fields {
+		if strings.TrimSpace(field.Name) == "" {
+			errs = append(errs, fmt.Sprintf("- %s.Fields[%d].Name: is required", prefix, i))
+			continue
+		}
+		if seen[field.Name] {
+			errs = append(errs, fmt.Sprintf("- %s.Fields[%d].Name: duplicate field '%s'", prefix, i, field.Name))
+		}
+		seen[field.Name] = true
+	}
+	return errs
+}
+
+// validateRunMetadataConfig validates the RunMetadata section.
+func validateRunMetadataConfig(prefix string, cfg *RunMetadataConfig, schema *OutputSchemaConfig) []string {
+	var errs []string
+	fields := []struct{ option, name string }{{"RunID", cfg.RunID}, {"RunTime", cfg.RunTime}, {"ConfigName", cfg.ConfigName}}
+	seen := make(map[string]string, len(fields))
+	for _, f := range fields {
+		if f.name == "" {
+			continue
+		}
+		if other, dup := seen[f.name]; dup {
+			errs = append(errs, fmt.Sprintf("- %s.%s: field name '%s' is already used by %s", prefix, f.option, f.name, other))
+			continue
+		}
+		seen[f.name] = f.option
+		if schema != nil {
+			for _, schemaField := range schema.Fields {
+				if schemaField.Name == f.name {
+					logging.Logf(logging.Warning, "Validation: %s.%s: field '%s' is also an output schema field and will be overwritten by run metadata", prefix, f.option, f.name)
+				}
+			}
+		}
+	}
+	if len(seen) == 0 && len(errs) == 0 {
+		errs = append(errs, fmt.Sprintf("- %s: at least one of run_id, run_time, or config_name must name a target field", prefix))
+	}
+	if cfg.TimeFormat != "" && cfg.RunTime == "" {
+		logging.Logf(logging.Warning, "Validation: %s.TimeFormat is specified but will be ignored without RunTime", prefix)
+	}
+	return errs
+}
+
+// validateSchemaValidationConfig validates the SchemaValidation section, including that the schema file compiles.
+func validateSchemaValidationConfig(prefix string, cfg *SchemaValidationConfig) []string {
+	var errs []string
+	if cfg.Stage != "" && !isValidEnumValue(cfg.Stage, knownSchemaStages) {
+		errs = append(errs, fmt.Sprintf("- %s.Stage: invalid stage '%s', must be one of %v", prefix, cfg.Stage, knownSchemaStages))
+	}
+	if strings.TrimSpace(cfg.File) == "" {
+		errs = append(errs, fmt.Sprintf("- %s.File: is required", prefix))
+	} else if _, err := CompileJSONSchema(cfg.File); err != nil {
+		errs = append(errs, fmt.Sprintf("- %s.File: failed to load JSON Schema '%s': %v", prefix, cfg.File, err))
+	}
+	return errs
+}
+
 // validateDedupConfig validates the Deduplication section.
 func validateDedupConfig(prefix string, cfg *DedupConfig, mappingTargets map[string]bool) []string {
 	var errs []string
@@ -654,7 +1736,45 @@ func validateDedupConfig(prefix string, cfg *DedupConfig, mappingTargets map[str
 			if cfg.StrategyField != "" {
 				logging.Logf(logging.Warning, "Validation: %s.StrategyField ('%s') is specified but will be ignored when strategy is '%s'", prefix, cfg.StrategyField, cfg.Strategy)
 			}
+			if cfg.TieBreaker != "" {
+				logging.Logf(logging.Warning, "Validation: %s.TieBreaker ('%s') is specified but will be ignored when strategy is '%s'", prefix, cfg.TieBreaker, cfg.Strategy)
+			}
+			if cfg.StrategyFieldType != "" {
+				logging.Logf(logging.Warning, "Validation: %s.StrategyFieldType ('%s') is specified but will be ignored when strategy is '%s'", prefix, cfg.StrategyFieldType, cfg.Strategy)
+			}
+		}
+	}
+
+	if cfg.StrategyFieldType != "" && !isValidEnumValue(cfg.StrategyFieldType, knownStrategyFieldTypes) {
+		errs = append(errs, fmt.Sprintf("- %s.StrategyFieldType: invalid type '%s', must be one of %v", prefix, cfg.StrategyFieldType, knownStrategyFieldTypes))
+	}
+
+	// Validate count field
+	if strings.EqualFold(cfg.Strategy, DedupStrategyCount) {
+		if cfg.CountField == "" {
+			errs = append(errs, fmt.Sprintf("- %s.CountField: is required when strategy is '%s'", prefix, DedupStrategyCount))
+		}
+		for _, key := range cfg.Keys {
+			if cfg.CountField != "" && key == cfg.CountField {
+				errs = append(errs, fmt.Sprintf("- %s.CountField: field '%s' cannot also be a dedup key", prefix, cfg.CountField))
+			}
+		}
+	} else if cfg.CountField != "" {
+		errs = append(errs, fmt.Sprintf("- %s.CountField: is only allowed when strategy is '%s'", prefix, DedupStrategyCount))
+	}
+
+	// Validate tie-breaker
+	if cfg.TieBreaker != "" {
+		if cfg.TieBreaker == cfg.StrategyField {
+			errs = append(errs, fmt.Sprintf("- %s.TieBreaker: field '%s' must differ from StrategyField", prefix, cfg.TieBreaker))
+		} else if _, isMappingTarget := mappingTargets[cfg.TieBreaker]; !isMappingTarget {
+			logging.Logf(logging.Warning, "Validation: %s.TieBreaker: field '%s' is not an explicit target field in mappings. Ensure it exists for comparison.", prefix, cfg.TieBreaker)
 		}
+	} else if cfg.TieBreakerOrder != "" {
+		logging.Logf(logging.Warning, "Validation: %s.TieBreakerOrder ('%s') is specified but will be ignored without a TieBreaker field", prefix, cfg.TieBreakerOrder)
+	}
+	if cfg.TieBreakerOrder != "" && !isValidEnumValue(cfg.TieBreakerOrder, knownTieBreakerOrders) {
+		errs = append(errs, fmt.Sprintf("- %s.TieBreakerOrder: invalid order '%s', must be one of %v", prefix, cfg.TieBreakerOrder, knownTieBreakerOrders))
 	}
 	return errs
 }
@@ -665,6 +1785,9 @@ func validateErrorHandlingConfig(prefix string, cfg *ErrorHandlingConfig) []stri
 	if !isValidEnumValue(cfg.Mode, knownErrorModes) {
 		errs = append(errs, fmt.Sprintf("- %s.Mode: invalid error handling mode '%s', must be one of %v", prefix, cfg.Mode, knownErrorModes))
 	}
+	if cfg.OnEmptyInput != "" && !isValidEnumValue(cfg.OnEmptyInput, knownOnEmptyInputModes) {
+		errs = append(errs, fmt.Sprintf("- %s.OnEmptyInput: invalid value '%s', must be one of %v", prefix, cfg.OnEmptyInput, knownOnEmptyInputModes))
+	}
 
 	// Check dependent options based on mode
 	if cfg.Mode == ErrorHandlingModeHalt {
@@ -760,6 +1883,13 @@ func validateUnusedFormatOptions(prefix, actualType string, cfg interface{}) {
 		if _, isSource := cfg.(*SourceConfig); isSource && isFieldSet(v, "CommentChar") {
 			logging.Logf(logging.Warning, "Validation: %s.CommentChar is specified but will be ignored for type '%s'", prefix, actualType)
 		}
+		if _, isSource := cfg.(*SourceConfig); isSource {
+			for _, field := range []string{"MultiCharDelimiter", "TrimFields", "TreatEmptyAsNull", "DuplicateHeader"} {
+				if isFieldSet(v, field) {
+					logging.Logf(logging.Warning, "Validation: %s.%s is specified but will be ignored for type '%s'", prefix, field, actualType)
+				}
+			}
+		}
 	}
 
 	// Check XLSX options
@@ -771,6 +1901,39 @@ func validateUnusedFormatOptions(prefix, actualType string, cfg interface{}) {
 		if _, isSource := cfg.(*SourceConfig); isSource && isFieldSet(v, "SheetIndex") {
 			logging.Logf(logging.Warning, "Validation: %s.SheetIndex is specified but will be ignored for type '%s'", prefix, actualType)
 		}
+		if _, isSource := cfg.(*SourceConfig); isSource && isFieldSet(v, "TypedCells") {
+			logging.Logf(logging.Warning, "Validation: %s.TypedCells is specified but will be ignored for type '%s'", prefix, actualType)
+		}
+		// BoldHeader, FreezeHeader, and NumberFormat are destination-specific
+		if _, isDest := cfg.(*DestinationConfig); isDest {
+			for _, field := range []string{"BoldHeader", "FreezeHeader", "NumberFormat"} {
+				if isFieldSet(v, field) {
+					logging.Logf(logging.Warning, "Validation: %s.%s is specified but will be ignored for type '%s'", prefix, field, actualType)
+				}
+			}
+		}
+	}
+
+	// Check JSON options
+	if lcActualType != SourceTypeJSON && lcActualType != DestinationTypeJSON && isFieldSet(v, "Format") {
+		logging.Logf(logging.Warning, "Validation: %s.Format is specified but will be ignored for type '%s'", prefix, actualType)
+	}
+	if lcActualType != SourceTypeJSON && lcActualType != SourceTypeHTTP && isFieldSet(v, "RecordsPath") {
+		logging.Logf(logging.Warning, "Validation: %s.RecordsPath is specified but will be ignored for type '%s'", prefix, actualType)
+	}
+
+	// HTTP options are source-specific
+	if _, isSource := cfg.(*SourceConfig); isSource && lcActualType != SourceTypeHTTP {
+		for _, field := range []string{"URL", "Method", "Headers", "Timeout"} {
+			if isFieldSet(v, field) {
+				logging.Logf(logging.Warning, "Validation: %s.%s is specified but will be ignored for type '%s'", prefix, field, actualType)
+			}
+		}
+	}
+
+	// FloatPrecision is destination-specific (CSV and JSON)
+	if _, isDest := cfg.(*DestinationConfig); isDest && lcActualType != DestinationTypeCSV && lcActualType != DestinationTypeJSON && isFieldSet(v, "FloatPrecision") {
+		logging.Logf(logging.Warning, "Validation: %s.FloatPrecision is specified but will be ignored for type '%s'", prefix, actualType)
 	}
 
 	// Check XML options
@@ -778,9 +1941,16 @@ func validateUnusedFormatOptions(prefix, actualType string, cfg interface{}) {
 		if isFieldSet(v, "XMLRecordTag") {
 			logging.Logf(logging.Warning, "Validation: %s.XMLRecordTag is specified but will be ignored for type '%s'", prefix, actualType)
 		}
+		if _, isSource := cfg.(*SourceConfig); isSource && isFieldSet(v, "XMLAttributeFilter") {
+			logging.Logf(logging.Warning, "Validation: %s.XMLAttributeFilter is specified but will be ignored for type '%s'", prefix, actualType)
+		}
 		// XMLRootTag is destination-specific
-		if _, isDest := cfg.(*DestinationConfig); isDest && isFieldSet(v, "XMLRootTag") {
-			logging.Logf(logging.Warning, "Validation: %s.XMLRootTag is specified but will be ignored for type '%s'", prefix, actualType)
+		if _, isDest := cfg.(*DestinationConfig); isDest {
+			for _, field := range []string{"XMLRootTag", "AttributeFields"} {
+				if isFieldSet(v, field) {
+					logging.Logf(logging.Warning, "Validation: %s.%s is specified but will be ignored for type '%s'", prefix, field, actualType)
+				}
+			}
 		}
 	}
 }
@@ -794,8 +1964,76 @@ func isFieldSet(v reflect.Value, fieldName string) bool {
 	return !field.IsZero()
 }
 
+// validateThenTransform checks the nested 'then' transform (name, optionally with a ":" shorthand value) and its
+// optional 'thenParams' map used by coalesce and applyIf. coalesce cannot nest another coalesce.
+func validateThenTransform(prefix, funcName string, params map[string]interface{}, fipsEnabled bool) []string {
+	var errs []string
+	then, isStr := params["then"].(string)
+	thenBase := strings.ToLower(strings.TrimSpace(strings.SplitN(then, ":", 2)[0]))
+	thenParams, paramsOk := params["thenParams"].(map[string]interface{})
+	if _, hasThenParams := params["thenParams"]; hasThenParams && !paramsOk {
+		errs = append(errs, fmt.Sprintf("- %s.Params.thenParams: must be a map for transform '%s'", prefix, funcName))
+	}
+	switch {
+	case !isStr || thenBase == "":
+		errs = append(errs, fmt.Sprintf("- %s.Params.then: must be a non-empty transformation name", prefix))
+	case funcName == "coalesce" && thenBase == "coalesce":
+		errs = append(errs, fmt.Sprintf("- %s.Params.then: cannot be 'coalesce'", prefix))
+	case !isValidEnumValue(thenBase, knownTransformBaseFuncs):
+		errs = append(errs, fmt.Sprintf("- %s.Params.then: unknown base transformation function '%s'", prefix, thenBase))
+	default:
+		thenParts := strings.SplitN(then, ":", 2)
+		thenHasShorthand := len(thenParts) == 2 && strings.TrimSpace(thenParts[1]) != ""
+		errs = append(errs, validateTransformParams(prefix+".Params.then", thenBase, then, thenParams, fipsEnabled, thenHasShorthand)...)
+	}
+	return errs
+}
+
 // --- Parameter Parsing Helpers (used within validation) ---
 
+// dateRangeBoundLayouts mirror the layouts validateDateRange accepts for its bounds (RFC3339, naive ISO, and the
+// dateConvert fallbacks).
+var dateRangeBoundLayouts = []string{
+	time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02", "2006/01/02", "01/02/2006", "2006-01-02 15:04:05",
+	time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822, "01-02-06", "20060102",
+}
+
+// parseDateRangeBound parses a validateDateRange 'min'/'max' bound: a time.Time, "now", "today", or a date string.
+func parseDateRangeBound(raw interface{}) (time.Time, bool) {
+	switch v := raw.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "now":
+			return time.Now(), true
+		case "today":
+			return time.Now().UTC().Truncate(24 * time.Hour), true
+		}
+		for _, layout := range dateRangeBoundLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkCharsetRanges reports a reversed "lo-hi" range (e.g., "z-a") in a custom validateCharset 'charset'.
+func checkCharsetRanges(charset string) error {
+	runes := []rune(charset)
+	for i := 0; i+2 < len(runes); i++ {
+		if runes[i+1] != '-' {
+			continue
+		}
+		if runes[i] > runes[i+2] {
+			return fmt.Errorf("invalid range '%c-%c' in 'charset'", runes[i], runes[i+2])
+		}
+		i += 2
+	}
+	return nil
+}
+
 // parseParamAsInt parses various numeric types or string representations into an int.
 func parseParamAsInt(v interface{}) (int, bool) {
 	const maxIntPlatform = int(^uint(0) >> 1)
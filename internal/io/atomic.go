@@ -0,0 +1,104 @@
+package io
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"etl-tool/internal/logging"
+)
+
+// AtomicWriter wraps a file-based OutputWriter so that consumers never see a partially written output file.
+// Writes go to a hidden temporary file in the destination's directory, which Close renames to the final path.
+// If any Write fails (or the wrapped writer fails to close), the temporary file is removed instead and an
+// existing file at the final path is left untouched.
+type AtomicWriter struct {
+	inner     OutputWriter
+	mu        sync.Mutex
+	finalPath string // Destination path from the first Write call
+	tempPath  string // Temporary file receiving the output; empty until the first Write
+	failed    bool   // Set when a Write fails; Close then discards the temporary file
+}
+
+// NewAtomicWriter wraps inner so its output is only moved into place on a successful Close.
+func NewAtomicWriter(inner OutputWriter) *AtomicWriter {
+	return &AtomicWriter{inner: inner}
+}
+
+// SetFieldOrder implements FieldOrderer by forwarding to the wrapped writer, if it supports field ordering.
+func (aw *AtomicWriter) SetFieldOrder(fields []string) {
+	if fo, ok := aw.inner.(FieldOrderer); ok {
+		fo.SetFieldOrder(fields)
+	}
+}
+
+// Write passes the records to the wrapped writer, targeting a temporary file next to filePath.
+// The temporary file is created on the first call; later calls must use the same filePath.
+func (aw *AtomicWriter) Write(records []map[string]interface{}, filePath string) error {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	if aw.tempPath == "" {
+		dir := filepath.Dir(filePath)
+		if dir != "." && dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("AtomicWriter failed to create directory for '%s': %w", filePath, err)
+			}
+		}
+		// Keep the final name as the suffix so writers that check the file extension (e.g., XLSX) still accept it.
+		f, err := os.CreateTemp(dir, ".tmp-*-"+filepath.Base(filePath))
+		if err != nil {
+			return fmt.Errorf("AtomicWriter failed to create temporary file for '%s': %w", filePath, err)
+		}
+		if err := f.Close(); err != nil {
+			_ = os.Remove(f.Name())
+			return fmt.Errorf("AtomicWriter failed to close temporary file for '%s': %w", filePath, err)
+		}
+		aw.finalPath = filePath
+		aw.tempPath = f.Name()
+		aw.failed = false
+		logging.Logf(logging.Debug, "AtomicWriter writing '%s' via temporary file '%s'", filePath, aw.tempPath)
+	} else if aw.finalPath != filePath {
+		return fmt.Errorf("AtomicWriter already writing '%s', cannot write to different file '%s'; close the writer first", aw.finalPath, filePath)
+	}
+
+	if err := aw.inner.Write(records, aw.tempPath); err != nil {
+		aw.failed = true
+		return err
+	}
+	return nil
+}
+
+// Close closes the wrapped writer and then renames the temporary file to the final path. After a failed
+// Write or close, the temporary file is removed and no final file is produced. It is safe to call multiple times.
+func (aw *AtomicWriter) Close() error {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	closeErr := aw.inner.Close()
+	if aw.tempPath == "" {
+		return closeErr
+	}
+	tempPath, finalPath := aw.tempPath, aw.finalPath
+	aw.tempPath = ""
+
+	if closeErr != nil || aw.failed {
+		if err := os.Remove(tempPath); err != nil && !os.IsNotExist(err) {
+			logging.Logf(logging.Warning, "AtomicWriter failed to remove temporary file '%s': %v", tempPath, err)
+		}
+		logging.Logf(logging.Warning, "AtomicWriter discarded incomplete output; '%s' was not written", finalPath)
+		return closeErr
+	}
+
+	// os.CreateTemp uses mode 0600; give the output the same permissions the writers use for new files.
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		logging.Logf(logging.Warning, "AtomicWriter failed to set permissions on '%s': %v", tempPath, err)
+	}
+	if err := os.Rename(tempPath, finalPath); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("AtomicWriter failed to move temporary file into place at '%s': %w", finalPath, err)
+	}
+	logging.Logf(logging.Debug, "AtomicWriter moved completed output into place: %s", finalPath)
+	return nil
+}
@@ -0,0 +1,162 @@
+package io
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingOutputWriter is an OutputWriter that writes partial content and then fails.
+type failingOutputWriter struct {
+	closed bool
+}
+
+func (fw *failingOutputWriter) Write(records []map[string]interface{}, filePath string) error {
+	if err := os.WriteFile(filePath, []byte("partial"), 0644); err != nil {
+		return err
+	}
+	return errors.New("simulated write failure")
+}
+
+func (fw *failingOutputWriter) Close() error {
+	fw.closed = true
+	return nil
+}
+
+// dirEntryNames lists the names of the entries in dir.
+func dirEntryNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir(%s) failed: %v", dir, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestAtomicWriter(t *testing.T) {
+	records := []map[string]interface{}{{"id": 1, "name": "Alice"}, {"id": 2, "name": "Bob"}}
+
+	t.Run("final file appears only after Close", func(t *testing.T) {
+		dir := t.TempDir()
+		finalPath := filepath.Join(dir, "out", "result.csv")
+		csvWriter, err := NewCSVWriter(",")
+		if err != nil {
+			t.Fatalf("NewCSVWriter failed: %v", err)
+		}
+		writer := NewAtomicWriter(csvWriter)
+		writer.SetFieldOrder([]string{"name"})
+
+		if err := writer.Write(records, finalPath); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if _, err := os.Stat(finalPath); !os.IsNotExist(err) {
+			t.Fatalf("final file exists before Close (stat err: %v)", err)
+		}
+		names := dirEntryNames(t, filepath.Dir(finalPath))
+		if len(names) != 1 || !strings.HasPrefix(names[0], ".tmp-") || !strings.HasSuffix(names[0], "result.csv") {
+			t.Fatalf("expected one hidden temporary file before Close, got %v", names)
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		data, err := os.ReadFile(finalPath)
+		if err != nil {
+			t.Fatalf("final file missing after Close: %v", err)
+		}
+		if want := "name,id\nAlice,1\nBob,2\n"; string(data) != want {
+			t.Errorf("final content = %q, want %q", string(data), want)
+		}
+		if names := dirEntryNames(t, filepath.Dir(finalPath)); len(names) != 1 || names[0] != "result.csv" {
+			t.Errorf("expected only the final file after Close, got %v", names)
+		}
+		if err := writer.Close(); err != nil {
+			t.Errorf("second Close returned error: %v", err)
+		}
+	})
+
+	t.Run("replaces existing file on success", func(t *testing.T) {
+		finalPath := createTempFile(t, "old content", "existing_*.json")
+		writer := NewAtomicWriter(&JSONWriter{})
+		if err := writer.Write(records, finalPath); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if data, _ := os.ReadFile(finalPath); string(data) != "old content" {
+			t.Errorf("existing file changed before Close: %q", string(data))
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		got, err := (&JSONReader{}).Read(finalPath)
+		if err != nil {
+			t.Fatalf("reading final file failed: %v", err)
+		}
+		if len(got) != len(records) {
+			t.Errorf("final file has %d records, want %d", len(got), len(records))
+		}
+		if info, err := os.Stat(finalPath); err == nil && info.Mode().Perm() != 0644 {
+			t.Errorf("final file mode = %v, want 0644", info.Mode().Perm())
+		}
+	})
+
+	t.Run("failed write leaves no final file", func(t *testing.T) {
+		dir := t.TempDir()
+		finalPath := filepath.Join(dir, "result.xml")
+		inner := &failingOutputWriter{}
+		writer := NewAtomicWriter(inner)
+		if err := writer.Write(records, finalPath); err == nil || !strings.Contains(err.Error(), "simulated write failure") {
+			t.Fatalf("expected simulated write failure, got %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if !inner.closed {
+			t.Error("wrapped writer was not closed")
+		}
+		if names := dirEntryNames(t, dir); len(names) != 0 {
+			t.Errorf("expected no files after failed write, got %v", names)
+		}
+	})
+
+	t.Run("failed write keeps existing file", func(t *testing.T) {
+		finalPath := createTempFile(t, "previous run", "existing_*.xml")
+		writer := NewAtomicWriter(&failingOutputWriter{})
+		_ = writer.Write(records, finalPath)
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if data, _ := os.ReadFile(finalPath); string(data) != "previous run" {
+			t.Errorf("existing file content = %q, want it unchanged", string(data))
+		}
+		if names := dirEntryNames(t, filepath.Dir(finalPath)); len(names) != 1 {
+			t.Errorf("expected only the existing file, got %v", names)
+		}
+	})
+
+	t.Run("close without write is a no-op", func(t *testing.T) {
+		writer := NewAtomicWriter(&JSONWriter{})
+		if err := writer.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+	})
+
+	t.Run("different file path rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		csvWriter, _ := NewCSVWriter(",")
+		writer := NewAtomicWriter(csvWriter)
+		defer writer.Close()
+		if err := writer.Write(records, filepath.Join(dir, "a.csv")); err != nil {
+			t.Fatalf("first Write failed: %v", err)
+		}
+		err := writer.Write(records, filepath.Join(dir, "b.csv"))
+		if err == nil || !strings.Contains(err.Error(), "cannot write to different file") {
+			t.Errorf("expected different file error, got %v", err)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package io
 
 import (
+	"bufio"
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,6 +13,7 @@ import (
 	"sync"
 	"unicode/utf8"
 
+	"etl-tool/internal/config"
 	"etl-tool/internal/logging"
 )
 
@@ -19,6 +22,15 @@ import (
 type CSVReader struct {
 	Delimiter   rune // Field delimiter (e.g., ',', '\t').
 	CommentChar rune // Character indicating a comment line (e.g., '#'). 0 disables.
+	// multiCharDelimiter, if set, splits lines literally on this string instead of using encoding/csv.
+	multiCharDelimiter string
+	skipHeaderRows     int        // Parsed rows to discard before the header.
+	skipFooterRows     int        // Trailing parsed rows to discard.
+	maxRows            int        // Maximum data rows to keep (0 = unlimited).
+	trimFields         bool       // Trim surrounding whitespace from every cell.
+	treatEmptyAsNull   bool       // Read empty cells as nil instead of "".
+	duplicateHeader    string     // Repeated header handling: "last" (default), "first", "error", or "suffix".
+	open               fileOpener // Opens the input file; nil means os.Open (see GzipReader).
 }
 
 // NewCSVReader creates a CSVReader with options derived from SourceConfig.
@@ -50,27 +62,37 @@ func NewCSVReader(delimiter, commentChar string) (*CSVReader, error) {
 func (cr *CSVReader) Read(filePath string) ([]map[string]interface{}, error) {
 	logging.Logf(logging.Debug, "CSVReader reading file: %s (Delimiter: '%c', Comment: '%c')", filePath, cr.Delimiter, cr.CommentChar)
 
-	f, err := os.Open(filePath)
+	f, err := openInput(cr.open, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("CSVReader failed to open file '%s': %w", filePath, err)
 	}
 	defer f.Close()
 
-	reader := csv.NewReader(f)
-	reader.Comma = cr.Delimiter
-	if cr.CommentChar != 0 {
-		reader.Comment = cr.CommentChar
-	}
-	reader.FieldsPerRecord = -1 // Allow variable number of fields initially
+	var allRows [][]string
+	if cr.multiCharDelimiter != "" {
+		allRows, err = cr.readMultiCharRows(f)
+		if err != nil {
+			return nil, fmt.Errorf("CSVReader failed to read rows from '%s': %w", filePath, err)
+		}
+	} else {
+		reader := csv.NewReader(f)
+		reader.Comma = cr.Delimiter
+		if cr.CommentChar != 0 {
+			reader.Comment = cr.CommentChar
+		}
+		reader.FieldsPerRecord = -1 // Allow variable number of fields initially
 
-	allRows, err := reader.ReadAll()
-	if err != nil {
-		if parseErr, ok := err.(*csv.ParseError); ok {
-			return nil, fmt.Errorf("CSVReader parse error in '%s' on line %d, column %d: %w", filePath, parseErr.Line, parseErr.Column, parseErr.Err)
+		allRows, err = reader.ReadAll()
+		if err != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				return nil, fmt.Errorf("CSVReader parse error in '%s' on line %d, column %d: %w", filePath, parseErr.Line, parseErr.Column, parseErr.Err)
+			}
+			return nil, fmt.Errorf("CSVReader failed to read rows from '%s': %w", filePath, err)
 		}
-		return nil, fmt.Errorf("CSVReader failed to read rows from '%s': %w", filePath, err)
 	}
 
+	allRows = trimSourceRows(allRows, cr.skipHeaderRows, cr.skipFooterRows, cr.maxRows, filePath)
+
 	// Ensure an empty, non-nil slice is returned if no header or no data rows exist
 	if len(allRows) < 2 { // Changed condition to < 2 to handle header-only case
 		if len(allRows) == 0 {
@@ -86,6 +108,11 @@ func (cr *CSVReader) Read(filePath string) ([]map[string]interface{}, error) {
 	headerSet := make(map[string]int) // Stores count of each header
 	validHeaderIndices := make(map[int]string) // Map column index to valid header name
 
+	originalHeaders := make(map[string]bool, numHeaders) // Used by "suffix" mode to avoid colliding with real headers
+	for _, h := range headers {
+		originalHeaders[strings.TrimSpace(h)] = true
+	}
+
 	for i, h := range headers {
 		header := strings.TrimSpace(h)
 		if header == "" {
@@ -94,7 +121,27 @@ func (cr *CSVReader) Read(filePath string) ([]map[string]interface{}, error) {
 		}
 		headerSet[header]++
 		if headerSet[header] > 1 {
-			logging.Logf(logging.Warning, "CSVReader: Duplicate header '%s' found at column %d in file '%s'; data for this header name will represent the last occurring column", header, i+1, filePath)
+			switch strings.ToLower(cr.duplicateHeader) {
+			case config.DuplicateHeaderError:
+				return nil, fmt.Errorf("CSVReader found duplicate header '%s' at column %d in '%s'", header, i+1, filePath)
+			case config.DuplicateHeaderFirst:
+				logging.Logf(logging.Warning, "CSVReader: Duplicate header '%s' found at column %d in file '%s'; column ignored (first occurrence wins)", header, i+1, filePath)
+				continue
+			case config.DuplicateHeaderSuffix:
+				renamed := header
+				for n := headerSet[header]; ; n++ {
+					renamed = fmt.Sprintf("%s_%d", header, n)
+					if !originalHeaders[renamed] && headerSet[renamed] == 0 {
+						break
+					}
+				}
+				logging.Logf(logging.Info, "CSVReader: Duplicate header '%s' found at column %d in file '%s'; renamed to '%s'", header, i+1, filePath, renamed)
+				headerSet[renamed]++
+				validHeaderIndices[i] = renamed
+				continue
+			default:
+				logging.Logf(logging.Warning, "CSVReader: Duplicate header '%s' found at column %d in file '%s'; data for this header name will represent the last occurring column", header, i+1, filePath)
+			}
 		}
 		validHeaderIndices[i] = header // Store mapping from original index to valid header
 	}
@@ -117,14 +164,14 @@ func (cr *CSVReader) Read(filePath string) ([]map[string]interface{}, error) {
 		for colIdx, value := range row {
 			// Use only columns that had a valid header
 			if headerName, ok := validHeaderIndices[colIdx]; ok {
-				rec[headerName] = value // Assign value using the valid header name
+				rec[headerName] = cr.cellValue(value) // Assign value using the valid header name
 			}
 		}
 		// Ensure all valid headers (from headerSet keys) are present, even if row was short
 		// Note: Skipping rows with incorrect field count makes this less critical, but good practice
 		for header := range headerSet {
 			if _, exists := rec[header]; !exists && header != "" { // Ensure key exists, skip adding empty header key
-				rec[header] = ""
+				rec[header] = cr.cellValue("")
 			}
 		}
 		records = append(records, rec)
@@ -134,16 +181,53 @@ func (cr *CSVReader) Read(filePath string) ([]map[string]interface{}, error) {
 	return records, nil
 }
 
+// cellValue applies the trim_fields and treat_empty_as_null options to a raw cell.
+func (cr *CSVReader) cellValue(raw string) interface{} {
+	if cr.trimFields {
+		raw = strings.TrimSpace(raw)
+	}
+	if cr.treatEmptyAsNull && raw == "" {
+		return nil
+	}
+	return raw
+}
+
+// readMultiCharRows splits each line on the multi-character delimiter without any quote handling.
+// Blank lines and lines starting with the comment character are skipped, mirroring encoding/csv.
+func (cr *CSVReader) readMultiCharRows(r io.Reader) ([][]string, error) {
+	logging.Logf(logging.Debug, "CSVReader: Using multi-character delimiter '%s' (quoting not supported)", cr.multiCharDelimiter)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	var rows [][]string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if cr.CommentChar != 0 && strings.HasPrefix(line, string(cr.CommentChar)) {
+			continue
+		}
+		rows = append(rows, strings.Split(line, cr.multiCharDelimiter))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // CSVWriter implements the OutputWriter interface for CSV files.
 // It buffers writes and requires Close() to be called to finalize the file.
 type CSVWriter struct {
-	Delimiter     rune // Field delimiter to use for writing.
-	filePath      string
-	mu            sync.Mutex
-	file          *os.File
-	writer        *csv.Writer
-	headers       []string // Store headers determined after first write batch
-	headerWritten bool
+	Delimiter      rune // Field delimiter to use for writing.
+	filePath       string
+	mu             sync.Mutex
+	file           io.WriteCloser
+	create         fileCreator // Creates the output file; nil means a plain file (see GzipWriter)
+	writer         *csv.Writer
+	headers        []string // Store headers determined after first write batch
+	headerWritten  bool
+	fieldOrder     []string // Optional leading header order (see SetFieldOrder)
+	floatPrecision *int     // Optional fixed number of decimals for float values (see float_precision)
 }
 
 // NewCSVWriter creates a CSVWriter, deferring file opening until the first Write call.
@@ -161,6 +245,13 @@ func NewCSVWriter(delimiter string) (*CSVWriter, error) {
 	}, nil
 }
 
+// SetFieldOrder implements FieldOrderer. It must be called before the first non-empty Write.
+func (cw *CSVWriter) SetFieldOrder(fields []string) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.fieldOrder = fields
+}
+
 // Write saves the provided records to the CSV file.
 // The file is opened on the first call to Write. Headers are determined from *all* records
 // in the first batch and written once. Subsequent calls use the initially determined headers.
@@ -184,7 +275,7 @@ func (cw *CSVWriter) Write(records []map[string]interface{}, filePath string) er
 		}
 
 		// Create or truncate the file (even if records slice is empty on first call)
-		f, err := os.Create(filePath)
+		f, err := createOutput(cw.create, filePath)
 		if err != nil {
 			return fmt.Errorf("CSVWriter failed to create file '%s': %w", filePath, err)
 		}
@@ -223,12 +314,8 @@ func (cw *CSVWriter) Write(records []map[string]interface{}, filePath string) er
 				headerSet[k] = struct{}{}
 			}
 		}
-		// Convert set to slice and sort for consistent order
-		cw.headers = make([]string, 0, len(headerSet))
-		for k := range headerSet {
-			cw.headers = append(cw.headers, k)
-		}
-		sort.Strings(cw.headers)
+		// Apply the configured field order, then sort the rest for consistent order
+		cw.headers = orderFields(headerSet, cw.fieldOrder)
 
 		logging.Logf(logging.Debug, "CSVWriter determined headers from first batch: %v", cw.headers)
 		if err := cw.writer.Write(cw.headers); err != nil {
@@ -250,7 +337,11 @@ func (cw *CSVWriter) Write(records []map[string]interface{}, filePath string) er
 		for j, header := range cw.headers {
 			// Lookup value based on established header order
 			if val, ok := rec[header]; ok && val != nil {
-				row[j] = fmt.Sprintf("%v", val) // Use fmt.Sprintf for consistent string conversion
+				if formatted, isFloat := formatFixedFloat(val, cw.floatPrecision); isFloat {
+					row[j] = formatted
+				} else {
+					row[j] = fmt.Sprintf("%v", val) // Use fmt.Sprintf for consistent string conversion
+				}
 			} else {
 				row[j] = "" // Empty string for nil or missing values
 			}
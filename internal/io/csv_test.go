@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"testing"
+
+	"etl-tool/internal/config"
 )
 
 // --- Test Helpers ---
@@ -282,6 +284,186 @@ func TestCSVReader_Read(t *testing.T) {
 
 // --- Test CSVWriter ---
 
+// TestCSVReader_MultiCharDelimiter verifies lines are split literally on a multi-character delimiter.
+func TestCSVReader_MultiCharDelimiter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		delimiter   string
+		commentChar string
+		content     string
+		want        []map[string]interface{}
+	}{
+		{
+			name:      "Double pipe",
+			delimiter: "||",
+			content:   "id||name||note\r\n1||Alice||a,b\r\n2||Bob||\r\n",
+			want:      []map[string]interface{}{{"id": "1", "name": "Alice", "note": "a,b"}, {"id": "2", "name": "Bob", "note": ""}},
+		},
+		{
+			name:        "Tilde pipe with comments and blank lines",
+			delimiter:   "~|~",
+			commentChar: "#",
+			content:     "# exported feed\nid~|~name\n\n1~|~\"Quoted\"\n# trailer\n",
+			want:        []map[string]interface{}{{"id": "1", "name": "\"Quoted\""}}, // Quotes are kept literally
+		},
+		{
+			name:      "Row with wrong field count skipped",
+			delimiter: "||",
+			content:   "a||b\n1||2||3\n4||5\n",
+			want:      []map[string]interface{}{{"a": "4", "b": "5"}},
+		},
+		{
+			name:      "Header only",
+			delimiter: "||",
+			content:   "a||b\n",
+			want:      []map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempCSV(t, tc.content)
+			reader, err := NewInputReader(config.SourceConfig{Type: "csv", File: filePath, CommentChar: tc.commentChar, MultiCharDelimiter: tc.delimiter}, "")
+			if err != nil {
+				t.Fatalf("NewInputReader() error = %v", err)
+			}
+			got, err := reader.Read(filePath)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, got, tc.want)
+		})
+	}
+}
+
+// TestCSVReader_CellCleanup verifies the trim_fields and treat_empty_as_null source options.
+func TestCSVReader_CellCleanup(t *testing.T) {
+	content := "id,name,note\n1,  Alice  ,\n2,Bob,   \n3, ,kept \n"
+	testCases := []struct {
+		name             string
+		trimFields       bool
+		treatEmptyAsNull bool
+		multiChar        string
+		want             []map[string]interface{}
+	}{
+		{
+			name: "Defaults keep raw cells",
+			want: []map[string]interface{}{{"id": "1", "name": "  Alice  ", "note": ""}, {"id": "2", "name": "Bob", "note": "   "}, {"id": "3", "name": " ", "note": "kept "}},
+		},
+		{
+			name:       "Trim only",
+			trimFields: true,
+			want:       []map[string]interface{}{{"id": "1", "name": "Alice", "note": ""}, {"id": "2", "name": "Bob", "note": ""}, {"id": "3", "name": "", "note": "kept"}},
+		},
+		{
+			name:             "Empty as null only",
+			treatEmptyAsNull: true,
+			want:             []map[string]interface{}{{"id": "1", "name": "  Alice  ", "note": nil}, {"id": "2", "name": "Bob", "note": "   "}, {"id": "3", "name": " ", "note": "kept "}},
+		},
+		{
+			name:             "Trim and empty as null",
+			trimFields:       true,
+			treatEmptyAsNull: true,
+			want:             []map[string]interface{}{{"id": "1", "name": "Alice", "note": nil}, {"id": "2", "name": "Bob", "note": nil}, {"id": "3", "name": nil, "note": "kept"}},
+		},
+		{
+			name:             "Multi-char delimiter path",
+			trimFields:       true,
+			treatEmptyAsNull: true,
+			multiChar:        "||",
+			want:             []map[string]interface{}{{"id": "1", "name": "Alice", "note": nil}, {"id": "2", "name": "Bob", "note": nil}, {"id": "3", "name": nil, "note": "kept"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := content
+			if tc.multiChar != "" {
+				data = strings.ReplaceAll(content, ",", tc.multiChar)
+			}
+			filePath := createTempCSV(t, data)
+			reader, err := NewInputReader(config.SourceConfig{Type: "csv", File: filePath, MultiCharDelimiter: tc.multiChar, TrimFields: tc.trimFields, TreatEmptyAsNull: tc.treatEmptyAsNull}, "")
+			if err != nil {
+				t.Fatalf("NewInputReader() error = %v", err)
+			}
+			got, err := reader.Read(filePath)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, got, tc.want)
+		})
+	}
+}
+
+// TestCSVReader_RowWindow verifies skip_header_rows, skip_footer_rows, and max_rows trimming.
+func TestCSVReader_RowWindow(t *testing.T) {
+	content := "Sales Export generated 2024-01-01\nid,amount\n1,10\n2,20\n3,30\nTOTAL,60\n"
+	testCases := []struct {
+		name                           string
+		skipHeader, skipFooter, maxRow int
+		want                           []map[string]interface{}
+	}{
+		{"Banner and footer", 1, 1, 0, []map[string]interface{}{{"id": "1", "amount": "10"}, {"id": "2", "amount": "20"}, {"id": "3", "amount": "30"}}},
+		{"Banner footer and row cap", 1, 1, 2, []map[string]interface{}{{"id": "1", "amount": "10"}, {"id": "2", "amount": "20"}}},
+		{"Row cap larger than data", 1, 1, 10, []map[string]interface{}{{"id": "1", "amount": "10"}, {"id": "2", "amount": "20"}, {"id": "3", "amount": "30"}}},
+		{"Footer skips all data", 1, 5, 0, []map[string]interface{}{}},
+		{"Banner skips everything", 10, 0, 0, []map[string]interface{}{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempCSV(t, content)
+			reader, err := NewInputReader(config.SourceConfig{Type: "csv", File: filePath, SkipHeaderRows: tc.skipHeader, SkipFooterRows: tc.skipFooter, MaxRows: tc.maxRow}, "")
+			if err != nil {
+				t.Fatalf("NewInputReader() error = %v", err)
+			}
+			got, err := reader.Read(filePath)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, got, tc.want)
+		})
+	}
+}
+
+// TestCSVReader_DuplicateHeader verifies each duplicate_header mode against a file with a repeated header name.
+func TestCSVReader_DuplicateHeader(t *testing.T) {
+	content := "name,id,name,name_2,name\nA,1,B,C,D\n"
+	testCases := []struct {
+		name       string
+		mode       string
+		want       []map[string]interface{}
+		wantErrMsg string
+	}{
+		{name: "Default (last wins)", mode: "", want: []map[string]interface{}{{"name": "D", "id": "1", "name_2": "C"}}},
+		{name: "Last wins", mode: config.DuplicateHeaderLast, want: []map[string]interface{}{{"name": "D", "id": "1", "name_2": "C"}}},
+		{name: "First wins", mode: config.DuplicateHeaderFirst, want: []map[string]interface{}{{"name": "A", "id": "1", "name_2": "C"}}},
+		{name: "Suffix avoids existing headers", mode: config.DuplicateHeaderSuffix, want: []map[string]interface{}{{"name": "A", "id": "1", "name_3": "B", "name_2": "C", "name_4": "D"}}},
+		{name: "Error", mode: config.DuplicateHeaderError, wantErrMsg: "duplicate header 'name' at column 3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempCSV(t, content)
+			reader, err := NewInputReader(config.SourceConfig{Type: "csv", File: filePath, DuplicateHeader: tc.mode}, "")
+			if err != nil {
+				t.Fatalf("NewInputReader() error = %v", err)
+			}
+			got, err := reader.Read(filePath)
+			if tc.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrMsg) {
+					t.Fatalf("Read() error = %v, want error containing %q", err, tc.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, got, tc.want)
+		})
+	}
+}
+
 func TestNewCSVWriter(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -583,6 +765,60 @@ func TestCSVWriter_WriteAndClose(t *testing.T) {
 
 // --- Test CSVErrorWriter ---
 
+// TestCSVWriter_SetFieldOrder verifies configured fields lead the header in order, with others sorted after.
+func TestCSVWriter_SetFieldOrder(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "ordered.csv")
+	writer, err := NewCSVWriter(",")
+	if err != nil {
+		t.Fatalf("NewCSVWriter() error = %v", err)
+	}
+	var _ FieldOrderer = writer
+	writer.SetFieldOrder([]string{"zeta", "id", "absent"})
+	records := []map[string]interface{}{{"id": 1, "zeta": "z", "beta": "b", "alpha": "a"}}
+	if err := writer.Write(records, filePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	want := [][]string{{"zeta", "id", "alpha", "beta"}, {"z", "1", "a", "b"}}
+	if got := readCSVFile(t, filePath, ','); !reflect.DeepEqual(got, want) {
+		t.Errorf("CSV content = %v, want %v", got, want)
+	}
+}
+
+// TestCSVWriter_FloatPrecision verifies float_precision formats floats with fixed decimals and leaves other values alone.
+func TestCSVWriter_FloatPrecision(t *testing.T) {
+	records := []map[string]interface{}{{"pi": 3.14159, "small": float32(0.5), "n": 7, "s": "2.71828"}}
+	two := 2
+	testCases := []struct {
+		name      string
+		precision *int
+		want      [][]string
+	}{
+		{name: "Unset keeps default", precision: nil, want: [][]string{{"n", "pi", "s", "small"}, {"7", "3.14159", "2.71828", "0.5"}}},
+		{name: "Precision 2", precision: &two, want: [][]string{{"n", "pi", "s", "small"}, {"7", "3.14", "2.71828", "0.50"}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "floats.csv")
+			writer, err := NewOutputWriter(config.DestinationConfig{Type: "csv", File: filePath, FloatPrecision: tc.precision}, "")
+			if err != nil {
+				t.Fatalf("NewOutputWriter() error = %v", err)
+			}
+			if err := writer.Write(records, filePath); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := readCSVFile(t, filePath, ','); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("CSV content = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestNewCSVErrorWriter(t *testing.T) {
 	t.Run("Successful creation", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -4,6 +4,7 @@ package io
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"etl-tool/internal/config"
 	"etl-tool/internal/logging"
@@ -14,9 +15,19 @@ func NewInputReader(cfg config.SourceConfig, dbConnStr string) (InputReader, err
 	sourceType := strings.ToLower(cfg.Type)
 	logging.Logf(logging.Debug, "Creating input reader for type: %s", sourceType)
 
+	if sourceType != config.SourceTypePostgres && sourceType != config.SourceTypeHTTP && useGzip(cfg.File, cfg.Compression) {
+		plainCfg := cfg
+		plainCfg.Compression = config.CompressionNone
+		reader, err := NewInputReader(plainCfg, dbConnStr)
+		if err != nil {
+			return nil, err
+		}
+		return NewGzipReader(reader, cfg.Compression), nil
+	}
+
 	switch sourceType {
 	case config.SourceTypeJSON:
-		return &JSONReader{}, nil
+		return &JSONReader{lines: strings.EqualFold(cfg.Format, config.JSONFormatLines), recordsPath: cfg.RecordsPath}, nil
 	case config.SourceTypeCSV:
 		// Capture and return potential error from NewCSVReader
 		reader, err := NewCSVReader(cfg.Delimiter, cfg.CommentChar)
@@ -24,16 +35,27 @@ func NewInputReader(cfg config.SourceConfig, dbConnStr string) (InputReader, err
 			// Wrap the error for context
 			return nil, fmt.Errorf("failed to create CSV reader: %w", err)
 		}
+		reader.multiCharDelimiter = cfg.MultiCharDelimiter
+		reader.trimFields, reader.treatEmptyAsNull = cfg.TrimFields, cfg.TreatEmptyAsNull
+		reader.duplicateHeader = cfg.DuplicateHeader
+		reader.skipHeaderRows, reader.skipFooterRows, reader.maxRows = cfg.SkipHeaderRows, cfg.SkipFooterRows, cfg.MaxRows
 		return reader, nil // Return the reader only if no error occurred
 	case config.SourceTypeXLSX:
 		// Assuming NewXLSXReader doesn't return errors currently,
 		// but could be modified similarly if it did.
-		return NewXLSXReader(cfg.SheetName, cfg.SheetIndex), nil
+		reader := NewXLSXReader(cfg.SheetName, cfg.SheetIndex)
+		reader.typedCells = cfg.TypedCells
+		reader.skipHeaderRows, reader.skipFooterRows, reader.maxRows = cfg.SkipHeaderRows, cfg.SkipFooterRows, cfg.MaxRows
+		return reader, nil
 	case config.SourceTypeXML:
 		// Assuming NewXMLReader doesn't return errors currently.
-		return NewXMLReader(cfg.XMLRecordTag), nil
+		reader := NewXMLReader(cfg.XMLRecordTag)
+		reader.attrFilter = cfg.XMLAttributeFilter
+		return reader, nil
 	case config.SourceTypeYAML: // Added YAML case
 		return &YAMLReader{}, nil
+	case config.SourceTypeTOML:
+		return &TOMLReader{}, nil
 	case config.SourceTypePostgres:
 		if dbConnStr == "" {
 			return nil, fmt.Errorf("database connection string (-db or DB_CREDENTIALS) is required for source type 'postgres'")
@@ -43,6 +65,18 @@ func NewInputReader(cfg config.SourceConfig, dbConnStr string) (InputReader, err
 		}
 		// Assuming NewPostgresReader doesn't return errors currently.
 		return NewPostgresReader(dbConnStr, cfg.Query), nil
+	case config.SourceTypeHTTP:
+		var timeout time.Duration
+		if cfg.Timeout != "" {
+			parsed, err := time.ParseDuration(cfg.Timeout)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid timeout '%s' in source config for type 'http'", cfg.Timeout)
+			}
+			timeout = parsed
+		}
+		reader := NewHTTPReader(cfg.Method, cfg.Headers, timeout)
+		reader.recordsPath = cfg.RecordsPath
+		return reader, nil
 	default:
 		return nil, fmt.Errorf("unsupported source type '%s'", cfg.Type)
 	}
@@ -53,6 +87,27 @@ func NewOutputWriter(cfg config.DestinationConfig, dbConnStr string) (OutputWrit
 	destType := strings.ToLower(cfg.Type)
 	logging.Logf(logging.Debug, "Creating output writer for type: %s", destType)
 
+	if cfg.Atomic && destType != config.DestinationTypePostgres {
+		plainCfg := cfg
+		plainCfg.Atomic = false
+		writer, err := NewOutputWriter(plainCfg, dbConnStr)
+		if err != nil {
+			return nil, err
+		}
+		return NewAtomicWriter(writer), nil
+	}
+
+	// Compression wraps inside Atomic, so the compressed file is what gets renamed into place.
+	if destType != config.DestinationTypePostgres && useGzip(cfg.File, cfg.Compression) {
+		plainCfg := cfg
+		plainCfg.Compression = config.CompressionNone
+		writer, err := NewOutputWriter(plainCfg, dbConnStr)
+		if err != nil {
+			return nil, err
+		}
+		return NewGzipWriter(writer, cfg.Compression), nil
+	}
+
 	switch destType {
 	case config.DestinationTypePostgres:
 		if dbConnStr == "" {
@@ -62,7 +117,10 @@ func NewOutputWriter(cfg config.DestinationConfig, dbConnStr string) (OutputWrit
 			return nil, fmt.Errorf("target_table is required in destination config for type 'postgres'")
 		}
 		// Assuming NewPostgresWriter doesn't return errors currently.
-		return NewPostgresWriter(dbConnStr, cfg.TargetTable, cfg.Loader), nil
+		writer := NewPostgresWriter(dbConnStr, cfg.TargetTable, cfg.Loader)
+		writer.verifyCount = cfg.VerifyCount
+		writer.truncate = cfg.TruncateToColumnLength
+		return writer, nil
 	case config.DestinationTypeCSV:
 		// Capture and return potential error from NewCSVWriter
 		writer, err := NewCSVWriter(cfg.Delimiter)
@@ -70,15 +128,22 @@ func NewOutputWriter(cfg config.DestinationConfig, dbConnStr string) (OutputWrit
 			// Wrap the error for context
 			return nil, fmt.Errorf("failed to create CSV writer: %w", err)
 		}
+		writer.floatPrecision = cfg.FloatPrecision
 		return writer, nil // Return the writer only if no error occurred
 	case config.DestinationTypeXLSX:
 		// Assuming NewXLSXWriter doesn't return errors currently.
-		return NewXLSXWriter(cfg.SheetName), nil
+		writer := NewXLSXWriter(cfg.SheetName)
+		writer.boldHeader = cfg.BoldHeader
+		writer.freezeHeader = cfg.FreezeHeader
+		writer.numberFormats = cfg.NumberFormat
+		return writer, nil
 	case config.DestinationTypeXML:
 		// Assuming NewXMLWriter doesn't return errors currently.
-		return NewXMLWriter(cfg.XMLRecordTag, cfg.XMLRootTag), nil
+		writer := NewXMLWriter(cfg.XMLRecordTag, cfg.XMLRootTag)
+		writer.attributeFields = cfg.AttributeFields
+		return writer, nil
 	case config.DestinationTypeJSON:
-		return &JSONWriter{}, nil
+		return &JSONWriter{floatPrecision: cfg.FloatPrecision, lines: strings.EqualFold(cfg.Format, config.JSONFormatLines)}, nil
 	case config.DestinationTypeYAML: // Added YAML case
 		return &YAMLWriter{}, nil
 	default:
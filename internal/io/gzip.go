@@ -0,0 +1,188 @@
+package io
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"etl-tool/internal/config"
+	"etl-tool/internal/logging"
+)
+
+// useGzip reports whether filePath should be read or written gzip-compressed: always for compression "gzip",
+// never for "none", and otherwise (auto) when the path ends in ".gz".
+func useGzip(filePath, compression string) bool {
+	switch strings.ToLower(compression) {
+	case config.CompressionGzip:
+		return true
+	case config.CompressionNone:
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(filePath), ".gz")
+}
+
+// GzipReader wraps a file-based InputReader to read gzip-compressed files. The wrapped reader opens its input
+// through a gzip.Reader, so the file is decompressed as it is read and nothing is staged on disk. Files that
+// are not compressed (see useGzip) are read directly.
+type GzipReader struct {
+	inner       InputReader
+	compression string
+}
+
+// NewGzipReader wraps inner; compression is the source's Compression setting ("", "gzip", or "none").
+func NewGzipReader(inner InputReader, compression string) *GzipReader {
+	return &GzipReader{inner: inner, compression: compression}
+}
+
+// Read returns the records the wrapped reader reads from filePath, decompressing it when needed.
+func (gr *GzipReader) Read(filePath string) ([]map[string]interface{}, error) {
+	if !setFileOpener(gr.inner, gr.open) {
+		return nil, fmt.Errorf("GzipReader cannot decompress input for reader type %T", gr.inner)
+	}
+	return gr.inner.Read(filePath)
+}
+
+// open opens filePath, decompressing it through a gzip.Reader when it is compressed.
+func (gr *GzipReader) open(filePath string) (io.ReadCloser, error) {
+	if !useGzip(filePath, gr.compression) {
+		return openInput(nil, filePath)
+	}
+	logging.Logf(logging.Debug, "GzipReader decompressing file: %s", filePath)
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("GzipReader failed to read gzip header of '%s': %w", filePath, err)
+	}
+	return &gzipReadCloser{Reader: zr, file: f}, nil
+}
+
+// gzipReadCloser reads through a gzip.Reader and closes the underlying file along with it.
+type gzipReadCloser struct {
+	*gzip.Reader
+	file io.Closer
+}
+
+// Close closes the gzip.Reader and then the file.
+func (rc *gzipReadCloser) Close() error {
+	err := rc.Reader.Close()
+	if closeErr := rc.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// GzipWriter wraps a file-based OutputWriter to write gzip-compressed files. The wrapped writer creates its
+// output through a gzip.Writer, so records are compressed as they are written and nothing is staged on disk.
+// Files that should not be compressed (see useGzip) are written directly.
+type GzipWriter struct {
+	inner       OutputWriter
+	compression string
+}
+
+// NewGzipWriter wraps inner; compression is the destination's Compression setting ("", "gzip", or "none").
+func NewGzipWriter(inner OutputWriter, compression string) *GzipWriter {
+	return &GzipWriter{inner: inner, compression: compression}
+}
+
+// SetFieldOrder implements FieldOrderer by forwarding to the wrapped writer, if it supports field ordering.
+func (gw *GzipWriter) SetFieldOrder(fields []string) {
+	if fo, ok := gw.inner.(FieldOrderer); ok {
+		fo.SetFieldOrder(fields)
+	}
+}
+
+// Write passes the records to the wrapped writer, which compresses filePath when needed.
+func (gw *GzipWriter) Write(records []map[string]interface{}, filePath string) error {
+	if !setFileCreator(gw.inner, gw.create) {
+		return fmt.Errorf("GzipWriter cannot compress output for writer type %T", gw.inner)
+	}
+	return gw.inner.Write(records, filePath)
+}
+
+// Close closes the wrapped writer, which finishes the compressed stream of any file it still holds open.
+// It is safe to call multiple times.
+func (gw *GzipWriter) Close() error {
+	return gw.inner.Close()
+}
+
+// create creates filePath, compressing what is written to it through a gzip.Writer when it should be
+// compressed.
+func (gw *GzipWriter) create(filePath string) (io.WriteCloser, error) {
+	if !useGzip(filePath, gw.compression) {
+		return createOutput(nil, filePath)
+	}
+	logging.Logf(logging.Debug, "GzipWriter compressing output: %s", filePath)
+
+	f, err := createOutput(nil, filePath)
+	if err != nil {
+		return nil, err
+	}
+	zw := gzip.NewWriter(f)
+	zw.Name = strings.TrimSuffix(filepath.Base(filePath), ".gz")
+	return &gzipWriteCloser{Writer: zw, file: f}, nil
+}
+
+// gzipWriteCloser writes through a gzip.Writer and closes the underlying file along with it.
+type gzipWriteCloser struct {
+	*gzip.Writer
+	file io.Closer
+}
+
+// Close finishes the gzip stream and then closes the file.
+func (wc *gzipWriteCloser) Close() error {
+	err := wc.Writer.Close()
+	if closeErr := wc.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// setFileOpener makes a file-based reader open its input through open.
+// It reports false for readers that do not read files.
+func setFileOpener(reader InputReader, open fileOpener) bool {
+	switch r := reader.(type) {
+	case *CSVReader:
+		r.open = open
+	case *JSONReader:
+		r.open = open
+	case *XMLReader:
+		r.open = open
+	case *XLSXReader:
+		r.open = open
+	case *YAMLReader:
+		r.open = open
+	case *TOMLReader:
+		r.open = open
+	default:
+		return false
+	}
+	return true
+}
+
+// setFileCreator makes a file-based writer create its output through create.
+// It reports false for writers that do not write files.
+func setFileCreator(writer OutputWriter, create fileCreator) bool {
+	switch w := writer.(type) {
+	case *CSVWriter:
+		w.create = create
+	case *JSONWriter:
+		w.create = create
+	case *XMLWriter:
+		w.create = create
+	case *XLSXWriter:
+		w.create = create
+	case *YAMLWriter:
+		w.create = create
+	default:
+		return false
+	}
+	return true
+}
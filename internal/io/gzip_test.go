@@ -0,0 +1,173 @@
+package io
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"etl-tool/internal/config"
+)
+
+// readGzipFile returns the decompressed content of a gzip file.
+func readGzipFile(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open(%s) failed: %v", path, err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader(%s) failed: %v", path, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompressing %s failed: %v", path, err)
+	}
+	return string(data)
+}
+
+// roundTrip writes records through NewOutputWriter and reads them back through NewInputReader.
+func roundTrip(t *testing.T, fileType, path, compression string, records []map[string]interface{}) []map[string]interface{} {
+	t.Helper()
+	writer, err := NewOutputWriter(config.DestinationConfig{Type: fileType, File: path, Compression: compression}, "")
+	if err != nil {
+		t.Fatalf("NewOutputWriter failed: %v", err)
+	}
+	if err := writer.Write(records, path); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	reader, err := NewInputReader(config.SourceConfig{Type: fileType, File: path, Compression: compression}, "")
+	if err != nil {
+		t.Fatalf("NewInputReader failed: %v", err)
+	}
+	got, err := reader.Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return got
+}
+
+func TestGzipRoundTrip(t *testing.T) {
+	records := []map[string]interface{}{{"id": "1", "name": "Alice"}, {"id": "2", "name": "Bob"}}
+
+	t.Run("csv detected from extension", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out", "people.csv.gz")
+		got := roundTrip(t, "csv", path, "", records)
+		compareRecordsDeep(t, got, records)
+
+		if content := readGzipFile(t, path); content != "id,name\n1,Alice\n2,Bob\n" {
+			t.Errorf("decompressed content = %q", content)
+		}
+		if names := dirEntryNames(t, filepath.Dir(path)); len(names) != 1 || names[0] != "people.csv.gz" {
+			t.Errorf("expected only the compressed file, got %v", names)
+		}
+	})
+
+	t.Run("xlsx forced by compression setting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "people.xlsx")
+		got := roundTrip(t, "xlsx", path, config.CompressionGzip, records)
+		compareRecordsDeep(t, got, records)
+		readGzipFile(t, path) // Fails the test if the file is not gzip data
+	})
+
+	t.Run("streams without temporary files", func(t *testing.T) {
+		tempDir := t.TempDir()
+		t.Setenv("TMPDIR", tempDir)
+		for _, fileType := range []string{"csv", "json", "xml", "xlsx", "yaml"} {
+			path := filepath.Join(t.TempDir(), "people."+fileType+".gz")
+			got := roundTrip(t, fileType, path, "", records)
+			compareRecordsDeep(t, got, records)
+		}
+		if names := dirEntryNames(t, tempDir); len(names) != 0 {
+			t.Errorf("expected no temporary files, got %v", names)
+		}
+	})
+
+	t.Run("compression none writes plain file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "people.json.gz")
+		got := roundTrip(t, "json", path, config.CompressionNone, []map[string]interface{}{{"id": "1"}})
+		compareRecordsDeep(t, got, []map[string]interface{}{{"id": "1"}})
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "[") {
+			t.Errorf("expected plain JSON, got %q", data)
+		}
+	})
+}
+
+func TestGzipReader_InvalidData(t *testing.T) {
+	path := createTempFile(t, "id,name\n1,Alice\n", "plain-*.csv.gz")
+	reader := NewGzipReader(&JSONReader{}, "")
+	_, err := reader.Read(path)
+	if err == nil || !strings.Contains(err.Error(), "failed to read gzip header") {
+		t.Fatalf("expected gzip header error, got %v", err)
+	}
+}
+
+func TestGzipReader_UnsupportedReader(t *testing.T) {
+	reader := NewGzipReader(&PostgresReader{}, config.CompressionGzip)
+	_, err := reader.Read("input.csv.gz")
+	if err == nil || !strings.Contains(err.Error(), "cannot decompress input") {
+		t.Fatalf("expected unsupported reader error, got %v", err)
+	}
+}
+
+func TestGzipWriter_UnsupportedWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.csv.gz")
+	inner := &failingOutputWriter{}
+	writer := NewGzipWriter(inner, "")
+	if err := writer.Write([]map[string]interface{}{{"id": 1}}, path); err == nil || !strings.Contains(err.Error(), "cannot compress output") {
+		t.Fatalf("expected unsupported writer error, got %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !inner.closed {
+		t.Error("wrapped writer was not closed")
+	}
+	if names := dirEntryNames(t, dir); len(names) != 0 {
+		t.Errorf("expected no output files, got %v", names)
+	}
+}
+
+// closeFailingFile is a fileCreator target whose Close fails, like a gzip stream whose trailer cannot be written.
+type closeFailingFile struct{ bytes.Buffer }
+
+func (f *closeFailingFile) Close() error { return errors.New("trailer write failed") }
+
+func TestWriters_ReportCloseErrors(t *testing.T) {
+	create := func(string) (io.WriteCloser, error) { return &closeFailingFile{}, nil }
+	writers := map[string]OutputWriter{
+		"csv":  &CSVWriter{Delimiter: ','},
+		"json": &JSONWriter{},
+		"xml":  NewXMLWriter("", ""),
+		"xlsx": NewXLSXWriter(""),
+		"yaml": &YAMLWriter{},
+	}
+	for name, writer := range writers {
+		t.Run(name, func(t *testing.T) {
+			setFileCreator(writer, create)
+			path := filepath.Join(t.TempDir(), "out."+name)
+			err := writer.Write([]map[string]interface{}{{"id": "1"}}, path)
+			if err == nil {
+				err = writer.Close()
+			}
+			if err == nil || !strings.Contains(err.Error(), "trailer write failed") {
+				t.Errorf("expected the close error to be reported, got %v", err)
+			}
+		})
+	}
+}
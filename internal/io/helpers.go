@@ -0,0 +1,123 @@
+package io
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"etl-tool/internal/logging"
+)
+
+// orderFields returns the keys of fieldSet with the names from fieldOrder first (in that order),
+// followed by the remaining keys sorted alphabetically.
+func orderFields(fieldSet map[string]struct{}, fieldOrder []string) []string {
+	ordered := make([]string, 0, len(fieldSet))
+	placed := make(map[string]bool, len(fieldOrder))
+	for _, name := range fieldOrder {
+		if _, exists := fieldSet[name]; exists && !placed[name] {
+			ordered = append(ordered, name)
+			placed[name] = true
+		}
+	}
+	rest := make([]string, 0, len(fieldSet)-len(ordered))
+	for name := range fieldSet {
+		if !placed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(ordered, rest...)
+}
+
+// formatFixedFloat formats a float32/float64 value with exactly *precision decimals. It reports false (leaving
+// the value to the caller's default formatting) when precision is nil or the value is not a float.
+func formatFixedFloat(value interface{}, precision *int) (string, bool) {
+	if precision == nil {
+		return "", false
+	}
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', *precision, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', *precision, 32), true
+	}
+	return "", false
+}
+
+// trimSourceRows applies the skip_header_rows, skip_footer_rows, and max_rows source options to parsed
+// tabular rows. It returns the header row followed by the kept data rows (empty if nothing remains).
+func trimSourceRows(rows [][]string, skipHeaderRows, skipFooterRows, maxRows int, source string) [][]string {
+	if skipHeaderRows > 0 {
+		if skipHeaderRows >= len(rows) {
+			logging.Logf(logging.Warning, "skip_header_rows (%d) skips every row of '%s'", skipHeaderRows, source)
+			return [][]string{}
+		}
+		logging.Logf(logging.Debug, "Skipping %d row(s) before the header in '%s'", skipHeaderRows, source)
+		rows = rows[skipHeaderRows:]
+	}
+	if len(rows) < 2 {
+		return rows
+	}
+	header, data := rows[0], rows[1:]
+	if skipFooterRows > 0 {
+		if skipFooterRows >= len(data) {
+			logging.Logf(logging.Warning, "skip_footer_rows (%d) skips every data row of '%s'", skipFooterRows, source)
+			data = data[:0]
+		} else {
+			data = data[:len(data)-skipFooterRows]
+		}
+	}
+	if maxRows > 0 && len(data) > maxRows {
+		logging.Logf(logging.Info, "Reading only the first %d data row(s) of '%s' (max_rows)", maxRows, source)
+		data = data[:maxRows]
+	}
+	return append([][]string{header}, data...)
+}
+
+// fileOpener opens an input file. File-based readers hold one (nil means a plain os.Open) so that GzipReader
+// can have them read through a decompressing stream.
+type fileOpener func(filePath string) (io.ReadCloser, error)
+
+// fileCreator creates or truncates an output file. File-based writers hold one (nil means a plain file with
+// mode 0644) so that GzipWriter can have them write through a compressing stream.
+type fileCreator func(filePath string) (io.WriteCloser, error)
+
+// openInput opens filePath through open, or directly when open is nil.
+func openInput(open fileOpener, filePath string) (io.ReadCloser, error) {
+	if open != nil {
+		return open(filePath)
+	}
+	return os.Open(filePath)
+}
+
+// createOutput creates filePath through create, or directly when create is nil.
+func createOutput(create fileCreator, filePath string) (io.WriteCloser, error) {
+	if create != nil {
+		return create(filePath)
+	}
+	return os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+}
+
+// readInput returns the whole content of filePath, opened through open (see openInput).
+func readInput(open fileOpener, filePath string) ([]byte, error) {
+	f, err := openInput(open, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// writeOutput writes data to filePath, created through create (see createOutput).
+func writeOutput(create fileCreator, filePath string, data []byte) error {
+	f, err := createOutput(create, filePath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
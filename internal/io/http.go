@@ -0,0 +1,90 @@
+package io
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"etl-tool/internal/config"
+	"etl-tool/internal/logging"
+	"etl-tool/internal/util"
+)
+
+// maxHTTPErrorBodySize is the number of response body bytes quoted in the error for a non-2xx response.
+const maxHTTPErrorBodySize = 512
+
+// HTTPReader implements the InputReader interface for JSON REST endpoints. It issues a single request and
+// maps the JSON response to records: an array of objects, or a single object as one record.
+type HTTPReader struct {
+	method      string
+	headers     map[string]string // Values are expanded from the environment at request time
+	timeout     time.Duration     // Limits the whole request, including reading the body
+	recordsPath string            // Dotted path to the records array inside the response (see records_path)
+	client      *http.Client
+}
+
+// NewHTTPReader creates a new HTTPReader; an empty method defaults to GET and a non-positive timeout to
+// config.DefaultHTTPTimeout.
+func NewHTTPReader(method string, headers map[string]string, timeout time.Duration) *HTTPReader {
+	if method == "" {
+		method = config.DefaultHTTPMethod
+	}
+	if timeout <= 0 {
+		timeout, _ = time.ParseDuration(config.DefaultHTTPTimeout)
+	}
+	return &HTTPReader{
+		method:  strings.ToUpper(method),
+		headers: headers,
+		timeout: timeout,
+		client:  http.DefaultClient,
+	}
+}
+
+// Read requests the URL and returns the records decoded from the JSON response.
+// Responses with a status outside 2xx fail with the status and the start of the response body.
+func (hr *HTTPReader) Read(url string) ([]map[string]interface{}, error) {
+	maskedURL := util.MaskCredentials(url)
+	logging.Logf(logging.Debug, "HTTPReader sending %s request to: %s", hr.method, maskedURL)
+	ctx, cancel := context.WithTimeout(context.Background(), hr.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, hr.method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPReader failed to create request for '%s': %w", maskedURL, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range hr.headers {
+		req.Header.Set(name, util.ExpandEnvUniversal(value))
+	}
+
+	resp, err := hr.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPReader request to '%s' failed: %w", maskedURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPErrorBodySize))
+		return nil, fmt.Errorf("HTTPReader request to '%s' failed with status %s: %s", maskedURL, resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPReader failed to read response from '%s': %w", maskedURL, err)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("HTTPReader failed to unmarshal JSON response from '%s': %w", maskedURL, err)
+	}
+	records, err := jsonRecordsAt(data, hr.recordsPath)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPReader cannot read records from '%s': %w", maskedURL, err)
+	}
+
+	logging.Logf(logging.Debug, "HTTPReader successfully loaded %d records from %s", len(records), maskedURL)
+	return records, nil
+}
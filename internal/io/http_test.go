@@ -0,0 +1,160 @@
+package io
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHTTPReader_Read verifies JSON responses are mapped to records, including nested arrays and error statuses.
+func TestHTTPReader_Read(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		body        string
+		recordsPath string
+		wantRecords []map[string]interface{}
+		wantErrMsg  string
+	}{
+		{
+			name:        "200 array response",
+			status:      http.StatusOK,
+			body:        `[{"id": 1, "name": "Alice"}, {"id": 2, "name": "Bob"}]`,
+			wantRecords: []map[string]interface{}{{"id": float64(1), "name": "Alice"}, {"id": float64(2), "name": "Bob"}},
+		},
+		{
+			name:        "Single object response",
+			status:      http.StatusOK,
+			body:        `{"id": 1}`,
+			wantRecords: []map[string]interface{}{{"id": float64(1)}},
+		},
+		{
+			name:        "Nested records field",
+			status:      http.StatusOK,
+			body:        `{"meta": {"count": 2}, "data": [{"id": 1}, {"id": 2}]}`,
+			recordsPath: "data",
+			wantRecords: []map[string]interface{}{{"id": float64(1)}, {"id": float64(2)}},
+		},
+		{
+			name:        "Deeply nested records field",
+			status:      http.StatusOK,
+			body:        `{"response": {"items": [{"id": 7}]}}`,
+			recordsPath: "response.items",
+			wantRecords: []map[string]interface{}{{"id": float64(7)}},
+		},
+		{
+			name:        "Empty nested array",
+			status:      http.StatusOK,
+			body:        `{"data": []}`,
+			recordsPath: "data",
+			wantRecords: []map[string]interface{}{},
+		},
+		{
+			name:        "Records field not found",
+			status:      http.StatusOK,
+			body:        `{"data": []}`,
+			recordsPath: "results",
+			wantErrMsg:  "key 'results' not found",
+		},
+		{
+			name:        "Records field through non-object",
+			status:      http.StatusOK,
+			body:        `{"data": [1]}`,
+			recordsPath: "data.items",
+			wantErrMsg:  "'data' is []interface {}, expected a JSON object",
+		},
+		{
+			name:       "Array of non-objects",
+			status:     http.StatusOK,
+			body:       `[{"id": 1}, 2]`,
+			wantErrMsg: "element 1 of the records array",
+		},
+		{
+			name:       "Invalid JSON",
+			status:     http.StatusOK,
+			body:       `{"id": `,
+			wantErrMsg: "failed to unmarshal JSON response",
+		},
+		{
+			name:       "Non-2xx status",
+			status:     http.StatusNotFound,
+			body:       `{"error": "no such resource"}`,
+			wantErrMsg: `failed with status 404 Not Found: {"error": "no such resource"}`,
+		},
+		{
+			name:       "Server error",
+			status:     http.StatusInternalServerError,
+			body:       "boom",
+			wantErrMsg: "failed with status 500",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			reader := NewHTTPReader("", nil, 0)
+			reader.recordsPath = tc.recordsPath
+			records, err := reader.Read(server.URL)
+			if tc.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrMsg) {
+					t.Fatalf("Read() error = %v, want error containing %q", err, tc.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, records, tc.wantRecords)
+		})
+	}
+}
+
+// TestHTTPReader_Request verifies the configured method and headers (with environment expansion) are sent.
+func TestHTTPReader_Request(t *testing.T) {
+	t.Setenv("ETL_TEST_API_TOKEN", "secret-token")
+	var gotMethod, gotAuth, gotAccept, gotCustom string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotAuth, gotAccept, gotCustom = r.Method, r.Header.Get("Authorization"), r.Header.Get("Accept"), r.Header.Get("X-Custom")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	reader := NewHTTPReader("post", map[string]string{"Authorization": "Bearer ${ETL_TEST_API_TOKEN}", "X-Custom": "yes"}, 0)
+	if _, err := reader.Read(server.URL + "/search"); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want POST", gotMethod)
+	}
+	if gotAuth != "Bearer secret-token" {
+		t.Errorf("Authorization header = %q, want %q", gotAuth, "Bearer secret-token")
+	}
+	if gotAccept != "application/json" || gotCustom != "yes" {
+		t.Errorf("Accept = %q, X-Custom = %q, want application/json and yes", gotAccept, gotCustom)
+	}
+}
+
+// TestHTTPReader_Timeout verifies a slow endpoint fails once the configured timeout elapses.
+func TestHTTPReader_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	reader := NewHTTPReader("", nil, 50*time.Millisecond)
+	if _, err := reader.Read(server.URL); err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("Read() error = %v, want a deadline exceeded error", err)
+	}
+}
@@ -24,6 +24,20 @@ type OutputWriter interface {
 	Close() error
 }
 
+// FieldOrderer is optionally implemented by OutputWriters that lay out fields as ordered
+// columns or elements (CSV, XLSX, XML). When a field order is set, those fields come first
+// in the given order; any other fields follow alphabetically.
+type FieldOrderer interface {
+	SetFieldOrder(fields []string)
+}
+
+// ErrorRouter is optionally implemented by OutputWriters that can isolate individual failing records
+// (e.g., the PostgreSQL custom SQL loader). skipFailed mirrors the "skip" error handling mode; failed
+// records are written to errorWriter when it is non-nil.
+type ErrorRouter interface {
+	SetErrorRouting(errorWriter ErrorWriter, skipFailed bool)
+}
+
 // ErrorWriter defines the interface for writing records that failed during processing.
 type ErrorWriter interface {
 	// Write records the problematic input record (or partially transformed record)
@@ -33,4 +47,4 @@ type ErrorWriter interface {
 	// Close ensures any buffered error data is flushed and resources (like files) are released.
 	// Implementations should be idempotent.
 	Close() error
-}
\ No newline at end of file
+}
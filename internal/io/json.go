@@ -1,28 +1,55 @@
 package io
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"etl-tool/internal/logging"
 )
 
+// maxJSONLineSize is the longest line (in bytes) JSONReader accepts in JSON Lines mode.
+const maxJSONLineSize = 64 * 1024 * 1024
+
 // JSONReader implements the InputReader interface for JSON files.
-type JSONReader struct{}
+type JSONReader struct {
+	lines       bool       // Read JSON Lines (one object per line) instead of a single array (see format)
+	recordsPath string     // Dotted path to the records array inside the document (see records_path)
+	open        fileOpener // Opens the input file; nil means os.Open (see GzipReader)
+}
 
 // Read loads data from a JSON file specified by filePath.
 // The JSON file is expected to contain an array of objects, but will
-// gracefully handle a single top-level object as well.
+// gracefully handle a single top-level object as well. With a records path,
+// the array (or single object) is taken from that location in the document instead.
 // Returns a slice of maps representing the records, or an error.
 func (jr *JSONReader) Read(filePath string) ([]map[string]interface{}, error) {
 	logging.Logf(logging.Debug, "JSONReader reading file: %s", filePath)
-	data, err := os.ReadFile(filePath)
+	if jr.lines {
+		return jr.readLines(filePath)
+	}
+	data, err := readInput(jr.open, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("JSONReader failed to read file '%s': %w", filePath, err)
 	}
 
+	if jr.recordsPath != "" {
+		var document interface{}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("JSONReader failed to unmarshal JSON from '%s': %w", filePath, err)
+		}
+		records, err := jsonRecordsAt(document, jr.recordsPath)
+		if err != nil {
+			return nil, fmt.Errorf("JSONReader cannot read records from '%s': %w", filePath, err)
+		}
+		logging.Logf(logging.Debug, "JSONReader successfully loaded %d records at '%s' from %s", len(records), jr.recordsPath, filePath)
+		return records, nil
+	}
+
 	var records []map[string]interface{}
 	// Attempt to unmarshal the JSON data into the slice of maps (array expected).
 	if err := json.Unmarshal(data, &records); err != nil {
@@ -41,9 +68,87 @@ func (jr *JSONReader) Read(filePath string) ([]map[string]interface{}, error) {
 	return records, nil
 }
 
+// jsonRecordsAt follows the dotted path (empty for the top level) through nested JSON objects and converts the
+// value found there to records: an array must contain only objects, and a single object becomes one record.
+func jsonRecordsAt(data interface{}, path string) ([]map[string]interface{}, error) {
+	if path != "" {
+		walked := make([]string, 0, strings.Count(path, ".")+1)
+		for _, key := range strings.Split(path, ".") {
+			obj, ok := data.(map[string]interface{})
+			if !ok {
+				if len(walked) == 0 {
+					return nil, fmt.Errorf("path '%s': top-level value is %T, expected a JSON object", path, data)
+				}
+				return nil, fmt.Errorf("path '%s': '%s' is %T, expected a JSON object", path, strings.Join(walked, "."), data)
+			}
+			walked = append(walked, key)
+			if data, ok = obj[key]; !ok {
+				return nil, fmt.Errorf("path '%s': key '%s' not found", path, strings.Join(walked, "."))
+			}
+		}
+	}
+
+	switch v := data.(type) {
+	case []interface{}:
+		records := make([]map[string]interface{}, 0, len(v))
+		for i, item := range v {
+			record, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("element %d of the records array is %T, expected a JSON object", i, item)
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	case map[string]interface{}:
+		return []map[string]interface{}{v}, nil
+	default:
+		return nil, fmt.Errorf("expected a JSON array or object of records, got %T", data)
+	}
+}
+
+// readLines reads a JSON Lines file, decoding each non-blank line as one record. The file is scanned line by
+// line rather than loaded whole; string values containing newlines are escaped in JSON, so they never span lines.
+func (jr *JSONReader) readLines(filePath string) ([]map[string]interface{}, error) {
+	file, err := openInput(jr.open, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("JSONReader failed to read file '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	records := []map[string]interface{}{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLineSize)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("JSONReader failed to unmarshal line %d of '%s' as a JSON object: %w", lineNum, filePath, err)
+		}
+		if record == nil { // A literal null line
+			return nil, fmt.Errorf("JSONReader failed to unmarshal line %d of '%s': expected a JSON object, got null", lineNum, filePath)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("JSONReader failed to read line %d of '%s': %w", lineNum+1, filePath, err)
+	}
+
+	logging.Logf(logging.Debug, "JSONReader successfully loaded %d JSON Lines records from %s", len(records), filePath)
+	return records, nil
+}
+
 // JSONWriter implements the OutputWriter interface for JSON files.
 // The Write operation is self-contained and does not require a separate Close call.
-type JSONWriter struct{}
+type JSONWriter struct {
+	floatPrecision *int        // Optional fixed number of decimals for float values (see float_precision)
+	lines          bool        // Write JSON Lines (one compact object per line) instead of an indented array (see format)
+	create         fileCreator // Creates the output file; nil means a plain file (see GzipWriter)
+}
 
 // Write saves the provided records as a JSON array to the specified filePath.
 // It marshals the data with indentation for readability. Ensures the output directory exists.
@@ -59,6 +164,10 @@ func (jw *JSONWriter) Write(records []map[string]interface{}, filePath string) e
 		}
 	}
 
+	if jw.lines {
+		return jw.writeLines(records, filePath)
+	}
+
 	// Marshal the slice of maps into a JSON byte array with indentation.
 	// Handle the case of empty records slice specifically.
 	var data []byte
@@ -67,7 +176,11 @@ func (jw *JSONWriter) Write(records []map[string]interface{}, filePath string) e
 		logging.Logf(logging.Debug, "JSONWriter: No records provided, writing empty JSON array '[]' to %s", filePath)
 		data = []byte("[]\n") // Write an empty JSON array explicitly. Add newline for consistency.
 	} else {
-		data, err = json.MarshalIndent(records, "", "  ") // Use two spaces for indentation.
+		var output interface{} = records
+		if jw.floatPrecision != nil {
+			output = fixJSONFloatPrecision(records, *jw.floatPrecision)
+		}
+		data, err = json.MarshalIndent(output, "", "  ") // Use two spaces for indentation.
 		if err != nil {
 			return fmt.Errorf("JSONWriter failed to marshal records to JSON: %w", err)
 		}
@@ -76,8 +189,8 @@ func (jw *JSONWriter) Write(records []map[string]interface{}, filePath string) e
 	}
 
 	// Write the JSON data to the specified file.
-	// os.WriteFile handles file creation, truncation, and closing internally.
-	err = os.WriteFile(filePath, data, 0644) // Use standard file permissions.
+	// writeOutput handles file creation, truncation, and closing internally.
+	err = writeOutput(jw.create, filePath, data)
 	if err != nil {
 		return fmt.Errorf("JSONWriter failed to write file '%s': %w", filePath, err)
 	}
@@ -86,9 +199,77 @@ func (jw *JSONWriter) Write(records []map[string]interface{}, filePath string) e
 	return nil
 }
 
+// writeLines writes records as JSON Lines, encoding one record at a time through a buffered writer.
+// No records produce an empty file.
+func (jw *JSONWriter) writeLines(records []map[string]interface{}, filePath string) error {
+	file, err := createOutput(jw.create, filePath)
+	if err != nil {
+		return fmt.Errorf("JSONWriter failed to write file '%s': %w", filePath, err)
+	}
+	buf := bufio.NewWriter(file)
+	encoder := json.NewEncoder(buf) // Encode terminates each record with a newline
+	for i, record := range records {
+		var output interface{} = record
+		if jw.floatPrecision != nil {
+			output = fixJSONFloatPrecision(record, *jw.floatPrecision)
+		}
+		if err := encoder.Encode(output); err != nil {
+			file.Close()
+			return fmt.Errorf("JSONWriter failed to marshal record %d to JSON: %w", i, err)
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("JSONWriter failed to write file '%s': %w", filePath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("JSONWriter failed to write file '%s': %w", filePath, err)
+	}
+
+	logging.Logf(logging.Debug, "JSONWriter successfully wrote %d JSON Lines records to %s", len(records), filePath)
+	return nil
+}
+
 // Close implements the OutputWriter interface. For JSONWriter, this is a no-op
-// as os.WriteFile handles file closing internally within the Write method.
+// as the file is closed within the Write method.
 func (jw *JSONWriter) Close() error {
 	logging.Logf(logging.Debug, "JSONWriter Close called (no-op).")
 	return nil
-}
\ No newline at end of file
+}
+
+// fixJSONFloatPrecision returns a copy of value in which every float (including inside nested maps and slices)
+// is replaced by a json.Number with exactly precision decimals, so it is still written as a JSON number.
+// Records are copied rather than modified in place.
+func fixJSONFloatPrecision(value interface{}, precision int) interface{} {
+	switch v := value.(type) {
+	case float64, float32:
+		formatted, _ := formatFixedFloat(v, &precision)
+		return json.Number(formatted)
+	case []map[string]interface{}:
+		out := make([]map[string]interface{}, len(v))
+		for i, rec := range v {
+			out[i] = fixJSONFloatPrecision(rec, precision).(map[string]interface{})
+		}
+		return out
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = fixJSONFloatPrecision(item, precision)
+		}
+		return out
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = fixJSONFloatPrecision(item, precision)
+		}
+		return out
+	default:
+		return value
+	}
+}
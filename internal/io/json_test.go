@@ -270,6 +270,218 @@ func TestJSONWriter_Write(t *testing.T) {
 
 }
 
+// TestJSONWriter_FloatPrecision verifies float_precision rounds floats (including nested ones) as JSON numbers.
+func TestJSONWriter_FloatPrecision(t *testing.T) {
+	records := []map[string]interface{}{{"pi": 3.14159, "n": 7, "nested": map[string]interface{}{"vals": []interface{}{1.005, "x"}}}}
+	two := 2
+	testCases := []struct {
+		name        string
+		precision   *int
+		wantContent string
+	}{
+		{name: "Unset keeps default", precision: nil, wantContent: "[\n  {\n    \"n\": 7,\n    \"nested\": {\n      \"vals\": [\n        1.005,\n        \"x\"\n      ]\n    },\n    \"pi\": 3.14159\n  }\n]\n"},
+		{name: "Precision 2", precision: &two, wantContent: "[\n  {\n    \"n\": 7,\n    \"nested\": {\n      \"vals\": [\n        1.00,\n        \"x\"\n      ]\n    },\n    \"pi\": 3.14\n  }\n]\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "floats.json")
+			writer := JSONWriter{floatPrecision: tc.precision}
+			if err := writer.Write(records, filePath); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if string(content) != tc.wantContent {
+				t.Errorf("Write() file content mismatch:\ngot:\n%s\nwant:\n%s", content, tc.wantContent)
+			}
+		})
+	}
+	if records[0]["pi"] != 3.14159 {
+		t.Errorf("Write() modified the input record: %v", records[0])
+	}
+}
+
+// TestJSONReader_ReadLines verifies JSON Lines parsing, including blank lines and escaped newlines in strings.
+func TestJSONReader_ReadLines(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		wantRecords []map[string]interface{}
+		wantErrMsg  string
+	}{
+		{
+			name:        "One object per line",
+			content:     "{\"id\": 1, \"name\": \"Alice\"}\n{\"id\": 2, \"name\": \"Bob\"}",
+			wantRecords: []map[string]interface{}{{"id": float64(1), "name": "Alice"}, {"id": float64(2), "name": "Bob"}},
+		},
+		{
+			name:        "Blank lines and trailing newlines",
+			content:     "\n{\"id\": 1}\n\n   \n{\"id\": 2}\r\n\n\n",
+			wantRecords: []map[string]interface{}{{"id": float64(1)}, {"id": float64(2)}},
+		},
+		{
+			name:        "Embedded newlines inside strings",
+			content:     "{\"note\": \"line one\\nline two\"}\n{\"note\": \"tab\\there\"}\n",
+			wantRecords: []map[string]interface{}{{"note": "line one\nline two"}, {"note": "tab\there"}},
+		},
+		{
+			name:        "Empty file",
+			content:     "",
+			wantRecords: []map[string]interface{}{},
+		},
+		{
+			name:       "Invalid line",
+			content:    "{\"id\": 1}\n{\"id\": \n",
+			wantErrMsg: "failed to unmarshal line 2",
+		},
+		{
+			name:       "Array line is not an object",
+			content:    "[1, 2]\n",
+			wantErrMsg: "failed to unmarshal line 1",
+		},
+		{
+			name:       "Null line",
+			content:    "{\"id\": 1}\nnull\n",
+			wantErrMsg: "expected a JSON object, got null",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempFile(t, tc.content, "lines_*.jsonl")
+			reader := JSONReader{lines: true}
+			records, err := reader.Read(filePath)
+			if tc.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrMsg) {
+					t.Fatalf("Read() error = %v, want error containing %q", err, tc.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, records, tc.wantRecords)
+		})
+	}
+}
+
+// TestJSONReader_ReadRecordsPath verifies records are located at a dotted path inside the document.
+func TestJSONReader_ReadRecordsPath(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		recordsPath string
+		wantRecords []map[string]interface{}
+		wantErrMsg  string
+	}{
+		{
+			name:        "Top-level key",
+			content:     `{"meta": {"page": 1}, "results": [{"id": 1}, {"id": 2}]}`,
+			recordsPath: "results",
+			wantRecords: []map[string]interface{}{{"id": float64(1)}, {"id": float64(2)}},
+		},
+		{
+			name:        "Nested key",
+			content:     `{"data": {"total": 1, "items": [{"id": 3, "name": "Carol"}]}}`,
+			recordsPath: "data.items",
+			wantRecords: []map[string]interface{}{{"id": float64(3), "name": "Carol"}},
+		},
+		{
+			name:        "Object at path is a single record",
+			content:     `{"data": {"item": {"id": 4}}}`,
+			recordsPath: "data.item",
+			wantRecords: []map[string]interface{}{{"id": float64(4)}},
+		},
+		{
+			name:        "Empty array at path",
+			content:     `{"results": []}`,
+			recordsPath: "results",
+			wantRecords: []map[string]interface{}{},
+		},
+		{
+			name:        "Missing key",
+			content:     `{"data": {"rows": []}}`,
+			recordsPath: "data.items",
+			wantErrMsg:  "key 'data.items' not found",
+		},
+		{
+			name:        "Path through an array",
+			content:     `{"results": [{"id": 1}]}`,
+			recordsPath: "results.items",
+			wantErrMsg:  "'results' is []interface {}, expected a JSON object",
+		},
+		{
+			name:        "Top-level array",
+			content:     `[{"id": 1}]`,
+			recordsPath: "results",
+			wantErrMsg:  "top-level value is []interface {}, expected a JSON object",
+		},
+		{
+			name:        "Scalar at path",
+			content:     `{"results": 5}`,
+			recordsPath: "results",
+			wantErrMsg:  "expected a JSON array or object of records, got float64",
+		},
+		{
+			name:        "Invalid JSON",
+			content:     `{"results": [`,
+			recordsPath: "results",
+			wantErrMsg:  "failed to unmarshal JSON",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempFile(t, tc.content, "nested_*.json")
+			reader := JSONReader{recordsPath: tc.recordsPath}
+			records, err := reader.Read(filePath)
+			if tc.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrMsg) {
+					t.Fatalf("Read() error = %v, want error containing %q", err, tc.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, records, tc.wantRecords)
+		})
+	}
+}
+
+// TestJSONWriter_WriteLines verifies JSON Lines output and that it reads back to the same records.
+func TestJSONWriter_WriteLines(t *testing.T) {
+	records := []map[string]interface{}{{"id": float64(1), "note": "line one\nline two"}, {"id": float64(2), "price": 9.5}}
+	filePath := filepath.Join(t.TempDir(), "out", "records.jsonl")
+	writer := JSONWriter{lines: true}
+	if err := writer.Write(records, filePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	wantContent := "{\"id\":1,\"note\":\"line one\\nline two\"}\n{\"id\":2,\"price\":9.5}\n"
+	if string(content) != wantContent {
+		t.Errorf("Write() file content mismatch:\ngot:\n%s\nwant:\n%s", content, wantContent)
+	}
+	got, err := (&JSONReader{lines: true}).Read(filePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	compareRecordsDeep(t, got, records)
+
+	t.Run("No records writes empty file", func(t *testing.T) {
+		emptyPath := filepath.Join(t.TempDir(), "empty.jsonl")
+		if err := writer.Write(nil, emptyPath); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if content, err := os.ReadFile(emptyPath); err != nil || len(content) != 0 {
+			t.Errorf("expected empty file, got %q (err %v)", content, err)
+		}
+	})
+}
+
 func TestJSONWriter_Close(t *testing.T) {
 	writer := JSONWriter{}
 	err := writer.Close()
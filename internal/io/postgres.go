@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"etl-tool/internal/config"
 	"etl-tool/internal/logging"
@@ -25,6 +26,16 @@ var pgxPoolNewFunc = pgxpool.New // Store original function
 // Default database connection and query timeout
 const defaultDbTimeout = 30 * time.Second
 
+// txBeginner is the part of *pgxpool.Pool used by the custom SQL loader (allows substituting a fake in tests).
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// rowQuerier is the part of *pgxpool.Pool used to look up column metadata (allows substituting a fake in tests).
+type rowQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 // PostgresReader implements the InputReader interface for PostgreSQL sources.
 type PostgresReader struct {
 	connStr string
@@ -129,6 +140,26 @@ type PostgresWriter struct {
 	connStr     string
 	targetTable string
 	loaderCfg   *config.LoaderConfig
+	verifyCount bool        // Fail the write if the rows written differ from the records sent
+	truncate    bool        // Truncate strings longer than their column's character_maximum_length
+	errorWriter ErrorWriter // Optional: receives records whose custom SQL command failed
+	skipFailed  bool        // Skip mode: isolate failed records in a batch instead of aborting the load
+}
+
+// SetErrorRouting implements ErrorRouter. In skip mode, a failing statement in a custom SQL batch no longer
+// aborts the load: the batch is replayed record by record and failed records go to errorWriter (if set).
+func (pw *PostgresWriter) SetErrorRouting(errorWriter ErrorWriter, skipFailed bool) {
+	pw.errorWriter, pw.skipFailed = errorWriter, skipFailed
+}
+
+// reportFailedRecord logs a failed custom SQL record and, in skip mode, writes it to the error writer (if set).
+func (pw *PostgresWriter) reportFailedRecord(index int, rec map[string]interface{}, execErr error) {
+	logging.Logf(logging.Error, "PostgresWriter (SQL): Command failed for record index %d: %v. Record data (masked): %v", index, execErr, util.MaskSensitiveData(rec))
+	if pw.errorWriter != nil && pw.skipFailed {
+		if err := pw.errorWriter.Write(rec, fmt.Errorf("custom SQL command failed for record index %d: %w", index, execErr)); err != nil {
+			logging.Logf(logging.Error, "PostgresWriter (SQL): Failed to write record index %d to error file: %v", index, err)
+		}
+	}
 }
 
 // NewPostgresWriter creates a new PostgresWriter instance.
@@ -164,6 +195,14 @@ func (pw *PostgresWriter) Write(records []map[string]interface{}, _ string) erro
 	}
 	defer pool.Close()
 
+	if pw.truncate {
+		columnLengths, err := getPostgresColumns(ctx, pool, pw.targetTable)
+		if err != nil {
+			return err
+		}
+		records = truncateToColumnLengths(records, columnLengths, pw.targetTable)
+	}
+
 	useCustomSQL := pw.loaderCfg != nil && strings.ToLower(pw.loaderCfg.Mode) == config.LoaderModeSQL
 
 	// Execute Preload SQL if configured
@@ -307,6 +346,9 @@ func (pw *PostgresWriter) loadUsingCopy(ctx context.Context, pool *pgxpool.Pool,
 	if copyCount != int64(len(records)) {
 		logging.Logf(logging.Warning, "PostgresWriter (COPY): Expected to copy %d rows to table '%s', but driver reported %d rows copied.", len(records), pw.targetTable, copyCount)
 		// Note: This is generally not treated as a fatal error by the driver itself.
+		if pw.verifyCount {
+			return verifyRowCount("COPY", pw.targetTable, len(records), copyCount)
+		}
 	} else {
 		logging.Logf(logging.Info, "PostgresWriter (COPY): Successfully inserted %d rows into table '%s'.", copyCount, pw.targetTable)
 	}
@@ -315,7 +357,8 @@ func (pw *PostgresWriter) loadUsingCopy(ctx context.Context, pool *pgxpool.Pool,
 
 // loadWithCustomSQL loads records using configured SQL commands, supporting batching.
 // Now expects pgxpool.Pool directly.
-func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.Pool, records []map[string]interface{}) error {
+// Accepts any txBeginner (normally *pgxpool.Pool).
+func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool txBeginner, records []map[string]interface{}) error {
 	// Basic validation
 	if pw.loaderCfg == nil || pw.loaderCfg.Command == "" {
 		return fmt.Errorf("PostgresWriter (SQL): loader config or command is missing")
@@ -383,7 +426,7 @@ func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.P
 			_, execErr := tx.Exec(ctx, pw.loaderCfg.Command, params...)
 			if execErr != nil {
 				errorCount++
-				logging.Logf(logging.Error, "PostgresWriter (SQL): Failed executing command for record %d: %v. Rolling back. Record data (masked): %v", i, execErr, util.MaskSensitiveData(rec))
+				pw.reportFailedRecord(i, rec, execErr)
 				if errors.Is(execErr, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
 					return fmt.Errorf("PostgresWriter (SQL): command execution timed out for record %d: %w", i, ctx.Err()) // Return timeout error
 				}
@@ -461,6 +504,7 @@ func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.P
 
 			// Check results for each command in the batch
 			batchErrCount := 0
+			failedIndex := -1 // Record index of the first failing command
 			var firstBatchErr error
 			for k := 0; k < currentBatchSize; k++ {
 				// Check context while processing results
@@ -475,8 +519,8 @@ func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.P
 					batchErrCount++
 					// Record the first error encountered in the batch
 					if firstBatchErr == nil {
-						recordIndex := k + batchStart
-						firstBatchErr = fmt.Errorf("command for record index %d (in batch %d-%d) failed: %w", recordIndex, batchStart, batchEnd-1, execErr)
+						failedIndex = k + batchStart
+						firstBatchErr = fmt.Errorf("command for record index %d (in batch %d-%d) failed: %w", failedIndex, batchStart, batchEnd-1, execErr)
 					}
 				}
 			}
@@ -493,12 +537,35 @@ func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.P
 
 			// If any error occurred during batch execution or closing results reader
 			if firstBatchErr != nil {
-				errorCount += currentBatchSize // Assume whole batch failed if any part failed
 				logging.Logf(logging.Error, "PostgresWriter (SQL): Batch %d-%d failed with %d error(s), rolling back transaction. First error: %v", batchStart, batchEnd-1, batchErrCount, firstBatchErr)
 				// Rollback happens in defer. Check if the error was a timeout.
 				if errors.Is(firstBatchErr, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+					errorCount += currentBatchSize
 					return fmt.Errorf("PostgresWriter (SQL): batch %d-%d timed out: %w", batchStart, batchEnd-1, firstBatchErr) // Return timeout error
 				}
+				if pw.skipFailed && failedIndex >= 0 {
+					// Statements after the failure only report the aborted transaction, so replay the batch
+					// record by record to isolate every failing record and keep the rest.
+					rbCtx, rbCancel := context.WithTimeout(rollbackCtx, 5*time.Second)
+					if rbErr := tx.Rollback(rbCtx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+						logging.Logf(logging.Error, "PostgresWriter (SQL): Failed to rollback batch %d-%d transaction: %v", batchStart, batchEnd-1, rbErr)
+					}
+					rbCancel()
+					committed = true // The failed transaction is closed; the replay uses its own
+					succeeded, failed, replayErr := pw.replayBatch(ctx, pool, currentBatchRecords, batchStart, columns)
+					processedCount += succeeded
+					errorCount += failed
+					if replayErr != nil {
+						errorCount += currentBatchSize - succeeded - failed
+						return fmt.Errorf("PostgresWriter (SQL): replay of batch %d-%d failed: %w", batchStart, batchEnd-1, replayErr)
+					}
+					logging.Logf(logging.Warning, "PostgresWriter (SQL): Batch %d-%d committed %d record(s) after skipping %d failed record(s).", batchStart, batchEnd-1, succeeded, failed)
+					continue
+				}
+				errorCount += currentBatchSize // Assume whole batch failed if any part failed
+				if failedIndex >= 0 {
+					logging.Logf(logging.Error, "PostgresWriter (SQL): Failing record index %d data (masked): %v", failedIndex, util.MaskSensitiveData(records[failedIndex]))
+				}
 				// For other batch errors, return the first specific error found
 				return fmt.Errorf("PostgresWriter (SQL): batch %d-%d failed: %w", batchStart, batchEnd-1, firstBatchErr)
 			}
@@ -520,6 +587,12 @@ func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.P
 		} // End batched loop
 	}
 
+	if pw.verifyCount {
+		if err := verifyRowCount("SQL", pw.targetTable, totalRecords, int64(processedCount)); err != nil {
+			return err
+		}
+	}
+
 	// Final summary logging
 	if errorCount > 0 {
 		logging.Logf(logging.Warning, "PostgresWriter (SQL): Completed processing for table '%s'. %d records processed successfully, %d records encountered errors (in failed transactions/batches).", pw.targetTable, processedCount, errorCount)
@@ -531,6 +604,174 @@ func (pw *PostgresWriter) loadWithCustomSQL(ctx context.Context, pool *pgxpool.P
 	return nil // Return nil if execution completes, even if some non-batched records failed (logged above)
 }
 
+// replayBatch executes a failed batch again in one transaction, running each record's command inside its own
+// savepoint so a failing record is rolled back and reported without aborting the others.
+// It returns the number of committed and failed records.
+func (pw *PostgresWriter) replayBatch(ctx context.Context, pool txBeginner, batchRecords []map[string]interface{}, batchStart int, columns []string) (int, int, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			rbCtx, rbCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer rbCancel()
+			if rbErr := tx.Rollback(rbCtx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logging.Logf(logging.Error, "PostgresWriter (SQL): Failed to rollback replayed batch starting at %d: %v", batchStart, rbErr)
+			}
+		}
+	}()
+
+	succeeded, failed := 0, 0
+	for k, rec := range batchRecords {
+		params := make([]interface{}, len(columns))
+		for j, colName := range columns {
+			params[j] = rec[colName]
+		}
+		savepoint, err := tx.Begin(ctx) // Nested transaction = SAVEPOINT
+		if err != nil {
+			return succeeded, failed, fmt.Errorf("failed to create savepoint for record index %d: %w", batchStart+k, err)
+		}
+		if _, execErr := savepoint.Exec(ctx, pw.loaderCfg.Command, params...); execErr != nil {
+			if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+				return succeeded, failed, fmt.Errorf("failed to roll back savepoint for record index %d: %w", batchStart+k, rbErr)
+			}
+			failed++
+			pw.reportFailedRecord(batchStart+k, rec, execErr)
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return succeeded, failed, fmt.Errorf("failed to release savepoint for record index %d: %w", batchStart+k, err)
+		}
+		succeeded++
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, failed, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+	return succeeded, failed, nil
+}
+
+// getPostgresColumns returns the columns of table (optionally schema-qualified) mapped to their
+// character_maximum_length from information_schema; columns without a length limit map to 0.
+// The name is resolved like Postgres does (see postgresTableName). An unqualified, unquoted name that
+// matches no table is retried exactly as configured, since the COPY loader quotes the configured name as-is.
+func getPostgresColumns(ctx context.Context, db rowQuerier, table string) (map[string]int, error) {
+	schema, name := postgresTableName(table)
+	candidates := []string{name}
+	if name != table && !strings.ContainsAny(table, `".`) {
+		candidates = append(candidates, table)
+	}
+
+	columns := make(map[string]int)
+	for _, candidate := range candidates {
+		rows, err := db.Query(ctx, `SELECT column_name, character_maximum_length FROM information_schema.columns
+		WHERE table_name = $1 AND table_schema = COALESCE(NULLIF($2, ''), current_schema())`, candidate, schema)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresWriter failed to query columns of table '%s': %w", table, err)
+		}
+		for rows.Next() {
+			var column string
+			var maxLength *int64
+			if err := rows.Scan(&column, &maxLength); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("PostgresWriter failed to read columns of table '%s': %w", table, err)
+			}
+			columns[column] = 0
+			if maxLength != nil {
+				columns[column] = int(*maxLength)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("PostgresWriter failed to read columns of table '%s': %w", table, err)
+		}
+		if len(columns) > 0 {
+			break
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("PostgresWriter found no columns for table '%s'", table)
+	}
+	logging.Logf(logging.Debug, "PostgresWriter: Column length limits for table '%s': %v", table, columns)
+	return columns, nil
+}
+
+// postgresTableName splits a configured table name into its schema ("" if unqualified) and table name, resolving
+// each part like Postgres resolves identifiers: double-quoted parts keep their case (with "" unescaped to "),
+// unquoted parts are folded to lower case. For example, Sales."OrderLines" resolves to sales and OrderLines.
+func postgresTableName(table string) (schema, name string) {
+	var parts []string
+	var part strings.Builder
+	inQuotes, quoted := false, false
+	endPart := func() {
+		if quoted {
+			parts = append(parts, part.String())
+		} else {
+			parts = append(parts, strings.ToLower(strings.TrimSpace(part.String())))
+		}
+		part.Reset()
+		quoted = false
+	}
+	for i := 0; i < len(table); i++ {
+		switch c := table[i]; {
+		case c == '"' && inQuotes && i+1 < len(table) && table[i+1] == '"':
+			part.WriteByte('"')
+			i++
+		case c == '"':
+			inQuotes, quoted = !inQuotes, true
+		case c == '.' && !inQuotes:
+			endPart()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	endPart()
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+// truncateToColumnLengths returns records with string values cut to their column's maximum length in characters,
+// logging a warning for each truncated value. Records that need truncation are copied; the input is not modified.
+func truncateToColumnLengths(records []map[string]interface{}, columnLengths map[string]int, table string) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(records))
+	for i, rec := range records {
+		out[i] = rec
+		copied := false
+		for column, value := range rec {
+			limit := columnLengths[column]
+			str, isStr := value.(string)
+			if limit <= 0 || !isStr || utf8.RuneCountInString(str) <= limit {
+				continue
+			}
+			if !copied {
+				out[i] = make(map[string]interface{}, len(rec))
+				for k, v := range rec {
+					out[i][k] = v
+				}
+				copied = true
+			}
+			out[i][column] = string([]rune(str)[:limit])
+			logging.Logf(logging.Warning, "PostgresWriter: Truncated value of column '%s' in record index %d from %d to %d characters for table '%s'.", column, i, utf8.RuneCountInString(str), limit, table)
+		}
+	}
+	return out
+}
+
+// verifyRowCount compares the number of rows written against the number of records sent
+// and returns an error describing the mismatch, if any.
+func verifyRowCount(mode, table string, expected int, actual int64) error {
+	if actual != int64(expected) {
+		logging.Logf(logging.Error, "PostgresWriter (%s): Row count verification failed for table '%s': expected %d rows, got %d.", mode, table, expected, actual)
+		return fmt.Errorf("PostgresWriter (%s): row count verification failed for table '%s': expected %d rows, got %d", mode, table, expected, actual)
+	}
+	logging.Logf(logging.Debug, "PostgresWriter (%s): Row count verification passed for table '%s' (%d rows).", mode, table, actual)
+	return nil
+}
+
 // Close implements the OutputWriter interface. For PostgresWriter, this is a no-op
 // as database connections/pools are managed entirely within the Write method scope.
 func (pw *PostgresWriter) Close() error {
@@ -11,6 +11,8 @@ import (
 
 	"etl-tool/internal/config"
 	"etl-tool/internal/util" 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -121,6 +123,51 @@ func TestPostgresWriter_Write_EdgeCases(t *testing.T) {
 	// RECOMMENDATION: Use integration tests or refactor for dependency injection.
 }
 
+// TestVerifyRowCount checks the post-load row count comparison used by verify_count.
+func TestVerifyRowCount(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mode       string
+		expected   int
+		actual     int64
+		wantErrMsg string
+	}{
+		{name: "COPY counts match", mode: "COPY", expected: 3, actual: 3},
+		{name: "SQL counts match", mode: "SQL", expected: 0, actual: 0},
+		{name: "COPY mismatch", mode: "COPY", expected: 3, actual: 2, wantErrMsg: "PostgresWriter (COPY): row count verification failed for table 'public.t': expected 3 rows, got 2"},
+		{name: "SQL partial commit mismatch", mode: "SQL", expected: 10, actual: 7, wantErrMsg: "PostgresWriter (SQL): row count verification failed for table 'public.t': expected 10 rows, got 7"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifyRowCount(tc.mode, "public.t", tc.expected, tc.actual)
+			if tc.wantErrMsg == "" {
+				if err != nil {
+					t.Errorf("verifyRowCount() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErrMsg {
+				t.Errorf("verifyRowCount() error = %v, want %q", err, tc.wantErrMsg)
+			}
+		})
+	}
+}
+
+// TestNewOutputWriter_PostgresVerifyCount confirms verify_count is passed to the writer.
+func TestNewOutputWriter_PostgresVerifyCount(t *testing.T) {
+	writer, err := NewOutputWriter(config.DestinationConfig{Type: "postgres", TargetTable: "t", VerifyCount: true}, "postgres://u:p@h/db")
+	if err != nil {
+		t.Fatalf("NewOutputWriter() unexpected error: %v", err)
+	}
+	pgWriter, ok := writer.(*PostgresWriter)
+	if !ok {
+		t.Fatalf("NewOutputWriter() returned %T, want *PostgresWriter", writer)
+	}
+	if !pgWriter.verifyCount {
+		t.Error("PostgresWriter.verifyCount = false, want true")
+	}
+}
+
 // TestPostgresWriter_Close confirms Close is a no-op.
 func TestPostgresWriter_Close(t *testing.T) {
 	writer := NewPostgresWriter("pg://close", "tbl", nil)
@@ -129,3 +176,291 @@ func TestPostgresWriter_Close(t *testing.T) {
 		t.Errorf("Close() returned unexpected error: %v", err)
 	}
 }
+
+// --- Custom SQL partial batch failures ---
+
+// fakeSQLDB is an in-memory stand-in for the custom SQL loader's database: commands insert their first
+// argument, and failOn simulates constraint violations.
+type fakeSQLDB struct {
+	committed []interface{}
+	failOn    func(args []interface{}) error
+}
+
+// fakeTx implements the parts of pgx.Tx used by loadWithCustomSQL. Nested transactions act as savepoints.
+type fakeTx struct {
+	pgx.Tx
+	db      *fakeSQLDB
+	parent  *fakeTx
+	pending []interface{}
+	aborted bool
+}
+
+func (db *fakeSQLDB) Begin(context.Context) (pgx.Tx, error) { return &fakeTx{db: db}, nil }
+
+func (tx *fakeTx) Begin(context.Context) (pgx.Tx, error) { return &fakeTx{db: tx.db, parent: tx}, nil }
+
+func (tx *fakeTx) Exec(_ context.Context, _ string, args ...interface{}) (pgconn.CommandTag, error) {
+	if tx.aborted {
+		return pgconn.CommandTag{}, errors.New("current transaction is aborted, commands ignored until end of transaction block")
+	}
+	if err := tx.db.failOn(args); err != nil {
+		tx.aborted = true
+		return pgconn.CommandTag{}, err
+	}
+	tx.pending = append(tx.pending, args[0])
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	if tx.aborted {
+		return errors.New("commit of aborted transaction")
+	}
+	if tx.parent != nil {
+		tx.parent.pending = append(tx.parent.pending, tx.pending...)
+	} else {
+		tx.db.committed = append(tx.db.committed, tx.pending...)
+	}
+	tx.pending = nil
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error { tx.pending = nil; return nil }
+
+func (tx *fakeTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return &fakeBatchResults{ctx: ctx, tx: tx, queries: b.QueuedQueries}
+}
+
+// fakeBatchResults replays queued batch queries against the fake transaction one Exec at a time.
+type fakeBatchResults struct {
+	pgx.BatchResults
+	ctx     context.Context
+	tx      *fakeTx
+	queries []*pgx.QueuedQuery
+	next    int
+}
+
+func (br *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	q := br.queries[br.next]
+	br.next++
+	return br.tx.Exec(br.ctx, q.SQL, q.Arguments...)
+}
+
+func (br *fakeBatchResults) Close() error { return nil }
+
+// recordingErrorWriter captures records routed to the error writer.
+type recordingErrorWriter struct {
+	records []map[string]interface{}
+	errs    []error
+}
+
+func (w *recordingErrorWriter) Write(record map[string]interface{}, processError error) error {
+	w.records = append(w.records, record)
+	w.errs = append(w.errs, processError)
+	return nil
+}
+
+func (w *recordingErrorWriter) Close() error { return nil }
+
+// TestPostgresWriter_CustomSQLPartialBatchFailure verifies a batch whose 3rd record violates a constraint:
+// halt mode aborts with the failing record index, skip mode routes that record to the error writer and keeps the rest.
+func TestPostgresWriter_CustomSQLPartialBatchFailure(t *testing.T) {
+	records := []map[string]interface{}{{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}, {"id": 6}}
+	newDB := func() *fakeSQLDB {
+		return &fakeSQLDB{failOn: func(args []interface{}) error {
+			if args[0] == 3 {
+				return errors.New("duplicate key value violates unique constraint \"items_pkey\"")
+			}
+			return nil
+		}}
+	}
+	loader := &config.LoaderConfig{Mode: config.LoaderModeSQL, Command: "INSERT INTO items (id) VALUES ($1)", BatchSize: 4}
+
+	t.Run("halt reports failing record index", func(t *testing.T) {
+		db := newDB()
+		writer := NewPostgresWriter("pg://test", "items", loader)
+		err := writer.loadWithCustomSQL(context.Background(), db, records)
+		if err == nil || !strings.Contains(err.Error(), "command for record index 2 (in batch 0-3) failed") || !strings.Contains(err.Error(), "items_pkey") {
+			t.Fatalf("Expected failure naming record index 2, got %v", err)
+		}
+		if len(db.committed) != 0 {
+			t.Errorf("Expected nothing committed, got %v", db.committed)
+		}
+	})
+
+	t.Run("skip routes failed record and continues", func(t *testing.T) {
+		db := newDB()
+		errWriter := &recordingErrorWriter{}
+		writer := NewPostgresWriter("pg://test", "items", loader)
+		var _ ErrorRouter = writer
+		writer.SetErrorRouting(errWriter, true)
+		writer.verifyCount = true // 5 of 6 rows written: verification must see the skipped record
+		err := writer.loadWithCustomSQL(context.Background(), db, records)
+		if err == nil || !strings.Contains(err.Error(), "expected 6 rows, got 5") {
+			t.Fatalf("Expected row count mismatch error, got %v", err)
+		}
+		if want := []interface{}{1, 2, 4, 5, 6}; !reflect.DeepEqual(db.committed, want) {
+			t.Errorf("committed = %v, want %v", db.committed, want)
+		}
+		if len(errWriter.records) != 1 || errWriter.records[0]["id"] != 3 {
+			t.Fatalf("Expected only record id 3 in error writer, got %v", errWriter.records)
+		}
+		if msg := errWriter.errs[0].Error(); !strings.Contains(msg, "record index 2") || !strings.Contains(msg, "items_pkey") {
+			t.Errorf("error writer message = %q, want record index and constraint", msg)
+		}
+	})
+
+	unbatched := &config.LoaderConfig{Mode: config.LoaderModeSQL, Command: loader.Command}
+	t.Run("unbatched halt keeps error writer empty", func(t *testing.T) {
+		errWriter := &recordingErrorWriter{}
+		writer := NewPostgresWriter("pg://test", "items", unbatched)
+		writer.SetErrorRouting(errWriter, false)
+		if err := writer.loadWithCustomSQL(context.Background(), newDB(), records); err != nil {
+			t.Fatalf("loadWithCustomSQL() error = %v", err)
+		}
+		if len(errWriter.records) != 0 {
+			t.Errorf("Expected no records in error writer in halt mode, got %v", errWriter.records)
+		}
+	})
+
+	t.Run("unbatched skip routes failed record", func(t *testing.T) {
+		errWriter := &recordingErrorWriter{}
+		writer := NewPostgresWriter("pg://test", "items", unbatched)
+		writer.SetErrorRouting(errWriter, true)
+		if err := writer.loadWithCustomSQL(context.Background(), newDB(), records); err != nil {
+			t.Fatalf("loadWithCustomSQL() error = %v", err)
+		}
+		if len(errWriter.records) != 1 || errWriter.records[0]["id"] != 3 {
+			t.Errorf("Expected only record id 3 in error writer, got %v", errWriter.records)
+		}
+	})
+}
+
+// --- Column length truncation ---
+
+// fakeColumnRows serves information_schema column rows (name, character_maximum_length) to getPostgresColumns.
+type fakeColumnRows struct {
+	pgx.Rows
+	names   []string
+	lengths []*int64
+	next    int
+}
+
+func (r *fakeColumnRows) Next() bool { r.next++; return r.next <= len(r.names) }
+
+func (r *fakeColumnRows) Scan(dest ...any) error {
+	*dest[0].(*string) = r.names[r.next-1]
+	*dest[1].(**int64) = r.lengths[r.next-1]
+	return nil
+}
+
+func (r *fakeColumnRows) Err() error { return nil }
+
+func (r *fakeColumnRows) Close() {}
+
+// fakeColumnQuerier records the table lookup arguments and returns its rows. If onlyTable is set, lookups of
+// any other table name return no rows.
+type fakeColumnQuerier struct {
+	rows      *fakeColumnRows
+	args      []any
+	calls     [][]any
+	onlyTable string
+}
+
+func (q *fakeColumnQuerier) Query(_ context.Context, _ string, args ...any) (pgx.Rows, error) {
+	q.args = args
+	q.calls = append(q.calls, args)
+	if q.onlyTable != "" && args[0] != q.onlyTable {
+		return &fakeColumnRows{}, nil
+	}
+	return q.rows, nil
+}
+
+// TestGetPostgresColumns verifies column lengths are read and schema-qualified names are split.
+func TestGetPostgresColumns(t *testing.T) {
+	length := func(n int64) *int64 { return &n }
+	q := &fakeColumnQuerier{rows: &fakeColumnRows{names: []string{"code", "name", "notes"}, lengths: []*int64{length(3), length(10), nil}}}
+	got, err := getPostgresColumns(context.Background(), q, "sales.customers")
+	if err != nil {
+		t.Fatalf("getPostgresColumns() unexpected error: %v", err)
+	}
+	if want := map[string]int{"code": 3, "name": 10, "notes": 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getPostgresColumns() = %v, want %v", got, want)
+	}
+	if want := []any{"customers", "sales"}; !reflect.DeepEqual(q.args, want) {
+		t.Errorf("query args = %v, want %v", q.args, want)
+	}
+
+	_, err = getPostgresColumns(context.Background(), &fakeColumnQuerier{rows: &fakeColumnRows{}}, "missing")
+	if err == nil || !strings.Contains(err.Error(), "found no columns for table 'missing'") {
+		t.Errorf("Expected no columns error, got %v", err)
+	}
+}
+
+// TestGetPostgresColumns_MixedCase verifies mixed-case and quoted table names are resolved like Postgres does,
+// falling back to the exact configured name that the COPY loader uses.
+func TestGetPostgresColumns_MixedCase(t *testing.T) {
+	testCases := []struct {
+		table     string
+		onlyTable string // The name the fake database knows the table by
+		wantCalls [][]any
+	}{
+		{"Customers", "customers", [][]any{{"customers", ""}}},
+		{"Sales.Customers", "customers", [][]any{{"customers", "sales"}}},
+		{`"Customers"`, "Customers", [][]any{{"Customers", ""}}},
+		{`Sales."Order ""Lines"".v2"`, `Order "Lines".v2`, [][]any{{`Order "Lines".v2`, "sales"}}},
+		{"MixedCase", "MixedCase", [][]any{{"mixedcase", ""}, {"MixedCase", ""}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.table, func(t *testing.T) {
+			q := &fakeColumnQuerier{rows: &fakeColumnRows{names: []string{"code"}, lengths: []*int64{nil}}, onlyTable: tc.onlyTable}
+			got, err := getPostgresColumns(context.Background(), q, tc.table)
+			if err != nil {
+				t.Fatalf("getPostgresColumns() unexpected error: %v", err)
+			}
+			if want := map[string]int{"code": 0}; !reflect.DeepEqual(got, want) {
+				t.Errorf("getPostgresColumns() = %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(q.calls, tc.wantCalls) {
+				t.Errorf("query calls = %v, want %v", q.calls, tc.wantCalls)
+			}
+		})
+	}
+
+	q := &fakeColumnQuerier{rows: &fakeColumnRows{}}
+	if _, err := getPostgresColumns(context.Background(), q, `"Missing"`); err == nil || len(q.calls) != 1 {
+		t.Errorf("Expected one lookup and a no columns error for a quoted name, got %v after %d calls", err, len(q.calls))
+	}
+}
+
+// TestTruncateToColumnLengths verifies overlong strings are cut to the column limit without modifying the input.
+func TestTruncateToColumnLengths(t *testing.T) {
+	lengths := map[string]int{"code": 3, "name": 5, "notes": 0}
+	records := []map[string]interface{}{
+		{"code": "ABCDE", "name": "Ana", "notes": "unlimited text stays", "qty": 7},
+		{"code": "XY", "name": "Zoë Smith", "notes": nil, "extra": "no such column"},
+		{"code": 12345, "name": "exact"},
+	}
+	got := truncateToColumnLengths(records, lengths, "customers")
+	want := []map[string]interface{}{
+		{"code": "ABC", "name": "Ana", "notes": "unlimited text stays", "qty": 7},
+		{"code": "XY", "name": "Zoë S", "notes": nil, "extra": "no such column"},
+		{"code": 12345, "name": "exact"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("truncateToColumnLengths() = %v, want %v", got, want)
+	}
+	if records[0]["code"] != "ABCDE" || records[1]["name"] != "Zoë Smith" {
+		t.Errorf("input records were modified: %v", records)
+	}
+}
+
+// TestNewOutputWriter_PostgresTruncate confirms truncate_to_column_length is passed to the writer.
+func TestNewOutputWriter_PostgresTruncate(t *testing.T) {
+	writer, err := NewOutputWriter(config.DestinationConfig{Type: "postgres", TargetTable: "t", TruncateToColumnLength: true}, "postgres://u:p@h/db")
+	if err != nil {
+		t.Fatalf("NewOutputWriter() unexpected error: %v", err)
+	}
+	if pgWriter, ok := writer.(*PostgresWriter); !ok || !pgWriter.truncate {
+		t.Errorf("NewOutputWriter() = %#v, want *PostgresWriter with truncate enabled", writer)
+	}
+}
@@ -0,0 +1,91 @@
+package io
+
+import (
+	"fmt"
+	"time"
+
+	"etl-tool/internal/logging"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TOMLReader implements the InputReader interface for TOML files.
+// A document whose only top-level key holds an array of tables (e.g., repeated [[record]] sections)
+// yields one record per table; any other document is read as a single record.
+type TOMLReader struct {
+	open fileOpener // Opens the input file; nil means os.Open (see GzipReader)
+}
+
+// Read loads data from a TOML file specified by filePath.
+func (tr *TOMLReader) Read(filePath string) ([]map[string]interface{}, error) {
+	logging.Logf(logging.Debug, "TOMLReader reading file: %s", filePath)
+	data, err := readInput(tr.open, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("TOMLReader failed to read file '%s': %w", filePath, err)
+	}
+
+	var doc map[string]interface{}
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, fmt.Errorf("TOMLReader failed to parse TOML from '%s': %w", filePath, err)
+	}
+	if len(doc) == 0 {
+		logging.Logf(logging.Debug, "TOMLReader: '%s' has no keys, returning no records", filePath)
+		return []map[string]interface{}{}, nil
+	}
+
+	if len(doc) == 1 {
+		for key, value := range doc {
+			if tables, ok := value.([]map[string]interface{}); ok {
+				records := make([]map[string]interface{}, 0, len(tables))
+				for _, table := range tables {
+					records = append(records, normalizeTOMLTable(table))
+				}
+				logging.Logf(logging.Debug, "TOMLReader successfully loaded %d records (array of tables '%s') from %s", len(records), key, filePath)
+				return records, nil
+			}
+		}
+	}
+
+	logging.Logf(logging.Debug, "TOML input file '%s' is a single table, processing as one record.", filePath)
+	return []map[string]interface{}{normalizeTOMLTable(doc)}, nil
+}
+
+// normalizeTOMLTable converts TOML local date/time values (which have no time zone) to their
+// string form, recursing into nested tables and arrays. Other values are kept as decoded.
+func normalizeTOMLTable(table map[string]interface{}) map[string]interface{} {
+	for key, value := range table {
+		table[key] = normalizeTOMLValue(value)
+	}
+	return table
+}
+
+// normalizeTOMLValue converts a single decoded TOML value; see normalizeTOMLTable.
+func normalizeTOMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		// The decoder marks local values with these zone names (see toml's internal/tz.go).
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format("2006-01-02")
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		}
+		return v
+	case map[string]interface{}:
+		return normalizeTOMLTable(v)
+	case []map[string]interface{}:
+		for i := range v {
+			v[i] = normalizeTOMLTable(v[i])
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeTOMLValue(v[i])
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,112 @@
+package io
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- Test TOMLReader ---
+
+func TestTOMLReader_Read(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tomlContent string
+		wantRecords []map[string]interface{}
+		wantErrMsg  string // Substring expected in the error; empty means no error
+	}{
+		{
+			name: "Array of tables",
+			tomlContent: `[[record]]
+id = 1
+name = "Alice"
+active = true
+
+[[record]]
+id = 2
+name = "Bob"
+score = 9.5
+tags = ["dev", "qa"]
+
+[record.address]
+city = "Oslo"
+`,
+			wantRecords: []map[string]interface{}{
+				{"id": int64(1), "name": "Alice", "active": true},
+				{"id": int64(2), "name": "Bob", "score": 9.5, "tags": []interface{}{"dev", "qa"}, "address": map[string]interface{}{"city": "Oslo"}},
+			},
+		},
+		{
+			name: "Single table",
+			tomlContent: `id = 100
+value = "single entry"
+created = 2024-03-01T10:30:00Z
+due = 2024-04-01
+opens = 09:30:00
+local = 2024-04-01T08:00:00
+
+[owner]
+name = "Carol"
+`,
+			wantRecords: []map[string]interface{}{
+				{
+					"id":      int64(100),
+					"value":   "single entry",
+					"created": time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+					"due":     "2024-04-01",
+					"opens":   "09:30:00",
+					"local":   "2024-04-01T08:00:00",
+					"owner":   map[string]interface{}{"name": "Carol"},
+				},
+			},
+		},
+		{
+			name: "Array of tables next to other keys is a single record",
+			tomlContent: `title = "batch"
+
+[[item]]
+sku = "A1"
+`,
+			wantRecords: []map[string]interface{}{
+				{"title": "batch", "item": []map[string]interface{}{{"sku": "A1"}}},
+			},
+		},
+		{
+			name:        "Empty file",
+			tomlContent: "",
+			wantRecords: []map[string]interface{}{},
+		},
+		{
+			name:        "Malformed TOML",
+			tomlContent: "id = \n",
+			wantErrMsg:  "TOMLReader failed to parse TOML",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempFile(t, tc.tomlContent, "test_*.toml")
+			reader := TOMLReader{}
+			gotRecords, err := reader.Read(filePath)
+
+			if tc.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrMsg) {
+					t.Fatalf("Read() error = %v, want error containing %q", err, tc.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() unexpected error: %v", err)
+			}
+			compareRecordsDeep(t, gotRecords, tc.wantRecords)
+		})
+	}
+
+	t.Run("File not found", func(t *testing.T) {
+		reader := TOMLReader{}
+		_, err := reader.Read("nonexistent_file.toml")
+		if err == nil || !strings.Contains(err.Error(), "TOMLReader failed to read file") {
+			t.Errorf("Read() error = %v, want file read error", err)
+		}
+	})
+}
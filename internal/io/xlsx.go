@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"etl-tool/internal/config"
 	"etl-tool/internal/logging"
@@ -16,8 +17,13 @@ import (
 
 // XLSXReader implements the InputReader interface for Excel (.xlsx) files.
 type XLSXReader struct {
-	sheetName  string
-	sheetIndex *int
+	sheetName      string
+	sheetIndex     *int
+	typedCells     bool       // Return numbers as float64, dates as time.Time, and booleans as bool instead of display strings.
+	skipHeaderRows int        // Rows to discard before the header.
+	skipFooterRows int        // Trailing rows to discard.
+	maxRows        int        // Maximum data rows to keep (0 = unlimited).
+	open           fileOpener // Opens the input file; nil means os.Open (see GzipReader).
 }
 
 // NewXLSXReader creates a new XLSXReader with sheet preferences.
@@ -28,11 +34,21 @@ func NewXLSXReader(sheetName string, sheetIndex *int) *XLSXReader {
 	}
 }
 
+// openWorkbook reads filePath, opened through xr.open (see openInput), as a workbook.
+func (xr *XLSXReader) openWorkbook(filePath string) (*excelize.File, error) {
+	in, err := openInput(xr.open, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	return excelize.OpenReader(in)
+}
+
 // Read loads data from the specified sheet (or default) of an Excel file.
 func (xr *XLSXReader) Read(filePath string) ([]map[string]interface{}, error) {
 	logging.Logf(logging.Debug, "XLSXReader reading file: %s (SheetName: '%s', SheetIndex: %v)", filePath, xr.sheetName, xr.sheetIndex)
 
-	f, err := excelize.OpenFile(filePath)
+	f, err := xr.openWorkbook(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("XLSXReader failed to open file '%s': %w", filePath, err)
 	}
@@ -92,6 +108,8 @@ func (xr *XLSXReader) Read(filePath string) ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("XLSXReader failed to get rows from sheet '%s' in '%s': %w", targetSheetName, filePath, err)
 	}
 
+	rows = trimSourceRows(rows, xr.skipHeaderRows, xr.skipFooterRows, xr.maxRows, filePath)
+
 	// --- MODIFIED: Initialize records slice directly ---
 	// Ensure we always return an initialized slice, not nil, if no data rows are processed.
 	records := make([]map[string]interface{}, 0)
@@ -135,9 +153,16 @@ func (xr *XLSXReader) Read(filePath string) ([]map[string]interface{}, error) {
 	logging.Logf(logging.Debug, "XLSXReader: Using unique headers (last wins): %v", validHeadersOrdered)
 
 
+	date1904 := false
+	if xr.typedCells {
+		if props, err := f.GetWorkbookProps(); err == nil && props.Date1904 != nil {
+			date1904 = *props.Date1904
+		}
+	}
+
 	// Data row processing loop remains the same...
 	for i, row := range rows[1:] { // This loop correctly handles len(rows) == 1 (no iterations)
-		rowNum := i + 2
+		rowNum := i + 2 + xr.skipHeaderRows // Excel row number, accounting for skipped banner rows
 		rec := make(map[string]interface{}, len(validHeadersMap))
 		for cellIdx := 0; cellIdx < len(row); cellIdx++ {
 			headerName, indexHasHeader := headerNameForIndex[cellIdx]
@@ -151,6 +176,8 @@ func (xr *XLSXReader) Read(filePath string) ([]map[string]interface{}, error) {
 				if err != nil {
 					logging.Logf(logging.Warning, "XLSXReader: Failed to get calculated value for cell %s on sheet '%s': %v. Using raw value '%s'.", cellName, targetSheetName, err, cellValue)
 					rec[headerName] = cellValue
+				} else if xr.typedCells {
+					rec[headerName] = xr.typedCellValue(f, targetSheetName, cellName, cellDisplayValue, date1904)
 				} else {
 					rec[headerName] = cellDisplayValue
 				}
@@ -169,11 +196,110 @@ func (xr *XLSXReader) Read(filePath string) ([]map[string]interface{}, error) {
 	return records, nil // Return the (potentially empty) initialized slice
 }
 
+// typedCellValue converts a cell to a native Go type when typed_cells is enabled.
+// Numeric cells become float64, or time.Time when the cell's number format is a date/time format;
+// boolean cells become bool. Empty numeric cells and all other cell types keep their display string.
+func (xr *XLSXReader) typedCellValue(f *excelize.File, sheet, cellName, displayValue string, date1904 bool) interface{} {
+	cellType, err := f.GetCellType(sheet, cellName)
+	if err != nil {
+		logging.Logf(logging.Warning, "XLSXReader: Failed to get type of cell %s on sheet '%s': %v. Using display value.", cellName, sheet, err)
+		return displayValue
+	}
+	rawValue, err := f.GetCellValue(sheet, cellName, excelize.Options{RawCellValue: true})
+	if err != nil || rawValue == "" {
+		return displayValue
+	}
+
+	switch cellType {
+	case excelize.CellTypeBool:
+		return rawValue == "1" || strings.EqualFold(rawValue, "true")
+	case excelize.CellTypeDate:
+		// ISO 8601 date cells ("d" type) are rare but valid.
+		if t, parseErr := time.Parse(time.RFC3339, rawValue); parseErr == nil {
+			return t
+		}
+		if t, parseErr := time.Parse("2006-01-02T15:04:05", rawValue); parseErr == nil {
+			return t
+		}
+		return displayValue
+	case excelize.CellTypeUnset, excelize.CellTypeNumber:
+		num, parseErr := strconv.ParseFloat(rawValue, 64)
+		if parseErr != nil {
+			return displayValue
+		}
+		if xr.isDateFormattedCell(f, sheet, cellName) {
+			t, dateErr := excelize.ExcelDateToTime(num, date1904)
+			if dateErr != nil {
+				logging.Logf(logging.Warning, "XLSXReader: Failed to convert date serial %v in cell %s on sheet '%s': %v. Using numeric value.", num, cellName, sheet, dateErr)
+				return num
+			}
+			return t
+		}
+		return num
+	default:
+		return displayValue
+	}
+}
+
+// isDateFormattedCell reports whether the cell's style uses a built-in or custom date/time number format.
+func (xr *XLSXReader) isDateFormattedCell(f *excelize.File, sheet, cellName string) bool {
+	styleID, err := f.GetCellStyle(sheet, cellName)
+	if err != nil || styleID == 0 {
+		return false
+	}
+	style, err := f.GetStyle(styleID)
+	if err != nil || style == nil {
+		return false
+	}
+	if style.CustomNumFmt != nil {
+		return isDateNumFmtCode(*style.CustomNumFmt)
+	}
+	switch {
+	case style.NumFmt >= 14 && style.NumFmt <= 22, style.NumFmt >= 45 && style.NumFmt <= 47:
+		return true
+	case style.NumFmt >= 27 && style.NumFmt <= 36, style.NumFmt >= 50 && style.NumFmt <= 58:
+		return true // East Asian locale date formats
+	}
+	return false
+}
+
+// isDateNumFmtCode reports whether a custom number format code contains date/time tokens
+// (y, m, d, h, s) outside of quoted literals, escapes, and bracketed sections like colors or locales.
+func isDateNumFmtCode(code string) bool {
+	inQuotes, inBrackets := false, false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case c == '\\':
+			i++ // skip escaped literal
+		case c == '[':
+			inBrackets = true
+		case c == ']':
+			inBrackets = false
+		case inBrackets:
+		default:
+			switch c {
+			case 'y', 'Y', 'd', 'D', 'h', 'H', 's', 'S', 'm', 'M':
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // --- XLSXWriter code remains the same as previous correction ---
 // (Includes boolean casing fix)
 // XLSXWriter implements the OutputWriter interface for Excel (.xlsx) files.
 type XLSXWriter struct {
-	sheetName string
+	sheetName     string
+	boldHeader    bool              // Apply a bold font to the header row.
+	freezeHeader  bool              // Freeze the header row so it stays visible while scrolling.
+	numberFormats map[string]string // Column name -> Excel number format code applied to data cells.
+	fieldOrder    []string          // Optional leading column order (see SetFieldOrder).
+	create        fileCreator       // Creates the output file; nil lets excelize save it (see GzipWriter).
 }
 
 // NewXLSXWriter creates a new XLSXWriter.
@@ -187,6 +313,11 @@ func NewXLSXWriter(sheetName string) *XLSXWriter {
 	}
 }
 
+// SetFieldOrder implements FieldOrderer.
+func (xw *XLSXWriter) SetFieldOrder(fields []string) {
+	xw.fieldOrder = fields
+}
+
 // Write saves the provided records to the specified sheet of an Excel file.
 func (xw *XLSXWriter) Write(records []map[string]interface{}, filePath string) error {
 	logging.Logf(logging.Debug, "XLSXWriter writing %d records to file: %s (Sheet: '%s')", len(records), filePath, xw.sheetName)
@@ -247,23 +378,19 @@ func (xw *XLSXWriter) Write(records []map[string]interface{}, filePath string) e
 
 	if len(records) == 0 {
 		logging.Logf(logging.Info, "XLSXWriter: No records provided, saving empty file %s with sheet '%s'.", filePath, targetSheetName)
-		if err := f.SaveAs(filePath); err != nil {
+		if err := xw.saveWorkbook(f, filePath); err != nil {
 			return fmt.Errorf("XLSXWriter failed to save empty file '%s': %w", filePath, err)
 		}
 		return nil
 	}
 
-	var headers []string
 	headerSet := make(map[string]struct{})
 	for _, rec := range records {
 		for k := range rec {
-			if _, exists := headerSet[k]; !exists {
-				headerSet[k] = struct{}{}
-				headers = append(headers, k)
-			}
+			headerSet[k] = struct{}{}
 		}
 	}
-	sort.Strings(headers)
+	headers := orderFields(headerSet, xw.fieldOrder)
 
 	headerRowInterface := make([]interface{}, len(headers))
 	for i, h := range headers {
@@ -272,6 +399,9 @@ func (xw *XLSXWriter) Write(records []map[string]interface{}, filePath string) e
 	if err := f.SetSheetRow(targetSheetName, "A1", &headerRowInterface); err != nil {
 		return fmt.Errorf("XLSXWriter failed to write header row to sheet '%s': %w", targetSheetName, err)
 	}
+	if err := xw.applyHeaderOptions(f, targetSheetName, len(headers)); err != nil {
+		return err
+	}
 
 	for i, rec := range records {
 		rowNum := i + 2
@@ -295,7 +425,11 @@ func (xw *XLSXWriter) Write(records []map[string]interface{}, filePath string) e
 		}
 	}
 
-	if err := f.SaveAs(filePath); err != nil {
+	if err := xw.applyNumberFormats(f, targetSheetName, headers, len(records)); err != nil {
+		return err
+	}
+
+	if err := xw.saveWorkbook(f, filePath); err != nil {
 		return fmt.Errorf("XLSXWriter failed to save file '%s': %w", filePath, err)
 	}
 
@@ -303,6 +437,95 @@ func (xw *XLSXWriter) Write(records []map[string]interface{}, filePath string) e
 	return nil
 }
 
+// saveWorkbook saves f to filePath, writing it through xw.create when set. excelize.SaveAs is used otherwise,
+// so plain output keeps its check that the file name has a workbook extension.
+func (xw *XLSXWriter) saveWorkbook(f *excelize.File, filePath string) error {
+	if xw.create == nil {
+		return f.SaveAs(filePath)
+	}
+	out, err := xw.create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := f.Write(out); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// applyHeaderOptions styles the header row (bold_header) and freezes it (freeze_header) when enabled.
+func (xw *XLSXWriter) applyHeaderOptions(f *excelize.File, sheetName string, headerCount int) error {
+	if xw.boldHeader && headerCount > 0 {
+		styleID, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		if err != nil {
+			return fmt.Errorf("XLSXWriter failed to create bold header style: %w", err)
+		}
+		lastCell, err := excelize.CoordinatesToCellName(headerCount, 1)
+		if err != nil {
+			return fmt.Errorf("XLSXWriter failed to calculate header range: %w", err)
+		}
+		if err := f.SetCellStyle(sheetName, "A1", lastCell, styleID); err != nil {
+			return fmt.Errorf("XLSXWriter failed to apply bold header style on sheet '%s': %w", sheetName, err)
+		}
+	}
+	if xw.freezeHeader {
+		panes := &excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		}
+		if err := f.SetPanes(sheetName, panes); err != nil {
+			return fmt.Errorf("XLSXWriter failed to freeze header row on sheet '%s': %w", sheetName, err)
+		}
+	}
+	return nil
+}
+
+// applyNumberFormats applies the configured number format codes to the data cells (rows 2..rowCount+1)
+// of each matching column. Formats for columns not present in the output are logged and skipped.
+func (xw *XLSXWriter) applyNumberFormats(f *excelize.File, sheetName string, headers []string, rowCount int) error {
+	if len(xw.numberFormats) == 0 || rowCount == 0 {
+		return nil
+	}
+	columnIndex := make(map[string]int, len(headers))
+	for i, h := range headers {
+		columnIndex[h] = i + 1
+	}
+
+	columns := make([]string, 0, len(xw.numberFormats))
+	for column := range xw.numberFormats {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		colNum, exists := columnIndex[column]
+		if !exists {
+			logging.Logf(logging.Warning, "XLSXWriter: number_format specified for column '%s' which is not present in the output; ignoring.", column)
+			continue
+		}
+		format := xw.numberFormats[column]
+		styleID, err := f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+		if err != nil {
+			return fmt.Errorf("XLSXWriter failed to create number format '%s' for column '%s': %w", format, column, err)
+		}
+		firstCell, err := excelize.CoordinatesToCellName(colNum, 2)
+		if err != nil {
+			return fmt.Errorf("XLSXWriter failed to calculate range for column '%s': %w", column, err)
+		}
+		lastCell, err := excelize.CoordinatesToCellName(colNum, rowCount+1)
+		if err != nil {
+			return fmt.Errorf("XLSXWriter failed to calculate range for column '%s': %w", column, err)
+		}
+		if err := f.SetCellStyle(sheetName, firstCell, lastCell, styleID); err != nil {
+			return fmt.Errorf("XLSXWriter failed to apply number format to column '%s' on sheet '%s': %w", column, sheetName, err)
+		}
+	}
+	return nil
+}
+
 // Close implements the OutputWriter interface.
 func (xw *XLSXWriter) Close() error {
 	logging.Logf(logging.Debug, "XLSXWriter Close called (no-op).")
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"etl-tool/internal/config" // For default sheet name constant
 	"github.com/xuri/excelize/v2"
@@ -336,6 +337,113 @@ func TestXLSXReader_Read(t *testing.T) {
 	})
 }
 
+// TestXLSXReader_TypedCells verifies that typed_cells returns native numbers, dates, and booleans.
+func TestXLSXReader_TypedCells(t *testing.T) {
+	orderDate := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	filePath := createTempXLSX(t, "Data", [][]interface{}{
+		{"Name", "Amount", "OrderDate", "Active", "Blank"},
+		{"Widget", 12.5, orderDate, true, nil},
+		{"Gadget", 3, "not a date", false, nil},
+	})
+
+	// Add a custom date format cell and a number stored with a currency format.
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to reopen XLSX: %v", err)
+	}
+	customDate := "dd/mm/yyyy"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &customDate})
+	if err != nil {
+		t.Fatalf("NewStyle failed: %v", err)
+	}
+	currency := "$#,##0.00"
+	currencyStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &currency})
+	if err != nil {
+		t.Fatalf("NewStyle failed: %v", err)
+	}
+	_ = f.SetCellValue("Data", "F1", "Shipped")
+	_ = f.SetCellValue("Data", "F2", 45366) // 2024-03-15 as a date serial
+	_ = f.SetCellStyle("Data", "F2", "F2", dateStyle)
+	_ = f.SetCellValue("Data", "G1", "Price")
+	_ = f.SetCellValue("Data", "G2", 1234.5)
+	_ = f.SetCellStyle("Data", "G2", "G2", currencyStyle)
+	if err := f.Save(); err != nil {
+		t.Fatalf("Failed to save XLSX: %v", err)
+	}
+	_ = f.Close()
+
+	reader, err := NewInputReader(config.SourceConfig{Type: "xlsx", File: filePath, TypedCells: true}, "")
+	if err != nil {
+		t.Fatalf("NewInputReader() error = %v", err)
+	}
+	got, err := reader.Read(filePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []map[string]interface{}{
+		{"Name": "Widget", "Amount": 12.5, "OrderDate": orderDate, "Active": true, "Blank": "", "Shipped": orderDate, "Price": 1234.5},
+		{"Name": "Gadget", "Amount": 3.0, "OrderDate": "not a date", "Active": false, "Blank": "", "Shipped": "", "Price": ""},
+	}
+	compareRecordsDeep(t, got, want)
+
+	// Without typed_cells the same file yields display strings.
+	untyped, err := NewXLSXReader("", nil).Read(filePath)
+	if err != nil {
+		t.Fatalf("Read() without typed_cells error = %v", err)
+	}
+	if _, isString := untyped[0]["Amount"].(string); !isString {
+		t.Errorf("Amount without typed_cells = %T, want string", untyped[0]["Amount"])
+	}
+	if _, isString := untyped[0]["OrderDate"].(string); !isString {
+		t.Errorf("OrderDate without typed_cells = %T, want string", untyped[0]["OrderDate"])
+	}
+}
+
+func TestIsDateNumFmtCode(t *testing.T) {
+	testCases := []struct {
+		code string
+		want bool
+	}{
+		{"yyyy-mm-dd", true},
+		{"dd/mm/yyyy hh:mm", true},
+		{"[h]:mm:ss", true},
+		{"$#,##0.00", false},
+		{"0.00%", false},
+		{"0.00E+00", false},
+		{`#,##0 "days"`, false},
+		{`0\d`, false},
+		{"[Red]#,##0", false},
+		{"[$-409]mmmm d, yyyy", true},
+	}
+	for _, tc := range testCases {
+		if got := isDateNumFmtCode(tc.code); got != tc.want {
+			t.Errorf("isDateNumFmtCode(%q) = %v, want %v", tc.code, got, tc.want)
+		}
+	}
+}
+
+// TestXLSXReader_RowWindow verifies banner/footer skipping and row caps, including cell lookups after skipped rows.
+func TestXLSXReader_RowWindow(t *testing.T) {
+	filePath := createTempXLSX(t, "Data", [][]interface{}{
+		{"Quarterly Report"},
+		{"Region", "Sales"},
+		{"North", 100},
+		{"South", 200},
+		{"East", 300},
+		{"Total", 600},
+	})
+	reader, err := NewInputReader(config.SourceConfig{Type: "xlsx", File: filePath, SkipHeaderRows: 1, SkipFooterRows: 1, MaxRows: 2, TypedCells: true}, "")
+	if err != nil {
+		t.Fatalf("NewInputReader() error = %v", err)
+	}
+	got, err := reader.Read(filePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := []map[string]interface{}{{"Region": "North", "Sales": 100.0}, {"Region": "South", "Sales": 200.0}}
+	compareRecordsDeep(t, got, want)
+}
+
 // --- Test XLSXWriter ---
 
 func TestNewXLSXWriter(t *testing.T) {
@@ -524,6 +632,129 @@ func TestXLSXWriter_Write(t *testing.T) {
 	})
 }
 
+// TestXLSXWriter_HeaderOptions verifies bold_header and freeze_header are applied to the written file.
+func TestXLSXWriter_HeaderOptions(t *testing.T) {
+	records := []map[string]interface{}{{"A": 1, "B": "x"}, {"A": 2, "B": "y"}}
+
+	testCases := []struct {
+		name         string
+		boldHeader   bool
+		freezeHeader bool
+	}{
+		{"Neither", false, false},
+		{"Bold only", true, false},
+		{"Freeze only", false, true},
+		{"Bold and freeze", true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "out.xlsx")
+			writer, err := NewOutputWriter(config.DestinationConfig{Type: "xlsx", File: filePath, BoldHeader: tc.boldHeader, FreezeHeader: tc.freezeHeader}, "")
+			if err != nil {
+				t.Fatalf("NewOutputWriter() error = %v", err)
+			}
+			if err := writer.Write(records, filePath); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			f, err := excelize.OpenFile(filePath)
+			if err != nil {
+				t.Fatalf("Failed to open output: %v", err)
+			}
+			defer f.Close()
+			sheet := config.DefaultSheetName
+
+			for _, cell := range []string{"A1", "B1", "A2"} {
+				styleID, err := f.GetCellStyle(sheet, cell)
+				if err != nil {
+					t.Fatalf("GetCellStyle(%s) error = %v", cell, err)
+				}
+				style, err := f.GetStyle(styleID)
+				if err != nil {
+					t.Fatalf("GetStyle(%d) error = %v", styleID, err)
+				}
+				gotBold := style.Font != nil && style.Font.Bold
+				wantBold := tc.boldHeader && cell != "A2"
+				if gotBold != wantBold {
+					t.Errorf("cell %s bold = %v, want %v", cell, gotBold, wantBold)
+				}
+			}
+
+			panes, err := f.GetPanes(sheet)
+			if err != nil {
+				t.Fatalf("GetPanes() error = %v", err)
+			}
+			if panes.Freeze != tc.freezeHeader {
+				t.Errorf("panes.Freeze = %v, want %v", panes.Freeze, tc.freezeHeader)
+			}
+			if tc.freezeHeader && (panes.YSplit != 1 || panes.TopLeftCell != "A2") {
+				t.Errorf("panes = %+v, want YSplit 1 and TopLeftCell A2", panes)
+			}
+		})
+	}
+}
+
+// TestXLSXWriter_NumberFormat verifies number_format styles are applied to the data cells of the right columns.
+func TestXLSXWriter_NumberFormat(t *testing.T) {
+	records := []map[string]interface{}{
+		{"Amount": 1234.5, "Name": "a", "Shipped": time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"Amount": 99.99, "Name": "b", "Shipped": time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	filePath := filepath.Join(t.TempDir(), "formatted.xlsx")
+	writer, err := NewOutputWriter(config.DestinationConfig{
+		Type: "xlsx", File: filePath,
+		NumberFormat: map[string]string{"Amount": "$#,##0.00", "Shipped": "yyyy-mm-dd", "Missing": "0.0"},
+	}, "")
+	if err != nil {
+		t.Fatalf("NewOutputWriter() error = %v", err)
+	}
+	if err := writer.Write(records, filePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to open output: %v", err)
+	}
+	defer f.Close()
+	sheet := config.DefaultSheetName
+
+	customFormat := func(cell string) string {
+		t.Helper()
+		styleID, err := f.GetCellStyle(sheet, cell)
+		if err != nil {
+			t.Fatalf("GetCellStyle(%s) error = %v", cell, err)
+		}
+		style, err := f.GetStyle(styleID)
+		if err != nil {
+			t.Fatalf("GetStyle(%d) error = %v", styleID, err)
+		}
+		if style.CustomNumFmt == nil {
+			return ""
+		}
+		return *style.CustomNumFmt
+	}
+
+	// Headers sort to Amount (A), Name (B), Shipped (C).
+	wantFormats := map[string]string{
+		"A1": "", "A2": "$#,##0.00", "A3": "$#,##0.00",
+		"B2": "", "B3": "",
+		"C1": "", "C2": "yyyy-mm-dd", "C3": "yyyy-mm-dd",
+	}
+	for cell, want := range wantFormats {
+		if got := customFormat(cell); got != want {
+			t.Errorf("cell %s number format = %q, want %q", cell, got, want)
+		}
+	}
+	if got, _ := f.GetCellValue(sheet, "A2"); got != "$1,234.50" {
+		t.Errorf("formatted A2 = %q, want %q", got, "$1,234.50")
+	}
+	if got, _ := f.GetCellValue(sheet, "C2"); got != "2024-03-15" {
+		t.Errorf("formatted C2 = %q, want %q", got, "2024-03-15")
+	}
+}
+
 func TestXLSXWriter_Close(t *testing.T) {
 	writer := NewXLSXWriter("TestSheet")
 	err := writer.Close()
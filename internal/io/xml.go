@@ -19,7 +19,9 @@ import (
 // by recordTag contain simple key-value fields.
 // It reads the character data within field tags, including nested tags' data flattened.
 type XMLReader struct {
-	recordTag string
+	recordTag  string
+	attrFilter *config.XMLAttributeFilterConfig // Optional: keep only record elements with this attribute value
+	open       fileOpener                       // Opens the input file; nil means os.Open (see GzipReader)
 }
 
 // NewXMLReader creates a new XMLReader.
@@ -38,7 +40,7 @@ func NewXMLReader(recordTag string) *XMLReader {
 func (xr *XMLReader) Read(filePath string) ([]map[string]interface{}, error) {
 	logging.Logf(logging.Debug, "XMLReader reading file: %s (Record Tag: '%s')", filePath, xr.recordTag)
 
-	file, err := os.Open(filePath)
+	file, err := openInput(xr.open, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("XMLReader failed to open file '%s': %w", filePath, err)
 	}
@@ -73,13 +75,18 @@ func (xr *XMLReader) Read(filePath string) ([]map[string]interface{}, error) {
 		case xml.StartElement:
 			if elementDepth == 0 && se.Name.Local == xr.recordTag { // Start of a NEW record
 				currentRecord = make(map[string]interface{})
+				if !xr.matchesAttrFilter(se) {
+					currentRecord = nil // Skip this record's fields and content; depth is still tracked below
+				}
 				currentFieldElement = nil // Reset field tracking
 				elementValue.Reset()
 				elementDepth++ // Enter record level
-			} else if elementDepth == 1 && currentRecord != nil { // Start of a FIELD within the record
-				currentFieldElement = &se // Store the field's start element
-				elementValue.Reset()      // Prepare to capture its value
-				elementDepth++            // Enter field level
+			} else if elementDepth == 1 { // Start of a FIELD within the record
+				if currentRecord != nil { // Only capture fields of records that passed the attribute filter
+					currentFieldElement = &se // Store the field's start element
+					elementValue.Reset()      // Prepare to capture its value
+				}
+				elementDepth++ // Enter field level
 			} else if elementDepth > 1 { // Nested element within a field
 				elementDepth++ // Just track depth
 			}
@@ -130,15 +137,32 @@ func (xr *XMLReader) Read(filePath string) ([]map[string]interface{}, error) {
 	return records, nil
 }
 
+// matchesAttrFilter reports whether a record start element passes the configured attribute filter.
+func (xr *XMLReader) matchesAttrFilter(se xml.StartElement) bool {
+	if xr.attrFilter == nil {
+		return true
+	}
+	for _, attr := range se.Attr {
+		if attr.Name.Local == xr.attrFilter.Name {
+			return attr.Value == xr.attrFilter.Value
+		}
+	}
+	return false
+}
+
 // --- XML Writer ---
 
 // XMLWriter implements the OutputWriter interface for XML files.
 // It generates a flat XML structure with a specified root element and
 // repeating record elements containing simple key-value fields.
-// It does not currently support writing XML attributes or nested structures.
+// Fields listed in attributeFields are written as attributes of the record element.
+// Nested map values (e.g., from nestFields) are written as child elements with sorted keys.
 type XMLWriter struct {
-	recordTag string
-	rootTag   string
+	recordTag       string
+	rootTag         string
+	fieldOrder      []string    // Optional leading field element order (see SetFieldOrder)
+	attributeFields []string    // Optional fields rendered as record element attributes, in this order
+	create          fileCreator // Creates the output file; nil means a plain file (see GzipWriter)
 }
 
 // NewXMLWriter creates a new XMLWriter.
@@ -157,6 +181,11 @@ func NewXMLWriter(recordTag, rootTag string) *XMLWriter {
 	}
 }
 
+// SetFieldOrder implements FieldOrderer.
+func (xw *XMLWriter) SetFieldOrder(fields []string) {
+	xw.fieldOrder = fields
+}
+
 // Write saves the provided records as an XML structure to the specified file.
 // Uses an encoder with indentation for readability.
 func (xw *XMLWriter) Write(records []map[string]interface{}, filePath string) error {
@@ -171,15 +200,27 @@ func (xw *XMLWriter) Write(records []map[string]interface{}, filePath string) er
 	}
 
 	// Create or truncate the output file
-	file, err := os.Create(filePath)
+	file, err := createOutput(xw.create, filePath)
 	if err != nil {
 		return fmt.Errorf("XMLWriter failed to create file '%s': %w", filePath, err)
 	}
-	// Ensure file is closed reliably
-	defer file.Close()
+	// Close explicitly: for compressed output, Close writes the gzip trailer and can fail
+	if err := xw.writeDocument(file, records, filePath); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("XMLWriter failed to close file '%s': %w", filePath, err)
+	}
+
+	logging.Logf(logging.Info, "XMLWriter successfully wrote %d records to %s", len(records), filePath)
+	return nil
+}
 
+// writeDocument writes the XML header, the root element, and one record element per record to file.
+func (xw *XMLWriter) writeDocument(file io.Writer, records []map[string]interface{}, filePath string) error {
 	// Write standard XML header
-	if _, err = file.WriteString(xml.Header); err != nil {
+	if _, err := io.WriteString(file, xml.Header); err != nil {
 		return fmt.Errorf("XMLWriter failed to write XML header to '%s': %w", filePath, err)
 	}
 
@@ -193,45 +234,46 @@ func (xw *XMLWriter) Write(records []map[string]interface{}, filePath string) er
 		return fmt.Errorf("XMLWriter failed to encode root start element <%s>: %w", xw.rootTag, err)
 	}
 
-	// Define the record start element (reused)
-	recordStartElem := xml.StartElement{Name: xml.Name{Local: xw.recordTag}}
+	attrSet := make(map[string]struct{}, len(xw.attributeFields))
+	for _, f := range xw.attributeFields {
+		attrSet[f] = struct{}{}
+	}
 
 	// Iterate through records and encode each one
 	// Ranging over a nil slice is safe and does nothing, so the nil check is removed.
 	for i, rec := range records {
+		// Build the record start element, adding configured attribute fields present in this record
+		recordStartElem := xml.StartElement{Name: xml.Name{Local: xw.recordTag}}
+		for _, field := range xw.attributeFields {
+			value, ok := rec[field]
+			if !ok {
+				continue
+			}
+			stringValue := ""
+			if value != nil {
+				stringValue = fmt.Sprintf("%v", value)
+			}
+			recordStartElem.Attr = append(recordStartElem.Attr, xml.Attr{Name: xml.Name{Local: field}, Value: stringValue})
+		}
+
 		// Encode record start tag
 		if err := encoder.EncodeToken(recordStartElem); err != nil {
 			return fmt.Errorf("XMLWriter failed to encode record start element <%s> for record %d: %w", xw.recordTag, i, err)
 		}
 
-		// Sort keys for consistent field order within each record
-		keys := make([]string, 0, len(rec))
+		// Apply the configured field order, then sort the rest for consistent order within each record
+		keySet := make(map[string]struct{}, len(rec))
 		for k := range rec {
-			keys = append(keys, k)
+			if _, isAttr := attrSet[k]; !isAttr {
+				keySet[k] = struct{}{}
+			}
 		}
-		sort.Strings(keys)
+		keys := orderFields(keySet, xw.fieldOrder)
 
-		// Encode each key-value pair as a field element
+		// Encode each key-value pair as a field element; nested maps become child elements
 		for _, key := range keys {
-			value := rec[key]
-			// Convert value to string; handle nil as empty string
-			stringValue := ""
-			if value != nil {
-				stringValue = fmt.Sprintf("%v", value)
-			}
-
-			fieldElement := xml.StartElement{Name: xml.Name{Local: key}}
-			// Encode field start tag
-			if err := encoder.EncodeToken(fieldElement); err != nil {
-				return fmt.Errorf("XMLWriter failed to encode field start element <%s> for record %d: %w", key, i, err)
-			}
-			// Encode field value (character data) - Encoder handles escaping
-			if err := encoder.EncodeToken(xml.CharData(stringValue)); err != nil {
-				return fmt.Errorf("XMLWriter failed to encode field value for <%s> for record %d: %w", key, i, err)
-			}
-			// Encode field end tag
-			if err := encoder.EncodeToken(fieldElement.End()); err != nil {
-				return fmt.Errorf("XMLWriter failed to encode field end element </%s> for record %d: %w", key, i, err)
+			if err := encodeXMLField(encoder, key, rec[key], i); err != nil {
+				return err
 			}
 		}
 
@@ -252,17 +294,52 @@ func (xw *XMLWriter) Write(records []map[string]interface{}, filePath string) er
 	}
 
 	// Add a final newline for POSIX compatibility / aesthetics
-	if _, err = file.WriteString("\n"); err != nil {
+	if _, err := io.WriteString(file, "\n"); err != nil {
 		// Non-fatal warning if writing newline fails
 		logging.Logf(logging.Warning, "XMLWriter failed to write final newline to '%s': %v", filePath, err)
 	}
+	return nil
+}
 
-	logging.Logf(logging.Info, "XMLWriter successfully wrote %d records to %s", len(records), filePath)
+// encodeXMLField encodes one field element. A map[string]interface{} value is written as nested child
+// elements in sorted key order; any other value is written as character data (nil as empty).
+func encodeXMLField(encoder *xml.Encoder, key string, value interface{}, recordIndex int) error {
+	fieldElement := xml.StartElement{Name: xml.Name{Local: key}}
+	// Encode field start tag
+	if err := encoder.EncodeToken(fieldElement); err != nil {
+		return fmt.Errorf("XMLWriter failed to encode field start element <%s> for record %d: %w", key, recordIndex, err)
+	}
+	if nested, ok := value.(map[string]interface{}); ok {
+		childKeys := make([]string, 0, len(nested))
+		for k := range nested {
+			childKeys = append(childKeys, k)
+		}
+		sort.Strings(childKeys)
+		for _, childKey := range childKeys {
+			if err := encodeXMLField(encoder, childKey, nested[childKey], recordIndex); err != nil {
+				return err
+			}
+		}
+	} else {
+		// Convert value to string; handle nil as empty string
+		stringValue := ""
+		if value != nil {
+			stringValue = fmt.Sprintf("%v", value)
+		}
+		// Encode field value (character data) - Encoder handles escaping
+		if err := encoder.EncodeToken(xml.CharData(stringValue)); err != nil {
+			return fmt.Errorf("XMLWriter failed to encode field value for <%s> for record %d: %w", key, recordIndex, err)
+		}
+	}
+	// Encode field end tag
+	if err := encoder.EncodeToken(fieldElement.End()); err != nil {
+		return fmt.Errorf("XMLWriter failed to encode field end element </%s> for record %d: %w", key, recordIndex, err)
+	}
 	return nil
 }
 
 // Close implements the OutputWriter interface. For XMLWriter, this is a no-op
-// as the file handle is managed within the Write method using defer file.Close().
+// as the file is closed within the Write method.
 func (xw *XMLWriter) Close() error {
 	logging.Logf(logging.Debug, "XMLWriter Close called (no-op).")
 	return nil
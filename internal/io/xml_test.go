@@ -333,6 +333,59 @@ func TestXMLReader_Read(t *testing.T) {
 	})
 }
 
+// TestXMLReader_AttributeFilter verifies that xmlAttributeFilter keeps only matching record elements.
+func TestXMLReader_AttributeFilter(t *testing.T) {
+	content := `<root>
+  <record type="A"><id>1</id><name>Alpha</name></record>
+  <record type="B"><id>2</id><name>Beta</name></record>
+  <record><id>3</id><name>Untyped</name></record>
+  <record kind="A" type="A"><id>4</id><name><first>Al</first></name></record>
+</root>`
+	testCases := []struct {
+		name   string
+		filter *config.XMLAttributeFilterConfig
+		want   []map[string]interface{}
+	}{
+		{"No filter keeps all", nil, []map[string]interface{}{{"id": "1", "name": "Alpha"}, {"id": "2", "name": "Beta"}, {"id": "3", "name": "Untyped"}, {"id": "4", "name": "Al"}}},
+		{"Keep type A", &config.XMLAttributeFilterConfig{Name: "type", Value: "A"}, []map[string]interface{}{{"id": "1", "name": "Alpha"}, {"id": "4", "name": "Al"}}},
+		{"Keep type B", &config.XMLAttributeFilterConfig{Name: "type", Value: "B"}, []map[string]interface{}{{"id": "2", "name": "Beta"}}},
+		{"No matches", &config.XMLAttributeFilterConfig{Name: "type", Value: "C"}, []map[string]interface{}{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := createTempXML(t, content)
+			reader, err := NewInputReader(config.SourceConfig{Type: "xml", File: filePath, XMLAttributeFilter: tc.filter}, "")
+			if err != nil {
+				t.Fatalf("NewInputReader() error = %v", err)
+			}
+			got, err := reader.Read(filePath)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			compareRecordsDeep(t, got, tc.want)
+		})
+	}
+}
+
+// TestXMLReader_AttributeFilterNestedChildren verifies that the nested content of a filtered-out record is skipped
+// entirely, so descendants named like the record tag are not read as top-level records.
+func TestXMLReader_AttributeFilterNestedChildren(t *testing.T) {
+	filePath := createTempXML(t, `<root>
+  <record type="B"><id>5</id><items><record type="A"><id>99</id></record></items><name>Skipped</name></record>
+  <record type="A"><id>6</id><name>Kept</name></record>
+</root>`)
+	reader, err := NewInputReader(config.SourceConfig{Type: "xml", File: filePath, XMLAttributeFilter: &config.XMLAttributeFilterConfig{Name: "type", Value: "A"}}, "")
+	if err != nil {
+		t.Fatalf("NewInputReader() error = %v", err)
+	}
+	got, err := reader.Read(filePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	compareRecordsDeep(t, got, []map[string]interface{}{{"id": "6", "name": "Kept"}})
+}
+
 // --- Test XMLWriter ---
 // (NewXMLWriter tests remain the same)
 func TestNewXMLWriter(t *testing.T) {
@@ -541,6 +594,78 @@ func TestXMLWriter_Write(t *testing.T) {
 }
 
 // (Close test remains the same)
+// TestXMLWriter_SetFieldOrder verifies configured fields are written first within each record.
+func TestXMLWriter_SetFieldOrder(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "ordered.xml")
+	writer := NewXMLWriter("", "")
+	var _ FieldOrderer = writer
+	writer.SetFieldOrder([]string{"name", "id"})
+	if err := writer.Write([]map[string]interface{}{{"id": 7, "name": "n", "code": "c"}}, filePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	got := string(content)
+	nameIdx, idIdx, codeIdx := strings.Index(got, "<name>"), strings.Index(got, "<id>"), strings.Index(got, "<code>")
+	if nameIdx < 0 || idIdx < 0 || codeIdx < 0 || !(nameIdx < idIdx && idIdx < codeIdx) {
+		t.Errorf("XML field order incorrect, want name, id, code:\n%s", got)
+	}
+}
+
+// TestXMLWriter_AttributeFields verifies attribute_fields render on the record element and other fields as children.
+func TestXMLWriter_AttributeFields(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "attrs.xml")
+	writer, err := NewOutputWriter(config.DestinationConfig{Type: "xml", File: filePath, AttributeFields: []string{"id", "kind"}}, "")
+	if err != nil {
+		t.Fatalf("NewOutputWriter() error = %v", err)
+	}
+	records := []map[string]interface{}{
+		{"id": 1, "kind": "A & B", "name": "Alice"},
+		{"id": 2, "name": "Bob"},
+	}
+	if err := writer.Write(records, filePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	got := string(content)
+	for _, want := range []string{`<record id="1" kind="A &amp; B">`, `<name>Alice</name>`, `<record id="2">`, `<name>Bob</name>`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("XML output missing %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"<id>", "<kind>"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("XML output contains attribute field as element %q:\n%s", unwanted, got)
+		}
+	}
+}
+
+// TestXMLWriter_NestedFields verifies nested map values (e.g., from nestFields) are written as child elements.
+func TestXMLWriter_NestedFields(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "nested.xml")
+	writer := NewXMLWriter("", "")
+	records := []map[string]interface{}{
+		{"id": 1, "address": map[string]interface{}{"zip": "0150", "city": "Oslo", "geo": map[string]interface{}{"lat": 59.9}}},
+	}
+	if err := writer.Write(records, filePath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	got := strings.Join(strings.Fields(string(content)), "")
+	want := "<address><city>Oslo</city><geo><lat>59.9</lat></geo><zip>0150</zip></address><id>1</id>"
+	if !strings.Contains(got, want) {
+		t.Errorf("XML output missing nested elements %q:\n%s", want, content)
+	}
+}
+
 func TestXMLWriter_Close(t *testing.T) {
 	writer := NewXMLWriter("record", "records")
 	err := writer.Close()
@@ -11,12 +11,14 @@ import (
 )
 
 // YAMLReader implements the InputReader interface for YAML files.
-type YAMLReader struct{}
+type YAMLReader struct {
+	open fileOpener // Opens the input file; nil means os.Open (see GzipReader)
+}
 
 // Read loads data from a YAML file specified by filePath.
 func (yr *YAMLReader) Read(filePath string) ([]map[string]interface{}, error) {
 	logging.Logf(logging.Debug, "YAMLReader reading file: %s", filePath)
-	data, err := os.ReadFile(filePath)
+	data, err := readInput(yr.open, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("YAMLReader failed to read file '%s': %w", filePath, err)
 	}
@@ -65,7 +67,9 @@ func (yr *YAMLReader) Read(filePath string) ([]map[string]interface{}, error) {
 }
 
 // YAMLWriter implements the OutputWriter interface for YAML files.
-type YAMLWriter struct{}
+type YAMLWriter struct {
+	create fileCreator // Creates the output file; nil means a plain file (see GzipWriter)
+}
 
 // Write saves the provided records as a YAML list (sequence of maps) to the specified filePath.
 func (yw *YAMLWriter) Write(records []map[string]interface{}, filePath string) error {
@@ -117,7 +121,7 @@ func (yw *YAMLWriter) Write(records []map[string]interface{}, filePath string) e
 
 	// Write the prepared data (either "null\n" or encoded YAML)
 	logging.Logf(logging.Debug, "YAMLWriter: Writing %d bytes to file %s", len(dataToWrite), filePath)
-	writeErr = os.WriteFile(filePath, dataToWrite, 0644)
+	writeErr = writeOutput(yw.create, filePath, dataToWrite)
 	if writeErr != nil {
 		return fmt.Errorf("YAMLWriter failed to write file '%s': %w", filePath, writeErr)
 	}
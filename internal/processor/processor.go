@@ -2,9 +2,12 @@ package processor
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"etl-tool/internal/config"
@@ -21,14 +24,44 @@ type Processor interface {
 	GetErrorCount() int64
 }
 
+// RunningTotalSetter is implemented by processors that can add a running-total column.
+type RunningTotalSetter interface {
+	SetRunningTotal(cfg *config.RunningTotalConfig)
+}
+
+// NestFieldsSetter is implemented by processors that can group dotted output fields into nested maps.
+type NestFieldsSetter interface {
+	SetNestFields(cfg *config.NestFieldsConfig)
+}
+
+// ConcurrencySetter is implemented by processors that can apply mappings to several records in parallel.
+type ConcurrencySetter interface {
+	SetConcurrency(workers int)
+}
+
+// RangeExpandSetter is implemented by processors that can expand range fields into multiple records.
+type RangeExpandSetter interface {
+	SetRangeExpand(cfg *config.RangeExpandConfig)
+}
+
 // processorImpl handles transformation, validation, and deduplication.
 type processorImpl struct {
 	mappings      []config.MappingRule
 	flatteningCfg *config.FlatteningConfig
 	dedupCfg      *config.DedupConfig
+	rangeExpand   *config.RangeExpandConfig
+	runningTotal  *config.RunningTotalConfig
+	nestFields    *config.NestFieldsConfig
 	errorHandling *config.ErrorHandlingConfig
 	errorWriter   etlio.ErrorWriter
 	errorCount    atomic.Int64
+	concurrency   int // Number of workers applying mappings; 1 or less processes records sequentially
+}
+
+// recordResult is the outcome of applying the mappings to one input record.
+type recordResult struct {
+	record map[string]interface{}
+	err    error
 }
 
 // NewProcessor creates a new Processor instance satisfying the Processor interface.
@@ -72,6 +105,28 @@ func NewProcessor(mappings []config.MappingRule, flatteningCfg *config.Flattenin
 	}
 }
 
+// SetRangeExpand configures the range-expansion stage (nil disables it).
+func (p *processorImpl) SetRangeExpand(cfg *config.RangeExpandConfig) {
+	p.rangeExpand = cfg
+}
+
+// SetConcurrency sets the number of workers applying mappings (values below 2 keep processing sequential).
+// Each record's mappings still run in order on a single worker, so rules may use earlier targets as their source;
+// only transforms that carry state from one record to the next (lagField, validateMonotonic) require one worker.
+func (p *processorImpl) SetConcurrency(workers int) {
+	p.concurrency = workers
+}
+
+// SetRunningTotal configures the running-total stage (nil disables it).
+func (p *processorImpl) SetRunningTotal(cfg *config.RunningTotalConfig) {
+	p.runningTotal = cfg
+}
+
+// SetNestFields configures the field-nesting stage (nil disables it).
+func (p *processorImpl) SetNestFields(cfg *config.NestFieldsConfig) {
+	p.nestFields = cfg
+}
+
 // GetErrorCount returns the number of records skipped due to processing errors.
 func (p *processorImpl) GetErrorCount() int64 {
 	return p.errorCount.Load()
@@ -84,13 +139,15 @@ func (p *processorImpl) ProcessRecords(inputRecords []map[string]interface{}) ([
 		return []map[string]interface{}{}, nil
 	}
 
+	transform.ResetLagState() // Stateful transforms (lagField, validateMonotonic) start fresh for each batch of records.
 	transformedRecords := make([]map[string]interface{}, 0, len(inputRecords))
 	p.errorCount.Store(0)
 
 	logging.Logf(logging.Debug, "Processor: Starting transformation/validation for %d records.", len(inputRecords))
+	results := p.transformRecords(inputRecords)
 	for i, originalRec := range inputRecords {
 		recordIndex := i
-		targetRecord, err := p.processSingleRecord(originalRec)
+		targetRecord, err := results[i].record, results[i].err
 		if err != nil {
 			p.errorCount.Add(1)
 			shouldLog := p.errorHandling.Mode == config.ErrorHandlingModeSkip && (p.errorHandling.LogErrors == nil || *p.errorHandling.LogErrors)
@@ -124,6 +181,31 @@ func (p *processorImpl) ProcessRecords(inputRecords []map[string]interface{}) ([
 		logging.Logf(logging.Debug, "Processor: Flattening phase completed. %d records remain.", len(flattenedRecords))
 	}
 
+	if p.rangeExpand != nil && len(flattenedRecords) > 0 {
+		logging.Logf(logging.Debug, "Processor: Starting range expansion (Source: '%s', Target: '%s').", p.rangeExpand.SourceField, p.rangeExpand.TargetField)
+		expandedOutput := make([]map[string]interface{}, 0, len(flattenedRecords))
+		for i, parentRecord := range flattenedRecords {
+			recordIndex := i
+			expandedRecs, err := p.expandRangeRecord(parentRecord)
+			if err != nil {
+				p.errorCount.Add(1)
+				shouldLog := p.errorHandling.Mode == config.ErrorHandlingModeSkip && (p.errorHandling.LogErrors == nil || *p.errorHandling.LogErrors)
+				if shouldLog { logging.Logf(logging.Warning, "Processor: Error record %d (range expansion): %v. Skipping record. Record (masked): %v", recordIndex, err, util.MaskSensitiveData(parentRecord)) } else if p.errorHandling.Mode == config.ErrorHandlingModeHalt { logging.Logf(logging.Error, "Processor: Error record %d (range expansion): %v. Halting.", recordIndex, err) }
+				if p.errorHandling.Mode == config.ErrorHandlingModeSkip && p.errorWriter != nil { if writeErr := p.errorWriter.Write(parentRecord, err); writeErr != nil { logging.Logf(logging.Error, "Processor: Failed to write record %d (range expansion) error to error file: %v", recordIndex, writeErr) } }
+				if p.errorHandling.Mode == config.ErrorHandlingModeHalt { return nil, fmt.Errorf("error processing record %d (range expansion, halting): %w", recordIndex, err) }
+				continue
+			}
+			expandedOutput = append(expandedOutput, expandedRecs...)
+		}
+		flattenedRecords = expandedOutput
+		logging.Logf(logging.Debug, "Processor: Range expansion phase completed. %d records remain.", len(flattenedRecords))
+	}
+
+	if p.runningTotal != nil && len(flattenedRecords) > 0 {
+		logging.Logf(logging.Debug, "Processor: Computing running total of '%s' into '%s' (PartitionBy: %v).", p.runningTotal.Field, p.runningTotal.Target, p.runningTotal.PartitionBy)
+		p.applyRunningTotal(flattenedRecords)
+	}
+
 	finalRecords := flattenedRecords
 	if p.dedupCfg != nil && len(p.dedupCfg.Keys) > 0 && len(flattenedRecords) > 0 {
 		originalCount := len(flattenedRecords)
@@ -135,11 +217,73 @@ func (p *processorImpl) ProcessRecords(inputRecords []map[string]interface{}) ([
 		logging.Logf(logging.Debug, "Processor: Skipping deduplication (no records after processing/flattening).")
 	}
 
+	if p.nestFields != nil && len(finalRecords) > 0 {
+		separator := p.nestFields.Separator; if separator == "" { separator = config.DefaultNestFieldsSeparator }
+		logging.Logf(logging.Debug, "Processor: Nesting dotted fields (Separator: '%s').", separator)
+		nestedOutput := make([]map[string]interface{}, 0, len(finalRecords))
+		for i, record := range finalRecords {
+			recordIndex := i
+			nested, err := nestRecordFields(record, separator)
+			if err != nil {
+				p.errorCount.Add(1)
+				shouldLog := p.errorHandling.Mode == config.ErrorHandlingModeSkip && (p.errorHandling.LogErrors == nil || *p.errorHandling.LogErrors)
+				if shouldLog { logging.Logf(logging.Warning, "Processor: Error record %d (nest fields): %v. Skipping record. Record (masked): %v", recordIndex, err, util.MaskSensitiveData(record)) } else if p.errorHandling.Mode == config.ErrorHandlingModeHalt { logging.Logf(logging.Error, "Processor: Error record %d (nest fields): %v. Halting.", recordIndex, err) }
+				if p.errorHandling.Mode == config.ErrorHandlingModeSkip && p.errorWriter != nil { if writeErr := p.errorWriter.Write(record, err); writeErr != nil { logging.Logf(logging.Error, "Processor: Failed to write record %d (nest fields) error to error file: %v", recordIndex, writeErr) } }
+				if p.errorHandling.Mode == config.ErrorHandlingModeHalt { return nil, fmt.Errorf("error processing record %d (nest fields, halting): %w", recordIndex, err) }
+				continue
+			}
+			nestedOutput = append(nestedOutput, nested)
+		}
+		finalRecords = nestedOutput
+	}
+
 	totalErrors := p.GetErrorCount()
 	if totalErrors > 0 { logging.Logf(logging.Warning, "Processor: Finished processing. Skipped %d records/parents due to errors.", totalErrors) } else { logging.Logf(logging.Debug, "Processor: Finished processing successfully with no errors.") }
 	return finalRecords, nil
 }
 
+// transformRecords applies the mappings to every record, fanning the records out to p.concurrency workers when it is
+// greater than 1. Results are indexed like inputRecords, so the caller handles them (including errors) in input order
+// exactly as in sequential mode. In halt mode, records after the first failed one may be left unprocessed.
+func (p *processorImpl) transformRecords(inputRecords []map[string]interface{}) []recordResult {
+	results := make([]recordResult, len(inputRecords))
+	workers := p.concurrency
+	if workers > len(inputRecords) { workers = len(inputRecords) }
+	if workers <= 1 {
+		for i, rec := range inputRecords {
+			results[i].record, results[i].err = p.processSingleRecord(rec)
+			if results[i].err != nil && p.errorHandling.Mode == config.ErrorHandlingModeHalt { break }
+		}
+		return results
+	}
+
+	logging.Logf(logging.Debug, "Processor: Applying mappings with %d workers.", workers)
+	halt := p.errorHandling.Mode == config.ErrorHandlingModeHalt
+	var firstFailed atomic.Int64 // Lowest index of a failed record in halt mode; later records need no work
+	firstFailed.Store(math.MaxInt64)
+	jobs := make(chan int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if halt && int64(i) > firstFailed.Load() { continue }
+				results[i].record, results[i].err = p.processSingleRecord(inputRecords[i])
+				if halt && results[i].err != nil {
+					for failed := firstFailed.Load(); int64(i) < failed; failed = firstFailed.Load() {
+						if firstFailed.CompareAndSwap(failed, int64(i)) { break }
+					}
+				}
+			}
+		}()
+	}
+	for i := range inputRecords { jobs <- i }
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
 // processSingleRecord applies mapping rules to one record.
 func (p *processorImpl) processSingleRecord(originalRecord map[string]interface{}) (map[string]interface{}, error) {
 	targetRecord := make(map[string]interface{})
@@ -155,6 +299,7 @@ func (p *processorImpl) processSingleRecord(originalRecord map[string]interface{
 			transformedValue = transform.ApplyTransform(rule.Transform, rule.Params, sourceValue, currentRecordState)
 			logging.Logf(logging.Debug, "Mapping #%d: Applied transform '%s', result: %v", i, rule.Transform, transformedValue)
 			if err, isError := transformedValue.(error); isError { return nil, fmt.Errorf("validation failed for rule #%d ('%s' -> '%s', transform: '%s'): %w", i, rule.Source, rule.Target, rule.Transform, err) }
+			if fields, isMerge := transformedValue.(transform.MergeFields); isMerge { for k, v := range fields { targetRecord[k] = v; currentRecordState[k] = v }; logging.Logf(logging.Debug, "Mapping #%d: Merged %d fields into record; target '%s' not set", i, len(fields), rule.Target); continue }
 		} else {
 			transformedValue = sourceValue
 			logging.Logf(logging.Debug, "Mapping #%d: No transform, assigned source value: %v", i, transformedValue)
@@ -229,6 +374,48 @@ func (p *processorImpl) flattenSingleRecord(parentRecord map[string]interface{})
 	return flattenedOutput, nil
 }
 
+// expandRangeRecord returns one copy of the record per integer in its inclusive range field, with the value in
+// the target field. Missing, nil, malformed, descending, or oversized ranges are errors.
+func (p *processorImpl) expandRangeRecord(parentRecord map[string]interface{}) ([]map[string]interface{}, error) {
+	cfg := p.rangeExpand
+	sourceVal, ok := getNestedField(parentRecord, cfg.SourceField)
+	if !ok || sourceVal == nil { return nil, fmt.Errorf("range source field '%s' not found or is nil", cfg.SourceField) }
+	start, end, err := parseRange(sourceVal, cfg.Separator)
+	if err != nil { return nil, fmt.Errorf("range source field '%s': %w", cfg.SourceField, err) }
+	maxValues := cfg.MaxValues; if maxValues <= 0 { maxValues = config.DefaultRangeMaxValues }
+	if count := int64(end) - int64(start) + 1; count > int64(maxValues) { return nil, fmt.Errorf("range source field '%s': range %d-%d has %d values, more than max_values (%d)", cfg.SourceField, start, end, count, maxValues) }
+
+	expanded := make([]map[string]interface{}, 0, end-start+1)
+	for v := start; v <= end; v++ {
+		newRec := deepcopy.Copy(parentRecord).(map[string]interface{})
+		newRec[cfg.TargetField] = v
+		expanded = append(expanded, newRec)
+	}
+	return expanded, nil
+}
+
+// parseRange parses an inclusive integer range such as "1-5" (using sep), a single integer string such as "7",
+// or an integer value. A leading minus sign on either bound is allowed when sep is "-" (e.g., "-3--1").
+func parseRange(value interface{}, sep string) (int, int, error) {
+	rawStr, isStr := value.(string)
+	if !isStr {
+		n, isNum := transform.ParseNumber(value)
+		if !isNum || n != math.Trunc(n) { return 0, 0, fmt.Errorf("value %v (type %T) is not an integer or range string", value, value) }
+		return int(n), int(n), nil
+	}
+	str := strings.TrimSpace(rawStr)
+	if str == "" { return 0, 0, fmt.Errorf("range is empty") }
+	// Search for the separator after the first character so a negative start bound is not mistaken for it.
+	startStr, endStr := str, str
+	if idx := strings.Index(str[1:], sep); idx >= 0 { startStr, endStr = str[:idx+1], str[idx+1+len(sep):] }
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil { return 0, 0, fmt.Errorf("invalid range start in '%s'", str) }
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil { return 0, 0, fmt.Errorf("invalid range end in '%s'", str) }
+	if start > end { return 0, 0, fmt.Errorf("range start %d is greater than end %d in '%s'", start, end, str) }
+	return start, end, nil
+}
+
 // getNestedField retrieves a value from a nested map structure using a dot-notation path.
 func getNestedField(data map[string]interface{}, path string) (interface{}, bool) {
 	keys := strings.Split(path, ".")
@@ -273,9 +460,62 @@ func removeNestedField(data map[string]interface{}, path string) {
 }
 
 
-// dedupRecords removes duplicates based on config.
+// nestRecordFields returns a copy of record with field names split on separator and grouped into nested maps
+// (e.g., "address.city" -> {"address": {"city": ...}}). A name that is both a value and a parent of another
+// field (e.g., "address" and "address.city") or that has an empty segment is an error.
+func nestRecordFields(record map[string]interface{}, separator string) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(record))
+	for k := range record { keys = append(keys, k) }
+	sort.Strings(keys)
+	nested := make(map[string]interface{}, len(record))
+	leafPaths := make(map[string]bool, len(record))
+	for _, key := range keys {
+		parts := strings.Split(key, separator)
+		current := nested
+		for i, part := range parts {
+			if part == "" && len(parts) > 1 { return nil, fmt.Errorf("field '%s' has an empty name segment", key) }
+			path := strings.Join(parts[:i+1], separator)
+			if i == len(parts)-1 {
+				if _, exists := current[part]; exists { return nil, fmt.Errorf("field '%s' conflicts with nested fields under the same name", key) }
+				current[part] = record[key]; leafPaths[path] = true
+				break
+			}
+			if leafPaths[path] { return nil, fmt.Errorf("field '%s' conflicts with field '%s'", key, path) }
+			child, exists := current[part].(map[string]interface{})
+			if !exists { child = make(map[string]interface{}); current[part] = child }
+			current = child
+		}
+	}
+	return nested, nil
+}
+
+// applyRunningTotal writes the cumulative sum of the configured field into the target field of each record,
+// in order, resetting the sum whenever the partition key differs from the previous record's.
+func (p *processorImpl) applyRunningTotal(records []map[string]interface{}) {
+	rt := p.runningTotal
+	total := 0.0
+	prevKey := ""
+	for i, rec := range records {
+		if len(rt.PartitionBy) > 0 {
+			keyParts := make([]string, 0, len(rt.PartitionBy))
+			for _, key := range rt.PartitionBy { val, _ := getNestedField(rec, key); keyParts = append(keyParts, transform.ValueToStringForHash(val)) }
+			key := strings.Join(keyParts, "||")
+			if i > 0 && key != prevKey { total = 0 }
+			prevKey = key
+		}
+		if val, ok := getNestedField(rec, rt.Field); ok && val != nil {
+			if num, isNum := transform.ParseNumber(val); isNum { total += num } else { logging.Logf(logging.Warning, "Running total: record %d field '%s' is not numeric (%v); adding 0.", i, rt.Field, val) }
+		}
+		rec[rt.Target] = total
+	}
+}
+
+// dedupRecords removes duplicates based on config. Kept records are returned in the order their keys first appear.
+// For the "count" strategy, each kept (first) record gets the number of records sharing its key in CountField.
 func (p *processorImpl) dedupRecords(records []map[string]interface{}) []map[string]interface{} {
 	seen := make(map[string]map[string]interface{})
+	var keyOrder []string
+	counts := make(map[string]int)
 	keys := p.dedupCfg.Keys
 	sort.Strings(keys)
 	lcStrategy := strings.ToLower(p.dedupCfg.Strategy)
@@ -291,17 +531,21 @@ func (p *processorImpl) dedupRecords(records []map[string]interface{}) []map[str
 		compositeKey := strings.Join(compositeKeyParts, "||")
 
 		storedRec, keyExists := seen[compositeKey]
+		if !keyExists { keyOrder = append(keyOrder, compositeKey) }
+		counts[compositeKey]++
 		keepCurrent := false
 		if !keyExists { keepCurrent = true } else {
 			switch lcStrategy {
-			case config.DedupStrategyFirst: break
+			case config.DedupStrategyFirst, config.DedupStrategyCount: break
 			case config.DedupStrategyLast: keepCurrent = true
 			case config.DedupStrategyMin, config.DedupStrategyMax:
 				currentVal, currentOk := getNestedField(currentRec, strategyField)
 				storedVal, storedOk := getNestedField(storedRec, strategyField)
 				if !currentOk { logging.Logf(logging.Warning, "Dedupe (%s): Field '%s' missing from current record for key '%s'. Keeping stored record.", lcStrategy, strategyField, compositeKey) } else if !storedOk { logging.Logf(logging.Warning, "Dedupe (%s): Field '%s' missing from stored record for key '%s'. Replacing with current record.", lcStrategy, strategyField, compositeKey); keepCurrent = true } else {
-					comparisonResult, err := transform.CompareValues(currentVal, storedVal)
-					if err != nil { logging.Logf(logging.Warning, "Dedupe (%s): Cannot compare strategy field '%s' for key '%s': %v. Keeping stored record.", lcStrategy, strategyField, compositeKey, err) } else { if (lcStrategy == config.DedupStrategyMin && comparisonResult < 0) || (lcStrategy == config.DedupStrategyMax && comparisonResult > 0) { keepCurrent = true } }
+					compare := transform.CompareValues
+					if strings.EqualFold(p.dedupCfg.StrategyFieldType, config.StrategyFieldTypeTimestamp) { compare = transform.CompareTimes }
+					comparisonResult, err := compare(currentVal, storedVal)
+					if err != nil { logging.Logf(logging.Warning, "Dedupe (%s): Cannot compare strategy field '%s' for key '%s': %v. Keeping stored record.", lcStrategy, strategyField, compositeKey, err) } else { if (lcStrategy == config.DedupStrategyMin && comparisonResult < 0) || (lcStrategy == config.DedupStrategyMax && comparisonResult > 0) { keepCurrent = true } else if comparisonResult == 0 && p.dedupCfg.TieBreaker != "" { keepCurrent = p.tieBreakerPrefersCurrent(currentRec, storedRec, compositeKey) } }
 				}
 			default: logging.Logf(logging.Error, "Dedupe: Internal error - unknown strategy '%s'. Key '%s'. Keeping first.", p.dedupCfg.Strategy, compositeKey); if !keyExists { keepCurrent = true }
 			}
@@ -309,6 +553,24 @@ func (p *processorImpl) dedupRecords(records []map[string]interface{}) []map[str
 		if keepCurrent { seen[compositeKey] = currentRec }
 	}
 	uniqueRecords := make([]map[string]interface{}, 0, len(seen))
-	for _, record := range seen { uniqueRecords = append(uniqueRecords, record) }
+	for _, compositeKey := range keyOrder {
+		record := seen[compositeKey]
+		if lcStrategy == config.DedupStrategyCount { record[p.dedupCfg.CountField] = counts[compositeKey] }
+		uniqueRecords = append(uniqueRecords, record)
+	}
 	return uniqueRecords
 }
+
+// tieBreakerPrefersCurrent resolves a min/max strategy tie using the configured TieBreaker field.
+// It returns true if the current record should replace the stored one. Missing or incomparable
+// tie-breaker values, and equal ones, keep the stored record.
+func (p *processorImpl) tieBreakerPrefersCurrent(currentRec, storedRec map[string]interface{}, compositeKey string) bool {
+	field := p.dedupCfg.TieBreaker
+	currentVal, currentOk := getNestedField(currentRec, field)
+	storedVal, storedOk := getNestedField(storedRec, field)
+	if !currentOk || !storedOk { logging.Logf(logging.Warning, "Dedupe: Tie-breaker field '%s' missing for key '%s'. Keeping stored record.", field, compositeKey); return false }
+	comparisonResult, err := transform.CompareValues(currentVal, storedVal)
+	if err != nil { logging.Logf(logging.Warning, "Dedupe: Cannot compare tie-breaker field '%s' for key '%s': %v. Keeping stored record.", field, compositeKey, err); return false }
+	if strings.ToLower(p.dedupCfg.TieBreakerOrder) == config.TieBreakerOrderDesc { return comparisonResult > 0 }
+	return comparisonResult < 0
+}
@@ -73,7 +73,16 @@ func TestProcessRecords(t *testing.T) {
 		{ name: "Validation fail (Skip Mode - Log)", mappings: validationMappings, errorHandling: errorHandlingSkipLog, useErrorWriter: false, inputRecords: []map[string]interface{}{ {"email": "test@example.com", "status": "active", "age": 30}, {"email": "good@email.org", "status": "", "age": 40}, {"email": "ok@domain.net", "status": "active", "age": 150}, {"email": "final@test.io", "status": "active", "age": 50}, }, wantRecords: []map[string]interface{}{ {"email": "test@example.com", "status": "active", "age": 30}, {"email": "final@test.io", "status": "active", "age": 50}, }, wantErr: false, wantErrorCount: 2, wantWriteCalls: 0, },
 		{ name: "Validation fail (Skip Mode - With Error Writer)", mappings: validationMappings, errorHandling: errorHandlingSkipLog, useErrorWriter: true, writerSetup: nil, inputRecords: []map[string]interface{}{ {"email": "test@example.com", "status": "active", "age": 30}, {"email": "invalid", "status": "active", "age": 40}, {"email": "ok@domain.net", "status": "ok", "age": -5}, }, wantRecords: []map[string]interface{}{ {"email": "test@example.com", "status": "active", "age": 30}, }, wantErr: false, wantErrorCount: 2, wantWriteCalls: 2, checkWrites: func(t *testing.T, mw *mockErrorWriter) { if len(mw.writeCalls) != 2 { t.Fatalf("W#!=2") }; if !reflect.DeepEqual(mw.writeCalls[0].Record["email"], "invalid") {t.Error("W0 rec")}; if !strings.Contains(mw.writeCalls[0].Err.Error(),"validateRegex") {t.Error("W0 err")}; if !reflect.DeepEqual(mw.writeCalls[1].Record["email"], "ok@domain.net") {t.Error("W1 rec")}; if !strings.Contains(mw.writeCalls[1].Err.Error(),"validateNumericRange") {t.Error("W1 err")} }, },
 		{ name: "Validation fail (Skip Mode - Error Writer Fails)", mappings: validationMappings, errorHandling: errorHandlingSkipLog, useErrorWriter: true, writerSetup: func(m *mockErrorWriter) { m.writeShouldFail = true }, inputRecords: []map[string]interface{}{ {"email": "test@example.com", "status": "active", "age": 30}, {"email": "invalid", "status": "active", "age": 40}, }, wantRecords: []map[string]interface{}{ {"email": "test@example.com", "status": "active", "age": 30}, }, wantErr: false, wantErrorCount: 1, wantWriteCalls: 1, checkWrites: func(t *testing.T, mw *mockErrorWriter) { if len(mw.writeCalls) != 1 || !reflect.DeepEqual(mw.writeCalls[0].Record["email"], "invalid") { t.Errorf("Expected write fail for 'invalid'") } }, },
+		{ name: "Stateful lagField delta over ordered records", mappings: []config.MappingRule{{Source: "id",Target:"id"},{Source:"amount",Target:"delta",Transform:"lagField",Params:map[string]interface{}{"field":"amount","delta":true}}}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"id":1, "amount":100},{"id":2, "amount":150},{"id":3, "amount":120}, }, wantRecords: []map[string]interface{}{ {"id":1, "delta":nil},{"id":2, "delta":50.0},{"id":3, "delta":-30.0}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Stateful validateMonotonic skip keeps last passing value as baseline", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"v",Target:"v",Transform:"validateMonotonic",Params:map[string]interface{}{"field":"v","keyField":"k"}}}, errorHandling: errorHandlingSkipLog, inputRecords: []map[string]interface{}{ {"k":"A", "v":10},{"k":"A", "v":5},{"k":"A", "v":8},{"k":"A", "v":12}, }, wantRecords: []map[string]interface{}{ {"k":"A", "v":10},{"k":"A", "v":12}, }, wantErr: false, wantErrorCount: 2, wantWriteCalls: 0, },
 		{ name: "Deduplication (First)", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"v",Target:"v"}}, dedupCfg: dedupConfigFirst, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "v":1},{"k":"B", "v":2},{"k":"A", "v":3},{"k":"C", "v":4},{"k":"B", "v":5}, }, wantRecords: []map[string]interface{}{ {"k":"A", "v":1},{"k":"B", "v":2},{"k":"C", "v":4}, }, wantErr: false, wantErrorCount: 0, wantWriteCalls: 0, },
+		{ name: "Deduplication (Min) tie keeps first without tie-breaker", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"v",Target:"v"},{Source:"seq",Target:"seq"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyMin, StrategyField: "v"}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "v":1, "seq":9},{"k":"A", "v":1, "seq":3},{"k":"A", "v":2, "seq":1}, }, wantRecords: []map[string]interface{}{ {"k":"A", "v":1, "seq":9}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Deduplication (Min) tie resolved by tie-breaker asc", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"v",Target:"v"},{Source:"seq",Target:"seq"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyMin, StrategyField: "v", TieBreaker: "seq", TieBreakerOrder: config.TieBreakerOrderAsc}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "v":1, "seq":9},{"k":"A", "v":1, "seq":3},{"k":"A", "v":2, "seq":1},{"k":"B", "v":5, "seq":2},{"k":"B", "v":5, "seq":7}, }, wantRecords: []map[string]interface{}{ {"k":"A", "v":1, "seq":3},{"k":"B", "v":5, "seq":2}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Deduplication (Max) tie resolved by tie-breaker desc", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"v",Target:"v"},{Source:"updated",Target:"updated"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyMax, StrategyField: "v", TieBreaker: "updated", TieBreakerOrder: config.TieBreakerOrderDesc}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "v":10, "updated":"2024-01-01"},{"k":"A", "v":10, "updated":"2024-03-01"},{"k":"A", "v":10, "updated":"2024-02-01"}, }, wantRecords: []map[string]interface{}{ {"k":"A", "v":10, "updated":"2024-03-01"}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Deduplication (Max) latest by ISO timestamp", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"updated",Target:"updated"},{Source:"v",Target:"v"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyMax, StrategyField: "updated", StrategyFieldType: config.StrategyFieldTypeTimestamp}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "updated":"2024-03-01T09:00:00Z", "v":1},{"k":"A", "updated":"2024-03-01T10:00:00+05:00", "v":2},{"k":"A", "updated":"2024-03-01T09:30:00.5Z", "v":3},{"k":"B", "updated":"2024-01-02T00:00:00Z", "v":4},{"k":"B", "updated":"2024-01-10T00:00:00Z", "v":5}, }, wantRecords: []map[string]interface{}{ {"k":"A", "updated":"2024-03-01T09:30:00.5Z", "v":3},{"k":"B", "updated":"2024-01-10T00:00:00Z", "v":5}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Deduplication (Max) ISO timestamp compared lexicographically without type", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"updated",Target:"updated"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyMax, StrategyField: "updated"}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "updated":"2024-03-01T09:00:00Z"},{"k":"A", "updated":"2024-03-01T10:00:00+05:00"}, }, wantRecords: []map[string]interface{}{ {"k":"A", "updated":"2024-03-01T10:00:00+05:00"}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Deduplication (Min) earliest by timestamp keeps stored on unparsable", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"ts",Target:"ts"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyMin, StrategyField: "ts", StrategyFieldType: "Timestamp"}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "ts":"2024-05-02"},{"k":"A", "ts":"not a date"},{"k":"A", "ts":"2024-05-01"}, }, wantRecords: []map[string]interface{}{ {"k":"A", "ts":"2024-05-01"}, }, wantErr: false, wantErrorCount: 0, },
+		{ name: "Deduplication (Count) keeps first with counts", mappings: []config.MappingRule{{Source: "k",Target:"k"},{Source:"seq",Target:"seq"}}, dedupCfg: &config.DedupConfig{Keys: []string{"k"}, Strategy: config.DedupStrategyCount, CountField: "dupes"}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"k":"A", "seq":1},{"k":"B", "seq":2},{"k":"A", "seq":3},{"k":"A", "seq":4},{"seq":5}, }, wantRecords: []map[string]interface{}{ {"k":"A", "seq":1, "dupes":3},{"k":"B", "seq":2, "dupes":1},{"k":nil, "seq":5, "dupes":1}, }, wantErr: false, wantErrorCount: 0, },
 		{ name: "Empty input records", mappings: basicMappings, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{}, wantRecords: []map[string]interface{}{}, wantErr: false, wantErrorCount: 0, wantWriteCalls: 0, },
 		{ name: "No mappings defined", mappings: []config.MappingRule{}, errorHandling: errorHandlingHalt, inputRecords: []map[string]interface{}{ {"id": 1}}, wantRecords: []map[string]interface{}{ {} }, wantErr: false, wantErrorCount: 0, wantWriteCalls: 0, },
 
@@ -137,4 +146,334 @@ func TestProcessRecords(t *testing.T) {
 			} else { if gotWriteCalls > 0 { t.Errorf("Writer calls = %d, want 0", gotWriteCalls) } }
 		})
 	}
-}
\ No newline at end of file
+}
+
+// TestProcessRecords_DedupCount verifies the "count" dedup strategy keeps the first record per composite key, in
+// first-seen key order, with the number of collapsed records in CountField.
+func TestProcessRecords_DedupCount(t *testing.T) {
+	mappings := []config.MappingRule{{Source: "region", Target: "region"}, {Source: "sku", Target: "sku"}, {Source: "qty", Target: "qty"}}
+	p := NewProcessor(mappings, nil, &config.DedupConfig{Keys: []string{"region", "sku"}, Strategy: "COUNT", CountField: "orders"}, &config.ErrorHandlingConfig{Mode: config.ErrorHandlingModeHalt}, nil)
+	input := []map[string]interface{}{{"region": "east", "sku": "A", "qty": 1}, {"region": "west", "sku": "A", "qty": 2}, {"region": "east", "sku": "A", "qty": 3}, {"region": "east", "sku": "B", "qty": 4}, {"region": "west", "sku": "A", "qty": 5}, {"region": "east", "sku": "A", "qty": 6}}
+	got, err := p.ProcessRecords(input)
+	if err != nil { t.Fatalf("ProcessRecords err: %v", err) }
+	want := []map[string]interface{}{{"region": "east", "sku": "A", "qty": 1, "orders": 3}, {"region": "west", "sku": "A", "qty": 2, "orders": 2}, {"region": "east", "sku": "B", "qty": 4, "orders": 1}}
+	if !reflect.DeepEqual(got, want) { t.Errorf("Records mismatch:\ngot:  %v\nwant: %v", got, want) }
+}
+
+// TestProcessRecords_RunningTotal verifies cumulative sums in input order, with and without partition resets.
+func TestProcessRecords_RunningTotal(t *testing.T) {
+	mappings := []config.MappingRule{{Source: "day", Target: "day"}, {Source: "acct", Target: "acct"}, {Source: "amount", Target: "amount"}}
+	input := []map[string]interface{}{
+		{"day": 1, "acct": "A", "amount": 10},
+		{"day": 2, "acct": "A", "amount": "5.5"},
+		{"day": 3, "acct": "A", "amount": nil},
+		{"day": 1, "acct": "B", "amount": 7},
+		{"day": 2, "acct": "B", "amount": "n/a"},
+		{"day": 3, "acct": "B", "amount": 3},
+		{"day": 4, "acct": "A", "amount": 1},
+	}
+	testCases := []struct {
+		name string
+		cfg  *config.RunningTotalConfig
+		want []interface{}
+	}{
+		{name: "No partition", cfg: &config.RunningTotalConfig{Field: "amount", Target: "cumulative"}, want: []interface{}{10.0, 15.5, 15.5, 22.5, 22.5, 25.5, 26.5}},
+		{name: "Reset on key change", cfg: &config.RunningTotalConfig{Field: "amount", Target: "cumulative", PartitionBy: []string{"acct"}}, want: []interface{}{10.0, 15.5, 15.5, 7.0, 7.0, 10.0, 1.0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewProcessor(mappings, nil, nil, nil, nil)
+			rts, ok := p.(RunningTotalSetter)
+			if !ok {
+				t.Fatalf("processor %T does not implement RunningTotalSetter", p)
+			}
+			rts.SetRunningTotal(tc.cfg)
+			got, err := p.ProcessRecords(input)
+			if err != nil {
+				t.Fatalf("ProcessRecords() error = %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d records, want %d", len(got), len(tc.want))
+			}
+			for i, rec := range got {
+				if rec["day"] != input[i]["day"] || rec["acct"] != input[i]["acct"] {
+					t.Errorf("record %d out of order: %v", i, rec)
+				}
+				if !reflect.DeepEqual(rec["cumulative"], tc.want[i]) {
+					t.Errorf("record %d cumulative = %v, want %v", i, rec["cumulative"], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestProcessRecords_NestFields tests that dotted targets are grouped into nested maps after mapping.
+func TestProcessRecords_NestFields(t *testing.T) {
+	t.Run("Nested output", func(t *testing.T) {
+		mappings := []config.MappingRule{
+			{Source: "id", Target: "id"},
+			{Source: "city", Target: "address.city"},
+			{Source: "zip", Target: "address.zip"},
+			{Source: "lat", Target: "address/geo/lat"},
+		}
+		p := NewProcessor(mappings, nil, nil, nil, nil)
+		nfs, ok := p.(NestFieldsSetter)
+		if !ok {
+			t.Fatalf("processor %T does not implement NestFieldsSetter", p)
+		}
+		nfs.SetNestFields(&config.NestFieldsConfig{})
+		got, err := p.ProcessRecords([]map[string]interface{}{{"id": 1, "city": "Oslo", "zip": "0150", "lat": 59.9}})
+		if err != nil {
+			t.Fatalf("ProcessRecords() error = %v", err)
+		}
+		want := []map[string]interface{}{{"id": 1, "address": map[string]interface{}{"city": "Oslo", "zip": "0150"}, "address/geo/lat": 59.9}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ProcessRecords() mismatch:\ngot:  %v\nwant: %v", got, want)
+		}
+
+		nfs.SetNestFields(&config.NestFieldsConfig{Separator: "/"})
+		got, err = p.ProcessRecords([]map[string]interface{}{{"id": 2, "lat": 1.5}})
+		if err != nil {
+			t.Fatalf("ProcessRecords() error = %v", err)
+		}
+		want = []map[string]interface{}{{"id": 2, "address.city": nil, "address.zip": nil, "address": map[string]interface{}{"geo": map[string]interface{}{"lat": 1.5}}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ProcessRecords() with separator mismatch:\ngot:  %v\nwant: %v", got, want)
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		mappings := []config.MappingRule{{Source: "addr", Target: "address"}, {Source: "city", Target: "address.city"}}
+		input := []map[string]interface{}{{"addr": "1 Main St", "city": "Oslo"}}
+		for _, mode := range []string{config.ErrorHandlingModeHalt, config.ErrorHandlingModeSkip} {
+			p := NewProcessor(mappings, nil, nil, &config.ErrorHandlingConfig{Mode: mode}, nil)
+			p.(NestFieldsSetter).SetNestFields(&config.NestFieldsConfig{})
+			got, err := p.ProcessRecords(input)
+			if mode == config.ErrorHandlingModeHalt {
+				if err == nil || !strings.Contains(err.Error(), "field 'address.city' conflicts with field 'address'") {
+					t.Errorf("halt: ProcessRecords() error = %v, want conflict error", err)
+				}
+				continue
+			}
+			if err != nil || len(got) != 0 || p.GetErrorCount() != 1 {
+				t.Errorf("skip: ProcessRecords() = %v, %v (errors %d), want no records and 1 error", got, err, p.GetErrorCount())
+			}
+		}
+	})
+}
+
+// TestProcessRecords_MergeFields tests that flattenMap results are merged into the output record.
+func TestProcessRecords_MergeFields(t *testing.T) {
+	mappings := []config.MappingRule{
+		{Source: "id", Target: "id"},
+		{Source: "address", Target: "address", Transform: "flattenMap", Params: map[string]interface{}{"prefix": "address"}},
+		{Source: "address.city", Target: "city_upper", Transform: "toUpperCase"},
+	}
+	input := []map[string]interface{}{
+		{"id": 1, "address": map[string]interface{}{"city": "Oslo", "zip": "0150"}},
+		{"id": 2, "address": nil},
+	}
+	want := []map[string]interface{}{
+		{"id": 1, "address.city": "Oslo", "address.zip": "0150", "city_upper": "OSLO"},
+		{"id": 2, "address": nil, "city_upper": nil},
+	}
+	got, err := NewProcessor(mappings, nil, nil, nil, nil).ProcessRecords(input)
+	if err != nil {
+		t.Fatalf("ProcessRecords() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProcessRecords() = %v, want %v", got, want)
+	}
+}
+
+// TestProcessRecords_RangeExpand tests expanding range fields into one record per value.
+func TestProcessRecords_RangeExpand(t *testing.T) {
+	mappings := []config.MappingRule{{Source: "id", Target: "id"}, {Source: "seats", Target: "seats"}}
+	rangeCfg := &config.RangeExpandConfig{SourceField: "seats", TargetField: "seat", Separator: "-", MaxValues: 100}
+	trueVal := true
+	testCases := []struct {
+		name          string
+		errorHandling *config.ErrorHandlingConfig
+		input         []map[string]interface{}
+		want          []map[string]interface{}
+		wantErr       string
+		wantErrWrites int
+	}{
+		{
+			name:  "Multi-value and single ranges",
+			input: []map[string]interface{}{{"id": "a", "seats": "1-3"}, {"id": "b", "seats": "5-5"}, {"id": "c", "seats": " 7 "}, {"id": "d", "seats": 9}},
+			want: []map[string]interface{}{
+				{"id": "a", "seats": "1-3", "seat": 1}, {"id": "a", "seats": "1-3", "seat": 2}, {"id": "a", "seats": "1-3", "seat": 3},
+				{"id": "b", "seats": "5-5", "seat": 5}, {"id": "c", "seats": " 7 ", "seat": 7}, {"id": "d", "seats": 9, "seat": 9},
+			},
+		},
+		{
+			name:          "Invalid range skipped",
+			errorHandling: &config.ErrorHandlingConfig{Mode: config.ErrorHandlingModeSkip, LogErrors: &trueVal},
+			input:         []map[string]interface{}{{"id": "a", "seats": "3-1"}, {"id": "b", "seats": "x-2"}, {"id": "c", "seats": "1-500"}, {"id": "d", "seats": "2-3"}},
+			want:          []map[string]interface{}{{"id": "d", "seats": "2-3", "seat": 2}, {"id": "d", "seats": "2-3", "seat": 3}},
+			wantErrWrites: 3,
+		},
+		{
+			name:          "Invalid range halts",
+			errorHandling: &config.ErrorHandlingConfig{Mode: config.ErrorHandlingModeHalt},
+			input:         []map[string]interface{}{{"id": "a", "seats": "1-2"}, {"id": "b", "seats": "abc"}},
+			wantErr:       "error processing record 1 (range expansion, halting): range source field 'seats': invalid range start in 'abc'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writer := &mockErrorWriter{}
+			p := NewProcessor(mappings, nil, nil, tc.errorHandling, writer)
+			res, ok := p.(RangeExpandSetter)
+			if !ok {
+				t.Fatalf("processor %T does not implement RangeExpandSetter", p)
+			}
+			res.SetRangeExpand(rangeCfg)
+			got, err := p.ProcessRecords(tc.input)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("ProcessRecords() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessRecords() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				printRecordsDiff(t, got, tc.want)
+				t.Errorf("ProcessRecords() records mismatch")
+			}
+			if len(writer.writeCalls) != tc.wantErrWrites {
+				t.Errorf("error writes = %d, want %d", len(writer.writeCalls), tc.wantErrWrites)
+			}
+			if p.GetErrorCount() != int64(tc.wantErrWrites) {
+				t.Errorf("GetErrorCount() = %d, want %d", p.GetErrorCount(), tc.wantErrWrites)
+			}
+		})
+	}
+}
+
+// TestParseRange tests range string parsing.
+func TestParseRange(t *testing.T) {
+	testCases := []struct {
+		value              interface{}
+		sep                string
+		wantStart, wantEnd int
+		wantErr            bool
+	}{
+		{value: "1-3", sep: "-", wantStart: 1, wantEnd: 3},
+		{value: " 2 - 4 ", sep: "-", wantStart: 2, wantEnd: 4},
+		{value: "-3--1", sep: "-", wantStart: -3, wantEnd: -1},
+		{value: "10..12", sep: "..", wantStart: 10, wantEnd: 12},
+		{value: "5", sep: "-", wantStart: 5, wantEnd: 5},
+		{value: 4.0, sep: "-", wantStart: 4, wantEnd: 4},
+		{value: 4.5, sep: "-", wantErr: true},
+		{value: "", sep: "-", wantErr: true},
+		{value: "1-", sep: "-", wantErr: true},
+		{value: "a-b", sep: "-", wantErr: true},
+		{value: "4-2", sep: "-", wantErr: true},
+		{value: true, sep: "-", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.value), func(t *testing.T) {
+			start, end, err := parseRange(tc.value, tc.sep)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseRange(%v) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+			if !tc.wantErr && (start != tc.wantStart || end != tc.wantEnd) {
+				t.Errorf("parseRange(%v) = %d, %d, want %d, %d", tc.value, start, end, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
+
+// concurrencyTestInput builds n records where every seventh record fails the amount range check.
+func concurrencyTestInput(n int) []map[string]interface{} {
+	records := make([]map[string]interface{}, n)
+	for i := range records {
+		amount := fmt.Sprintf("%d", i*3)
+		if i%7 == 3 {
+			amount = "-1"
+		}
+		records[i] = map[string]interface{}{"id": i, "name": fmt.Sprintf("  customer %d  ", i), "amount": amount, "code": fmt.Sprintf("c-%04d", i)}
+	}
+	return records
+}
+
+// concurrencyTestMappings chains rules through earlier targets, which stays correct in parallel mode because each
+// record's rules run in order on one worker.
+var concurrencyTestMappings = []config.MappingRule{
+	{Source: "id", Target: "id"},
+	{Source: "name", Target: "name", Transform: "trim"},
+	{Source: "name", Target: "name_upper", Transform: "toUpperCase"},
+	{Source: "amount", Target: "amount", Transform: "toInt"},
+	{Source: "amount", Target: "amount_checked", Transform: "validateNumericRange", Params: map[string]interface{}{"min": 0}},
+	{Source: "code", Target: "code_digits", Transform: "regexExtract", Params: map[string]interface{}{"pattern": `c-(\d+)`}},
+	{Source: "name_upper", Target: "label", Transform: "applyIf", Params: map[string]interface{}{"condition": "amount > 100", "then": "replaceAll", "thenParams": map[string]interface{}{"old": "CUSTOMER", "new": "VIP"}}},
+}
+
+// TestProcessRecords_Concurrency checks that parallel mapping yields the same records, order, and error handling
+// as sequential processing.
+func TestProcessRecords_Concurrency(t *testing.T) {
+	input := concurrencyTestInput(500)
+	run := func(workers int, mode string) ([]map[string]interface{}, error, *mockErrorWriter, int64) {
+		writer := &mockErrorWriter{}
+		proc := NewProcessor(concurrencyTestMappings, nil, nil, &config.ErrorHandlingConfig{Mode: mode}, writer)
+		proc.(ConcurrencySetter).SetConcurrency(workers)
+		got, err := proc.ProcessRecords(input)
+		return got, err, writer, proc.GetErrorCount()
+	}
+
+	wantRecords, _, wantWriter, wantErrors := run(1, config.ErrorHandlingModeSkip)
+	if len(wantRecords) != 429 || wantErrors != 71 {
+		t.Fatalf("sequential run kept %d records with %d errors, want 429 and 71", len(wantRecords), wantErrors)
+	}
+	for _, workers := range []int{2, 8, 1000} {
+		t.Run(fmt.Sprintf("skip with %d workers", workers), func(t *testing.T) {
+			got, err, writer, errCount := run(workers, config.ErrorHandlingModeSkip)
+			if err != nil {
+				t.Fatalf("ProcessRecords() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, wantRecords) {
+				t.Errorf("parallel output differs from sequential output (got %d records, want %d)", len(got), len(wantRecords))
+			}
+			if errCount != wantErrors || len(writer.writeCalls) != len(wantWriter.writeCalls) {
+				t.Fatalf("error count = %d with %d error writes, want %d and %d", errCount, len(writer.writeCalls), wantErrors, len(wantWriter.writeCalls))
+			}
+			for i := range writer.writeCalls {
+				if !reflect.DeepEqual(writer.writeCalls[i].Record, wantWriter.writeCalls[i].Record) {
+					t.Errorf("error write %d = %v, want %v (input order)", i, writer.writeCalls[i].Record, wantWriter.writeCalls[i].Record)
+				}
+			}
+		})
+		t.Run(fmt.Sprintf("halt with %d workers", workers), func(t *testing.T) {
+			_, err, _, errCount := run(workers, config.ErrorHandlingModeHalt)
+			if err == nil || !strings.Contains(err.Error(), "error processing record 3 (mapping, halting)") {
+				t.Errorf("ProcessRecords() error = %v, want the first failing record (3)", err)
+			}
+			if errCount != 1 {
+				t.Errorf("error count = %d, want 1", errCount)
+			}
+		})
+	}
+}
+
+// BenchmarkProcessRecords compares sequential and parallel mapping of the same records.
+func BenchmarkProcessRecords(b *testing.B) {
+	input := concurrencyTestInput(5000)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			proc := NewProcessor(concurrencyTestMappings, nil, nil, &config.ErrorHandlingConfig{Mode: config.ErrorHandlingModeSkip, LogErrors: new(bool)}, nil)
+			proc.(ConcurrencySetter).SetConcurrency(workers)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := proc.ProcessRecords(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
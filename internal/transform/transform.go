@@ -2,23 +2,43 @@
 package transform
 
 import (
+	"bytes"
+	"context"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/csv"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"math"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"text/template"
+	"text/template/parse"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"etl-tool/internal/logging"
+	"etl-tool/internal/util"
 
 	"github.com/Knetic/govaluate"
+	"gopkg.in/yaml.v3"
 )
 
 // fipsModeEnabled tracks whether FIPS compliance is active.
@@ -37,11 +57,36 @@ func IsFIPSMode() bool {
 	return fipsModeEnabled.Load()
 }
 
+// execEnabled tracks whether the exec transform may run external commands.
+var execEnabled atomic.Bool
+
+// SetExecEnabled allows or forbids the exec transform to run external commands (the -allow-exec flag).
+func SetExecEnabled(enabled bool) {
+	execEnabled.Store(enabled)
+	if enabled {
+		logging.Logf(logging.Debug, "External command execution enabled for transformations.")
+	}
+}
+
+// IsExecEnabled returns true if the exec transform may run external commands.
+func IsExecEnabled() bool {
+	return execEnabled.Load()
+}
+
+// defaultExecTimeout bounds how long a single exec transform command may run.
+const defaultExecTimeout = 30 * time.Second
+
 // TransformFunc defines the signature for transformation/validation functions.
 // It receives the input value, the full current record state, and any parameters.
 // It returns the transformed value or an error for validation/strict failures.
 type TransformFunc func(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{}
 
+// currencyCodeRegex matches a three-letter ISO 4217 code at the start or end of a currency string.
+var currencyCodeRegex = regexp.MustCompile(`^([A-Z]{3})\s*|\s*([A-Z]{3})$`)
+
+// currencySymbolCodes maps common currency symbols to the ISO code reported by parseCurrency.
+var currencySymbolCodes = map[string]string{"$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY"}
+
 // transformRegistry holds the mapping from function names (lowercase) to implementations.
 var transformRegistry = make(map[string]TransformFunc)
 
@@ -61,10 +106,75 @@ func init() {
 	transformRegistry["tofloat"] = toFloat
 	transformRegistry["tobool"] = toBool
 	transformRegistry["tostring"] = toString
+	transformRegistry["tojsonstring"] = toJSONString
 	transformRegistry["replaceall"] = replaceAll
 	transformRegistry["substring"] = substring
+	transformRegistry["padleft"] = padLeft
+	transformRegistry["padright"] = padRight
+	transformRegistry["ensureprefix"] = ensurePrefix
+	transformRegistry["ensuresuffix"] = ensureSuffix
+	transformRegistry["stripprefix"] = stripPrefix
+	transformRegistry["stripsuffix"] = stripSuffix
 	transformRegistry["coalesce"] = coalesceTransform
 	transformRegistry["hash"] = hashTransform
+	transformRegistry["toboolcustom"] = toBoolCustom
+	transformRegistry["parsepercent"] = parsePercent
+	transformRegistry["normalizepercent"] = normalizePercent
+	transformRegistry["parsecurrency"] = parseCurrency
+	transformRegistry["countrycode"] = countryCode
+	transformRegistry["regexredact"] = regexRedact
+	transformRegistry["collapserepeats"] = collapseRepeats
+	transformRegistry["fuzzymatch"] = fuzzyMatch
+	transformRegistry["editdistance"] = editDistance
+	transformRegistry["deduplist"] = dedupList
+	transformRegistry["sortlist"] = sortList
+	transformRegistry["listcount"] = listCount
+	transformRegistry["nthfield"] = nthField
+	transformRegistry["excelserialtodate"] = excelSerialToDate
+	transformRegistry["casttype"] = castType
+	transformRegistry["sumdelimited"] = sumDelimited
+	transformRegistry["configlookup"] = configLookup
+	transformRegistry["severitymap"] = severityMap
+	transformRegistry["shard"] = shard
+	transformRegistry["truncatetime"] = truncateTime
+	transformRegistry["exec"] = execTransform
+	transformRegistry["parsequerystring"] = parseQueryString
+	transformRegistry["urlparse"] = urlParse
+	transformRegistry["applymask"] = applyMask
+	transformRegistry["lagfield"] = lagField
+	transformRegistry["normalizetimestamp"] = normalizeTimestamp
+	transformRegistry["completeness"] = completeness
+	transformRegistry["pseudonymize"] = pseudonymize
+	transformRegistry["detecttype"] = detectType
+	transformRegistry["digitsonly"] = digitsOnly
+	transformRegistry["alphanumericonly"] = alphaNumericOnly
+	transformRegistry["stripcontrol"] = stripControl
+	transformRegistry["wordcount"] = wordCount
+	transformRegistry["charcount"] = charCount
+	transformRegistry["reversestring"] = reverseString
+	transformRegistry["initials"] = initials
+	transformRegistry["normalizemac"] = normalizeMAC
+	transformRegistry["base32encode"] = base32Encode
+	transformRegistry["base32decode"] = base32Decode
+	transformRegistry["rot13"] = rot13
+	transformRegistry["bitflag"] = bitFlag
+	transformRegistry["convertunit"] = convertUnit
+	transformRegistry["checkdigit"] = checkDigit
+	transformRegistry["timezoneinfo"] = timezoneInfo
+	transformRegistry["template"] = renderTemplate
+	transformRegistry["dynamicfield"] = dynamicField
+	transformRegistry["flattenmap"] = flattenMap
+	transformRegistry["ordinal"] = ordinal
+	transformRegistry["numbertowords"] = numberToWords
+	transformRegistry["jsonparse"] = jsonParse
+	transformRegistry["formatbool"] = formatBool
+	transformRegistry["jsonstringify"] = jsonStringify
+	transformRegistry["applyif"] = applyIf
+	transformRegistry["numberformat"] = numberFormat
+	transformRegistry["constant"] = constantValue
+	transformRegistry["env"] = envValue
+	transformRegistry["isweekend"] = isWeekend
+	transformRegistry["weekdayname"] = weekdayName
 
 	// Register STRICT transformation variants
 	transformRegistry["musttoint"] = mustToInt
@@ -72,16 +182,35 @@ func init() {
 	transformRegistry["musttobool"] = mustToBool
 	transformRegistry["mustepochtodate"] = mustEpochToDate
 	transformRegistry["mustdateconvert"] = mustDateConvert
+	transformRegistry["musttoboolcustom"] = mustToBoolCustom
+	transformRegistry["mustsumdelimited"] = mustSumDelimited
+	transformRegistry["mustnormalizetimestamp"] = mustNormalizeTimestamp
+	transformRegistry["mustcheckdigit"] = mustCheckDigit
+	transformRegistry["mustbase32decode"] = mustBase32Decode
+	transformRegistry["mustnormalizepercent"] = mustNormalizePercent
+	transformRegistry["mustjsonparse"] = mustJsonParse
 
 	// Register validation functions (which return error on failure)
 	transformRegistry["validaterequired"] = validateRequired
 	transformRegistry["validateregex"] = validateRegex
 	transformRegistry["validatenumericrange"] = validateNumericRange
+	transformRegistry["validateinteger"] = validateInteger
+	transformRegistry["validatedaterange"] = validateDateRange
 	transformRegistry["validateallowedvalues"] = validateAllowedValues
+	transformRegistry["validateinfile"] = validateInFile
+	transformRegistry["validatemonotonic"] = validateMonotonic
+	transformRegistry["validateprintable"] = validatePrintable
+	transformRegistry["validatemac"] = validateMAC
+	transformRegistry["validatehash"] = validateHash
+	transformRegistry["validatecharset"] = validateCharset
 }
 
+// MergeFields is returned by transforms (e.g., flattenMap) that produce several output fields at once.
+// The processor merges these fields into the output record instead of assigning the rule's target.
+type MergeFields map[string]interface{}
+
 // ApplyTransform looks up the specified transformation function by name and executes it.
-// It handles parsing shorthand parameters from the transform string (e.g., "regexExtract:pattern").
+// It handles parsing shorthand parameters from the transform string (e.g., "regexExtract:pattern", "regexRedact:pattern").
 // Returns the result of the transformation or the original value if the function is not found.
 // Validation and strict transformation functions return an error, which is passed through.
 func ApplyTransform(transformString string, params map[string]interface{}, sourceValue interface{}, recordState map[string]interface{}) interface{} {
@@ -108,7 +237,7 @@ func ApplyTransform(transformString string, params map[string]interface{}, sourc
 		if shorthandParam != "" {
 			paramKey := ""
 			switch funcName {
-			case "regexextract", "validateregex":
+			case "regexextract", "validateregex", "regexredact":
 				paramKey = "pattern"
 			}
 
@@ -203,7 +332,7 @@ func regexExtract(value interface{}, _ map[string]interface{}, params map[string
 		return nil
 	}
 
-	re, err := regexp.Compile(pattern)
+	re, err := compileRegex(pattern)
 	if err != nil {
 		logging.Logf(logging.Error, "regexExtract: Invalid regex pattern '%s': %v", pattern, err)
 		return nil
@@ -307,6 +436,50 @@ func branchTransform(value interface{}, record map[string]interface{}, params ma
 	return value
 }
 
+// applyIf applies the 'then' transform (name, optionally with a ":" shorthand value) and its 'thenParams' only when
+// 'condition' (a govaluate expression over the record's fields and inputValue, e.g. "type == 'X'") is true.
+// Otherwise, or if the condition cannot be evaluated, the input is returned unchanged. Errors from strict or
+// validation functions are passed through.
+func applyIf(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	condition, _ := getStringParam(params, "condition")
+	then, _ := getStringParam(params, "then")
+	if condition == "" || then == "" {
+		logging.Logf(logging.Warning, "applyIf: requires non-empty 'condition' and 'then' string parameters.")
+		return value
+	}
+
+	expression, err := govaluate.NewEvaluableExpression(condition)
+	if err != nil {
+		logging.Logf(logging.Error, "applyIf: Failed to parse condition '%s': %v", condition, err)
+		return value
+	}
+	exprParams := make(map[string]interface{}, len(record)+1)
+	for k, v := range record {
+		exprParams[k] = v
+	}
+	exprParams["inputValue"] = value
+	result, err := expression.Evaluate(exprParams)
+	if err != nil {
+		logging.Logf(logging.Warning, "applyIf: Failed to evaluate condition '%s': %v. Returning original value.", condition, err)
+		return value
+	}
+	if matched, isBool := result.(bool); !isBool || !matched {
+		logging.Logf(logging.Debug, "applyIf: Condition '%s' is not true; returning original value: %v", condition, value)
+		return value
+	}
+
+	thenParams, _ := params["thenParams"].(map[string]interface{})
+	return ApplyTransform(then, thenParams, value, record)
+}
+
+// dateFallbackFormats are the layouts tried by dateConvert-style transforms when no inputFormat is given
+// and the value is not RFC3339.
+var dateFallbackFormats = []string{
+	"2006-01-02", "2006/01/02", "01/02/2006", "2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05", time.RFC1123Z, time.RFC1123, time.RFC822Z,
+	time.RFC822, "01-02-06", "20060102",
+}
+
 // dateConvert converts a date/time string or time.Time object from one format to another.
 func dateConvert(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
 	strVal, isString := value.(string)
@@ -336,13 +509,8 @@ func dateConvert(value interface{}, _ map[string]interface{}, params map[string]
 	t, err := time.Parse(inputFormat, strVal)
 
 	if err != nil && originalInputFormat == "" {
-		fallbacks := []string{
-			"2006-01-02", "2006/01/02", "01/02/2006", "2006-01-02T15:04:05Z07:00",
-			"2006-01-02 15:04:05", time.RFC1123Z, time.RFC1123, time.RFC822Z,
-			time.RFC822, "01-02-06", "20060102",
-		}
 		parsed := false
-		for _, fbFormat := range fallbacks {
+		for _, fbFormat := range dateFallbackFormats {
 			if t, err = time.Parse(fbFormat, strVal); err == nil {
 				parsed = true
 				logging.Logf(logging.Debug, "dateConvert: Parsed '%s' using fallback format '%s'", strVal, fbFormat)
@@ -404,18 +572,20 @@ func multiDateConvert(value interface{}, _ map[string]interface{}, params map[st
 	return value
 }
 
-// toInt attempts to convert the input value to an int64.
-func toInt(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
-	if i, ok := parseValueAsInt64(value); ok {
+// toInt attempts to convert the input value to an int64. Optional 'thousandsSep' and 'decimalSep' params parse
+// locale-style strings such as "1.234" (see normalizeNumberSeparators).
+func toInt(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if i, ok := parseValueAsInt64(normalizeNumberSeparators(value, params)); ok {
 		return i
 	}
 	logging.Logf(logging.Warning, "toInt: conversion failed for input '%v' (type %T); returning nil", value, value)
 	return nil
 }
 
-// toFloat attempts to convert the input value to a float64.
-func toFloat(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
-	if f, ok := parseValueAsFloat64(value); ok {
+// toFloat attempts to convert the input value to a float64. Optional 'thousandsSep' and 'decimalSep' params parse
+// locale-style strings such as "1.234,56" (see normalizeNumberSeparators).
+func toFloat(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if f, ok := parseValueAsFloat64(normalizeNumberSeparators(value, params)); ok {
 		return f
 	}
 	logging.Logf(logging.Warning, "toFloat: conversion failed for input '%v' (type %T); returning nil", value, value)
@@ -432,16 +602,11 @@ func toBool(value interface{}, _ map[string]interface{}, _ map[string]interface{
 	case bool:
 		return v
 	case string:
-		lower := strings.ToLower(strings.TrimSpace(v))
-		switch lower {
-		case "true", "1", "yes", "t", "y":
-			return true
-		case "false", "0", "no", "f", "n", "":
-			return false
-		default:
-			logging.Logf(logging.Warning, "toBool: unrecognized string value '%s'; returning nil", v)
-			return nil
+		if b, ok := parseBoolString(v); ok {
+			return b
 		}
+		logging.Logf(logging.Warning, "toBool: unrecognized string value '%s'; returning nil", v)
+		return nil
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		rv := reflect.ValueOf(v)
 		switch rv.Kind() {
@@ -462,6 +627,19 @@ func toBool(value interface{}, _ map[string]interface{}, _ map[string]interface{
 	}
 }
 
+// parseBoolString recognizes the boolean spellings accepted by toBool (case-insensitive, trimmed).
+// An empty string is false. The second result is false for unrecognized strings.
+func parseBoolString(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "1", "yes", "t", "y":
+		return true, true
+	case "false", "0", "no", "f", "n", "":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 // toString converts the input value to its string representation.
 func toString(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
 	if value == nil {
@@ -474,6 +652,47 @@ func toString(value interface{}, _ map[string]interface{}, _ map[string]interfac
 	return fmt.Sprintf("%v", value)
 }
 
+// toJSONString serializes the input value (object, array, or scalar) as a compact JSON string, e.g.
+// []interface{}{"a", "b"} -> `["a","b"]`, so structured values are stored as JSON rather than Go's "[a b]" form.
+// HTML characters are not escaped and map keys are sorted. 'indent' (string, optional; e.g. "  ") pretty-prints
+// objects and arrays across lines. Nil input returns nil; unserializable values (e.g., NaN) return nil.
+func toJSONString(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	indent, _ := getStringParam(params, "indent")
+	result, err := marshalJSONText(value, indent)
+	if err != nil {
+		logging.Logf(logging.Warning, "toJSONString: cannot serialize value of type %T: %v", value, err)
+		return nil
+	}
+	return result
+}
+
+// jsonStringify is toJSONString (same params), except that nil input becomes "null" so the field still holds
+// valid JSON.
+func jsonStringify(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return "null"
+	}
+	return toJSONString(value, record, params)
+}
+
+// jsonParse decodes a JSON-encoded string into its value: objects become map[string]interface{}, arrays
+// []interface{}, and numbers float64 (e.g., `{"a":[1,2]}` -> map[a:[1 2]]). Invalid JSON is logged and the
+// original string is returned; non-string input (e.g., an already-parsed value) is returned unchanged.
+func jsonParse(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	if _, ok := value.(string); !ok {
+		return value
+	}
+	result, err := jsonParseValue(value)
+	if err != nil {
+		logging.Logf(logging.Warning, "jsonParse: %v", err)
+		return value
+	}
+	return result
+}
+
 // replaceAll replaces all occurrences of a substring within a string.
 func replaceAll(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
 	strVal, ok := value.(string)
@@ -530,7 +749,46 @@ func substring(value interface{}, _ map[string]interface{}, params map[string]in
 	return string(runes[start:end])
 }
 
+// padLeft pads a string on the left with 'pad' (single rune, default " ") to 'length' runes, e.g. "42" -> "00042"
+// with length 5 and pad "0". Strings already at least 'length' runes long are returned unchanged.
+// Non-string input is returned unchanged.
+func padLeft(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	return padString(value, params, true, "padLeft")
+}
+
+// padRight pads a string on the right with 'pad' (single rune, default " ") to 'length' runes, e.g. "Ann" -> "Ann  "
+// with length 5. Strings already at least 'length' runes long are returned unchanged.
+// Non-string input is returned unchanged.
+func padRight(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	return padString(value, params, false, "padRight")
+}
+
+// ensurePrefix adds 'value' to the start of a string that does not already begin with it, e.g. "42" -> "ID-42" with
+// value "ID-". With 'ignoreCase' (optional bool), an existing prefix in any case (e.g., "id-42") counts as present and
+// is left as is. Non-string input is returned unchanged.
+func ensurePrefix(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	return affixString(value, params, true, false, "ensurePrefix")
+}
+
+// ensureSuffix adds 'value' to the end of a string that does not already end with it; see ensurePrefix.
+func ensureSuffix(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	return affixString(value, params, false, false, "ensureSuffix")
+}
+
+// stripPrefix removes one occurrence of 'value' from the start of a string, e.g. "ID-42" -> "42" with value "ID-".
+// With 'ignoreCase' (optional bool), the prefix is matched in any case. Strings without the prefix and non-string
+// input are returned unchanged.
+func stripPrefix(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	return affixString(value, params, true, true, "stripPrefix")
+}
+
+// stripSuffix removes one occurrence of 'value' from the end of a string; see stripPrefix.
+func stripSuffix(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	return affixString(value, params, false, true, "stripSuffix")
+}
+
 // coalesceTransform returns the first non-nil, non-empty string value from a list of fields in the record.
+// If 'then' names a transform (with optional 'thenParams'), it is applied to the chosen value.
 func coalesceTransform(_ interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
 	fieldsRaw, ok := params["fields"]
 	if !ok {
@@ -555,11 +813,11 @@ func coalesceTransform(_ interface{}, record map[string]interface{}, params map[
 				if strVal, isString := val.(string); isString {
 					if strVal != "" {
 						logging.Logf(logging.Debug, "coalesceTransform: Found non-empty string value '%v' in field '%s'.", val, keyStr)
-						return val
+						return applyCoalesceThen(val, record, params)
 					}
 				} else {
 					logging.Logf(logging.Debug, "coalesceTransform: Found non-nil, non-string value '%v' in field '%s'.", val, keyStr)
-					return val
+					return applyCoalesceThen(val, record, params)
 				}
 			}
 		}
@@ -569,6 +827,17 @@ func coalesceTransform(_ interface{}, record map[string]interface{}, params map[
 	return nil
 }
 
+// applyCoalesceThen applies the optional 'then' transform (name, optionally with a ":" shorthand value) and its
+// 'thenParams' to the value chosen by coalesce. Errors from strict or validation functions are passed through.
+func applyCoalesceThen(chosen interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	then, _ := getStringParam(params, "then")
+	if then == "" {
+		return chosen
+	}
+	thenParams, _ := params["thenParams"].(map[string]interface{})
+	return ApplyTransform(then, thenParams, chosen, record)
+}
+
 // ValueToStringForHash provides a consistent, canonical string representation // CORRECTED: Exported
 // for different data types, suitable for generating stable hashes.
 func ValueToStringForHash(v interface{}) string {
@@ -672,447 +941,3388 @@ func hashTransform(_ interface{}, record map[string]interface{}, params map[stri
 	return hex.EncodeToString(hashedBytes)
 }
 
-// --- Strict Transformation Variants (Return error on failure) ---
+// toBoolCustom converts a value to a boolean using the caller-supplied 'truthy' and 'falsy' vocabularies.
+// Returns nil if the value matches neither set.
+func toBoolCustom(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	result, err := parseCustomBool(value, params)
+	if err != nil {
+		logging.Logf(logging.Warning, "toBoolCustom: %v; returning nil", err)
+		return nil
+	}
+	return result
+}
 
-// mustToInt ensures conversion to int64, returns error on failure.
-func mustToInt(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
-	if i, ok := parseValueAsInt64(value); ok {
-		return i
+// formatBool renders a boolean or bool-like value (parsed like toBool, e.g. "yes", "0", 1) as one of two custom
+// strings: 'trueValue' and 'falseValue' (string, required), e.g. "Yes"/"No" or "1"/"0". Nil or unrecognized
+// input returns nil.
+func formatBool(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
 	}
-	return fmt.Errorf("mustToInt: conversion failed for input '%v' (type %T)", value, value)
+	b, ok := toBool(value, nil, nil).(bool)
+	if !ok {
+		return nil
+	}
+	key := "falseValue"
+	if b {
+		key = "trueValue"
+	}
+	out, exists := getStringParam(params, key)
+	if !exists {
+		logging.Logf(logging.Warning, "formatBool: missing '%s' parameter; returning nil", key)
+		return nil
+	}
+	return out
 }
 
-// mustToFloat ensures conversion to float64, returns error on failure.
-func mustToFloat(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
-	if f, ok := parseValueAsFloat64(value); ok {
-		return f
+// parsePercent parses a percentage such as "45%" or "45" into a number.
+// The 'scale' parameter selects "fraction" (default, 45% -> 0.45) or "whole" (45% -> 45).
+// Returns nil if the input cannot be parsed as a percentage.
+func parsePercent(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	num, _, err := percentValue(value, params)
+	if err != nil {
+		logging.Logf(logging.Warning, "parsePercent: %v; returning nil", err)
+		return nil
 	}
-	return fmt.Errorf("mustToFloat: conversion failed for input '%v' (type %T)", value, value)
+	return num
 }
 
-// mustToBool ensures conversion to bool, returns error on failure or ambiguity.
-func mustToBool(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+// normalizePercent parses a percentage like parsePercent ("45%", "45", or a number) and checks that it lies in the
+// valid range: 0-1 for 'scale' "fraction" (default, 45% -> 0.45) or 0-100 for "whole" (45% -> 45). With 'clamp'
+// (bool) set, out-of-range values are clamped to the nearest bound (e.g., "110%" -> 100 with scale "whole");
+// otherwise they return nil. Unparseable input also returns nil.
+func normalizePercent(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
 	if value == nil {
-		return fmt.Errorf("mustToBool: input is nil")
+		return nil
 	}
-	switch v := value.(type) {
-	case bool:
-		return v
-	case string:
-		lower := strings.ToLower(strings.TrimSpace(v))
-		switch lower {
-		case "true", "1", "yes", "t", "y":
-			return true
-		case "false", "0", "no", "f", "n":
-			return false
-		default:
-			return fmt.Errorf("mustToBool: unrecognized or ambiguous string value '%s'", v)
-		}
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		rv := reflect.ValueOf(v)
-		switch rv.Kind() {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return rv.Int() != 0
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			return rv.Uint() != 0
-		default:
-			return fmt.Errorf("mustToBool: internal error handling numeric type %T", value)
-		}
-	case float32, float64:
-		numVal, _ := parseValueAsFloat64(v)
-		return numVal != 0.0
-	default:
-		return fmt.Errorf("mustToBool: conversion received unsupported type '%T'", value)
+	result, err := normalizePercentValue(value, params)
+	if err != nil {
+		logging.Logf(logging.Warning, "normalizePercent: %v; returning nil", err)
+		return nil
 	}
+	return result
 }
 
-// mustEpochToDate ensures conversion, returns error on failure.
-func mustEpochToDate(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
-	var epoch int64
-	parsed := false
-
-	if fVal, ok := parseValueAsFloat64(value); ok {
-		epoch = int64(math.Trunc(fVal))
-		parsed = true
+// parseCurrency parses a currency string such as "$1,234.56", "USD 1234.56", or (with 'decimalSeparator' ","
+// and 'groupSeparator' ".") "1.234,56 €" into a float64. Parameters: 'decimalSeparator' (default "."),
+// 'groupSeparator' (default ","), 'symbols' (array of strings to strip, default ["$", "€", "£", "¥"]) and
+// 'output' ("amount" (default) or "currency"). Group separators must split the integer part into groups of
+// three digits, so "1.234,56" read with the default separators returns nil instead of 1.23456.
+// With output "currency", the detected ISO code (e.g., "USD", or mapped from a known symbol) is returned
+// instead, so a second mapping can capture the currency into another field.
+// Accounting-style negatives "(1,234.56)" are supported. Returns nil on failure.
+func parseCurrency(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
 	}
-	if !parsed {
-		if iVal, ok := parseValueAsInt64(value); ok {
-			epoch = iVal
-			parsed = true
+	strVal, isString := value.(string)
+	if !isString {
+		if f, ok := parseValueAsFloat64(value); ok {
+			if output, _ := getStringParam(params, "output"); strings.EqualFold(output, "currency") {
+				return nil
+			}
+			return f
 		}
+		logging.Logf(logging.Warning, "parseCurrency: unsupported input type %T; returning nil", value)
+		return nil
 	}
 
-	if !parsed {
-		return fmt.Errorf("mustEpochToDate: could not parse input '%v' (type %T) as epoch seconds", value, value)
+	decimalSep, ok := getStringParam(params, "decimalSeparator")
+	if !ok || decimalSep == "" {
+		decimalSep = "."
+	}
+	groupSep, ok := getStringParam(params, "groupSeparator")
+	if !ok || groupSep == "" {
+		groupSep = ","
+	}
+	symbols := []string{"$", "€", "£", "¥"}
+	if symbolsRaw, ok := params["symbols"].([]interface{}); ok {
+		symbols = make([]string, 0, len(symbolsRaw))
+		for _, s := range symbolsRaw {
+			if sym, isStr := s.(string); isStr && sym != "" {
+				symbols = append(symbols, sym)
+			}
+		}
 	}
 
-	t := time.Unix(epoch, 0).UTC()
-	return t.Format("2006-01-02")
-}
+	cleaned := strings.TrimSpace(strVal)
+	currency := ""
+	if m := currencyCodeRegex.FindStringSubmatch(cleaned); m != nil {
+		currency = m[1] + m[2]
+		cleaned = strings.TrimSpace(currencyCodeRegex.ReplaceAllString(cleaned, ""))
+	}
+	for _, sym := range symbols {
+		if strings.Contains(cleaned, sym) {
+			if currency == "" {
+				currency = currencySymbolCodes[sym]
+			}
+			cleaned = strings.ReplaceAll(cleaned, sym, "")
+		}
+	}
 
-// mustDateConvert ensures date string conversion, returns error on failure.
-func mustDateConvert(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
-	strVal, isString := value.(string)
-	tVal, isTime := value.(time.Time)
+	if output, _ := getStringParam(params, "output"); strings.EqualFold(output, "currency") {
+		if currency == "" {
+			logging.Logf(logging.Warning, "parseCurrency: no currency code or known symbol found in '%s'; returning nil", strVal)
+			return nil
+		}
+		return currency
+	}
 
-	if !isString && !isTime {
-		return fmt.Errorf("mustDateConvert: input value is not a string or time.Time (type %T)", value)
+	cleaned = strings.TrimSpace(cleaned)
+	negative := false
+	if strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")") {
+		negative = true
+		cleaned = strings.TrimSpace(cleaned[1 : len(cleaned)-1])
 	}
-	outputFormat, _ := getStringParam(params, "outputFormat")
-	if outputFormat == "" {
-		outputFormat = time.RFC3339
+	cleaned = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) && !strings.ContainsRune(groupSep, r) {
+			return -1
+		}
+		return r
+	}, cleaned)
+	cleaned, _ = normalizeNumberSeparators(cleaned, map[string]interface{}{"thousandsSep": groupSep, "decimalSep": decimalSep}).(string)
+
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || cleaned == "" {
+		logging.Logf(logging.Warning, "parseCurrency: could not parse '%s' as a currency amount; returning nil", strVal)
+		return nil
 	}
-	if isTime {
-		return tVal.Format(outputFormat)
+	if negative {
+		amount = -amount
 	}
+	return amount
+}
 
-	inputFormat, _ := getStringParam(params, "inputFormat")
-	originalInputFormat := inputFormat
-	if inputFormat == "" {
-		inputFormat = time.RFC3339
+// countryCode standardizes country identifiers using the embedded ISO 3166-1 table.
+// The 'direction' parameter selects the conversion: "toAlpha2" (name, alpha-2 or alpha-3 -> alpha-2),
+// "toName" (code or name -> official short name) or "alpha2ToAlpha3". Matching is case-insensitive.
+// Returns nil if the input is not recognized.
+func countryCode(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, ok := value.(string)
+	if !ok {
+		logging.Logf(logging.Warning, "countryCode: input value is not a string (type %T); returning nil", value)
+		return nil
 	}
+	direction, _ := getStringParam(params, "direction")
 
-	var t time.Time
-	var err error
-
-	t, err = time.Parse(inputFormat, strVal)
-	if err != nil && originalInputFormat == "" {
-		fallbacks := []string{
-			"2006-01-02", "2006/01/02", "01/02/2006", "2006-01-02T15:04:05Z07:00",
-			"2006-01-02 15:04:05", time.RFC1123Z, time.RFC1123, time.RFC822Z,
-			time.RFC822, "01-02-06", "20060102",
+	switch strings.ToLower(direction) {
+	case "toalpha2":
+		if c, found := lookupCountry(strVal); found {
+			return c.alpha2
 		}
-		parsed := false
-		var tLoop time.Time
-		var errLoop error
-		for _, fbFormat := range fallbacks {
-			tLoop, errLoop = time.Parse(fbFormat, strVal)
-			if errLoop == nil {
-				t = tLoop
-				err = nil
-				parsed = true
-				break
-			}
+	case "toname":
+		if c, found := lookupCountry(strVal); found {
+			return c.name
 		}
-		if !parsed {
-			return fmt.Errorf("mustDateConvert: failed to parse '%s' with default format or common fallbacks", strVal)
+	case "alpha2toalpha3":
+		if c, found := countriesByAlpha2[strings.ToUpper(strings.TrimSpace(strVal))]; found {
+			return c.alpha3
 		}
-	} else if err != nil {
-		return fmt.Errorf("mustDateConvert: failed to parse '%s' with specified format '%s': %w", strVal, inputFormat, err)
+	default:
+		logging.Logf(logging.Warning, "countryCode: unknown direction '%s'; returning nil", direction)
+		return nil
 	}
-	return t.Format(outputFormat)
-}
-
-// --- Validation Function Implementations (Return error on failure) ---
 
-// validateRequired checks if a value is present (non-nil and non-empty/whitespace string).
-func validateRequired(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
-	if value == nil {
-		return fmt.Errorf("required value is missing (nil)")
-	}
-	if strVal, ok := value.(string); ok {
-		if strings.TrimSpace(strVal) == "" {
-			return fmt.Errorf("required string value is empty or whitespace")
-		}
-	}
-	return value
+	logging.Logf(logging.Warning, "countryCode: unrecognized country '%s' for direction '%s'; returning nil", strVal, direction)
+	return nil
 }
 
-// validateRegex checks if a string value matches a regex pattern.
-func validateRegex(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+// regexRedact replaces every match of a regex pattern within a string with a replacement
+// (default "[REDACTED]"). Non-string values pass through unchanged.
+func regexRedact(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
 	strVal, ok := value.(string)
 	if !ok {
-		// Allow non-strings to pass if not required implicitly
-		// Return the original value if it's not a string
 		return value
-		// If non-strings should fail, use:
-		// return fmt.Errorf("input value type %T is not a string, cannot validate with regex", value)
 	}
 	pattern, ok := getStringParam(params, "pattern")
 	if !ok || pattern == "" {
-		return fmt.Errorf("missing or empty 'pattern' string parameter for validateRegex")
+		logging.Logf(logging.Warning, "regexRedact: missing or invalid 'pattern' parameter; returning original value")
+		return value
 	}
-
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return fmt.Errorf("invalid regex pattern '%s': %w", pattern, err)
+	replacement, ok := getStringParam(params, "replacement")
+	if !ok {
+		replacement = "[REDACTED]"
 	}
 
-	if !re.MatchString(strVal) {
-		return fmt.Errorf("value %s does not match required pattern '%s'", strconv.Quote(strVal), pattern)
+	re, err := compileRegex(pattern)
+	if err != nil {
+		logging.Logf(logging.Warning, "regexRedact: invalid regex pattern '%s': %v; returning original value", pattern, err)
+		return value
 	}
-
-	return value
+	return re.ReplaceAllLiteralString(strVal, replacement)
 }
 
-// validateNumericRange checks if a numeric value falls within a specified min/max range.
-func validateNumericRange(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
-	numVal, ok := parseValueAsFloat64(value)
+// collapseRepeats collapses runs of the same character into a single occurrence.
+// If the 'chars' parameter is set, only runs of those characters are collapsed
+// (e.g., "- " collapses repeated hyphens and spaces); otherwise any repeated character is collapsed.
+// Non-string values pass through unchanged.
+func collapseRepeats(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, ok := value.(string)
 	if !ok {
-		// Allow non-numerics to pass validation by default
-		// Return the original value if it's not a valid number
 		return value
-		// If non-numerics should fail, use:
-		// return fmt.Errorf("input value '%v' (type %T) is not a valid number for range check", value, value)
 	}
-
-	_, minKeyExists := params["min"]
-	_, maxKeyExists := params["max"]
-
-	if !minKeyExists && !maxKeyExists {
-		return fmt.Errorf("requires at least 'min' or 'max' parameter for validateNumericRange")
-	}
-
-	var minVal float64
-	var minOK bool
-	if minKeyExists {
-		minVal, minOK = parseParamAsNumber(params["min"])
-		if !minOK {
-			return fmt.Errorf("invalid 'min' parameter: '%v' is not a valid number", params["min"])
+	chars, hasChars := getStringParam(params, "chars")
+
+	var sb strings.Builder
+	sb.Grow(len(strVal))
+	var prev rune
+	first := true
+	for _, r := range strVal {
+		if !first && r == prev && (!hasChars || strings.ContainsRune(chars, r)) {
+			continue
 		}
+		sb.WriteRune(r)
+		prev = r
+		first = false
 	}
+	return sb.String()
+}
 
-	var maxVal float64
-	var maxOK bool
-	if maxKeyExists {
-		maxVal, maxOK = parseParamAsNumber(params["max"])
-		if !maxOK {
-			return fmt.Errorf("invalid 'max' parameter: '%v' is not a valid number", params["max"])
+// fuzzyMatch reports whether a value is similar to the 'reference' parameter.
+// Similarity is 1 - (Levenshtein distance / length of the longer string), compared against
+// 'threshold' (0-1, default 0.8). Set 'caseInsensitive' to ignore case. Nil input returns false.
+func fuzzyMatch(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return false
+	}
+	reference, ok := getStringParam(params, "reference")
+	if !ok {
+		logging.Logf(logging.Warning, "fuzzyMatch: missing 'reference' string parameter; returning false")
+		return false
+	}
+	threshold := 0.8
+	if rawThreshold, exists := params["threshold"]; exists {
+		t, isNum := parseParamAsNumber(rawThreshold)
+		if !isNum || t < 0 || t > 1 {
+			logging.Logf(logging.Warning, "fuzzyMatch: invalid 'threshold' %v (must be between 0 and 1); returning false", rawThreshold)
+			return false
 		}
+		threshold = t
 	}
 
-	if minKeyExists && minOK && numVal < minVal {
-		return fmt.Errorf("value %v is less than minimum allowed %v", numVal, minVal)
+	strVal, isString := value.(string)
+	if !isString {
+		strVal = fmt.Sprintf("%v", value)
 	}
-	if maxKeyExists && maxOK && numVal > maxVal {
-		return fmt.Errorf("value %v is greater than maximum allowed %v", numVal, maxVal)
+	if caseInsensitive, _ := getBoolParam(params, "caseInsensitive"); caseInsensitive {
+		strVal = strings.ToLower(strVal)
+		reference = strings.ToLower(reference)
 	}
 
-	return value
+	return stringSimilarity(strVal, reference) >= threshold
 }
 
-// validateAllowedValues checks if a value is present in a predefined list.
-func validateAllowedValues(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
-	allowedValuesRaw, ok := params["values"]
-	if !ok {
-		return fmt.Errorf("missing 'values' array parameter for validateAllowedValues")
+// editDistance returns the Levenshtein distance (single-rune insertions, deletions, or substitutions) between
+// the value and the record field named by the 'field' parameter, as an int. Non-string values are formatted
+// with %v. Set 'caseInsensitive' to ignore case. Returns nil if either value is nil or 'field' is missing.
+func editDistance(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	field, ok := getStringParam(params, "field")
+	if !ok || field == "" {
+		logging.Logf(logging.Warning, "editDistance: missing or invalid 'field' parameter")
+		return nil
 	}
-	allowedValuesSlice, ok := allowedValuesRaw.([]interface{})
-	if !ok || len(allowedValuesSlice) == 0 {
-		return fmt.Errorf("'values' parameter is not a non-empty array for validateAllowedValues")
+	other := record[field]
+	if value == nil || other == nil {
+		return nil
 	}
-
-	found := false
-	for _, allowed := range allowedValuesSlice {
-		cmp, err := CompareValues(value, allowed)
-		if err == nil && cmp == 0 {
-			found = true
-			break
-		} else if err != nil {
-			logging.Logf(logging.Debug, "validateAllowedValues: Could not compare input '%v' (%T) with allowed value '%v' (%T): %v", value, value, allowed, allowed, err)
-		}
+	strVal, isString := value.(string)
+	if !isString {
+		strVal = fmt.Sprintf("%v", value)
 	}
-
-	if !found {
-		return fmt.Errorf("value '%v' is not in the list of allowed values", value)
+	otherVal, isString := other.(string)
+	if !isString {
+		otherVal = fmt.Sprintf("%v", other)
 	}
-
-	return value
+	if caseInsensitive, _ := getBoolParam(params, "caseInsensitive"); caseInsensitive {
+		strVal = strings.ToLower(strVal)
+		otherVal = strings.ToLower(otherVal)
+	}
+	return levenshteinDistance([]rune(strVal), []rune(otherVal))
 }
 
-// --- Helper Functions ---
-
-// getStringParam retrieves a string value from the parameters map.
-func getStringParam(params map[string]interface{}, key string) (string, bool) {
-	val, ok := params[key]
+// dedupList removes duplicate elements from a delimited string, keeping the first occurrence of each.
+// Params: 'separator' (default ","), 'trim' (trim whitespace around elements), and 'caseInsensitive'
+// (compare elements ignoring case; the first spelling is kept). Non-string values pass through unchanged.
+func dedupList(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, ok := value.(string)
 	if !ok {
-		return "", false
+		return value
 	}
-	strVal, ok := val.(string)
-	return strVal, ok
+	separator := getListSeparator(params)
+	trim, _ := getBoolParam(params, "trim")
+	caseInsensitive, _ := getBoolParam(params, "caseInsensitive")
+
+	parts := strings.Split(strVal, separator)
+	seen := make(map[string]struct{}, len(parts))
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trim {
+			part = strings.TrimSpace(part)
+		}
+		key := part
+		if caseInsensitive {
+			key = strings.ToLower(part)
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, part)
+	}
+	return strings.Join(result, separator)
 }
 
-// getIntParam retrieves an integer value from the parameters map.
-func getIntParam(params map[string]interface{}, key string) (int, bool) {
-	val, ok := params[key]
+// sortList sorts the elements of a delimited string (e.g., "c,a,b" -> "a,b,c").
+// Params: 'separator' (default ",") and 'numeric' (sort by numeric value). If 'numeric' is set but
+// any element is not a number, the list falls back to a plain string sort. Non-string values pass through unchanged.
+func sortList(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, ok := value.(string)
 	if !ok {
-		return 0, false
+		return value
 	}
-	return parseParamAsInt(val)
+	separator := getListSeparator(params)
+	parts := strings.Split(strVal, separator)
+
+	if numeric, _ := getBoolParam(params, "numeric"); numeric {
+		nums := make([]float64, len(parts))
+		allNumeric := true
+		for i, part := range parts {
+			n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				allNumeric = false
+				break
+			}
+			nums[i] = n
+		}
+		if allNumeric {
+			indices := make([]int, len(parts))
+			for i := range indices {
+				indices[i] = i
+			}
+			sort.SliceStable(indices, func(a, b int) bool { return nums[indices[a]] < nums[indices[b]] })
+			sorted := make([]string, len(parts))
+			for i, idx := range indices {
+				sorted[i] = parts[idx]
+			}
+			return strings.Join(sorted, separator)
+		}
+		logging.Logf(logging.Debug, "sortList: non-numeric element in '%s'; falling back to string sort", strVal)
+	}
+
+	sort.Strings(parts)
+	return strings.Join(parts, separator)
 }
 
-// parseValueAsInt64 attempts to parse various input types into int64.
-func parseValueAsInt64(value interface{}) (int64, bool) {
-	switch v := value.(type) {
-	case int:
-		return int64(v), true
-	case int8:
-		return int64(v), true
-	case int16:
-		return int64(v), true
-	case int32:
-		return int64(v), true
-	case int64:
-		return v, true
-	case uint:
-		if uint64(v) > uint64(math.MaxInt64) {
-			return 0, false
+// listCount returns the number of elements in a delimited string (split on 'separator', default ",")
+// or in a slice value. Set 'ignoreEmpty' to skip blank string elements and nil slice elements.
+// Empty strings and nil return 0; other value types log a warning and return nil.
+func listCount(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	ignoreEmpty, _ := getBoolParam(params, "ignoreEmpty")
+	isEmpty := func(elem interface{}) bool {
+		if elem == nil {
+			return true
 		}
-		return int64(v), true
-	case uint8:
-		return int64(v), true
-	case uint16:
-		return int64(v), true
-	case uint32:
-		return int64(v), true
-	case uint64:
-		if v > uint64(math.MaxInt64) {
-			return 0, false
+		s, isStr := elem.(string)
+		return isStr && strings.TrimSpace(s) == ""
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		if v == "" {
+			return 0
 		}
-		return int64(v), true
-	case float32:
-		if float32(int64(v)) == v && v >= float32(math.MinInt64) && v <= float32(math.MaxInt64) {
-			return int64(v), true
+		parts := strings.Split(v, getListSeparator(params))
+		if !ignoreEmpty {
+			return len(parts)
 		}
-		return 0, false
-	case float64:
-		if float64(int64(v)) == v && v >= float64(math.MinInt64) && v <= float64(math.MaxInt64) {
-			return int64(v), true
+		count := 0
+		for _, part := range parts {
+			if !isEmpty(part) {
+				count++
+			}
 		}
-		return 0, false
-	case string:
-		cleanV := strings.TrimSpace(v)
-		if cleanV == "" {
-			return 0, false
+		return count
+	default:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			logging.Logf(logging.Warning, "listCount: unsupported input type %T; returning nil", value)
+			return nil
 		}
-		i, err := strconv.ParseInt(cleanV, 10, 64)
-		if err == nil {
-			return i, true
+		if !ignoreEmpty {
+			return rv.Len()
 		}
-		f, errF := strconv.ParseFloat(cleanV, 64)
-		if errF == nil && f == math.Trunc(f) && f >= float64(math.MinInt64) && f <= float64(math.MaxInt64) {
-			if float64(int64(f)) == f {
-				return int64(f), true
+		count := 0
+		for i := 0; i < rv.Len(); i++ {
+			if !isEmpty(rv.Index(i).Interface()) {
+				count++
 			}
 		}
-		return 0, false
-	default:
-		return 0, false
+		return count
 	}
 }
 
-// parseValueAsFloat64 attempts to parse various input types into float64.
-func parseValueAsFloat64(value interface{}) (float64, bool) {
-	switch v := value.(type) {
-	case int, int8, int16, int32, int64:
-		rv := reflect.ValueOf(v)
-		return float64(rv.Int()), true
-	case uint, uint8, uint16, uint32, uint64:
-		rv := reflect.ValueOf(v)
-		return float64(rv.Uint()), true
-	case float32:
-		return float64(v), true
-	case float64:
-		return v, true
-	case string:
-		cleanV := strings.TrimSpace(v)
-		if cleanV == "" {
-			return 0, false
+// nthField returns the element at 'index' (0-based) of a delimited string split on 'separator' (default ",").
+// A negative index counts from the end (-1 is the last element). If the index is out of range, or the
+// value is nil, the 'default' parameter is returned (nil if unset). Other non-string values pass through unchanged.
+func nthField(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	defaultVal := params["default"]
+	if value == nil {
+		return defaultVal
+	}
+	strVal, ok := value.(string)
+	if !ok {
+		return value
+	}
+	index, ok := getIntParam(params, "index")
+	if !ok {
+		logging.Logf(logging.Warning, "nthField: missing or invalid 'index' integer parameter; returning default")
+		return defaultVal
+	}
+
+	parts := strings.Split(strVal, getListSeparator(params))
+	if index < 0 {
+		index += len(parts)
+	}
+	if index < 0 || index >= len(parts) {
+		return defaultVal
+	}
+	return parts[index]
+}
+
+// excelSerialToDate converts an Excel date serial number (e.g., 44927) to a date string.
+// Serials count days since 1899-12-30 and replicate Excel's 1900 leap-year bug: serials below 60 are
+// shifted by one day, and serial 60 (the nonexistent 1900-02-29) cannot be represented.
+// A fractional part is treated as the time of day. Set 'date1904' for workbooks using the 1904 date system.
+// 'outputFormat' is a Go layout (default "2006-01-02"). Returns the original value if it cannot be converted.
+func excelSerialToDate(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	serial, ok := parseValueAsFloat64(value)
+	if !ok {
+		logging.Logf(logging.Warning, "excelSerialToDate: could not parse input '%v' (type %T) as a numeric Excel serial.", value, value)
+		return value
+	}
+	outputFormat, _ := getStringParam(params, "outputFormat")
+	if outputFormat == "" {
+		outputFormat = "2006-01-02"
+	}
+
+	var base time.Time
+	if date1904, _ := getBoolParam(params, "date1904"); date1904 {
+		if serial < 0 {
+			logging.Logf(logging.Warning, "excelSerialToDate: serial %v is before the 1904 date system epoch.", serial)
+			return value
 		}
-		f, err := strconv.ParseFloat(cleanV, 64)
-		if err == nil {
-			return f, true
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		switch {
+		case serial < 1:
+			logging.Logf(logging.Warning, "excelSerialToDate: serial %v is before 1900-01-01.", serial)
+			return value
+		case serial >= 60 && serial < 61:
+			logging.Logf(logging.Warning, "excelSerialToDate: serial %v is Excel's nonexistent 1900-02-29.", serial)
+			return value
+		case serial < 60:
+			base = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+		default:
+			base = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
 		}
-		return 0, false
-	default:
-		return 0, false
 	}
+
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	t := base.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+	return t.Format(outputFormat)
 }
 
-// parseParamAsNumber is a convenience wrapper for validating numeric parameters.
-func parseParamAsNumber(v interface{}) (float64, bool) {
-	return parseValueAsFloat64(v)
+// castType converts a value to the type named by the 'type' parameter by delegating to the
+// matching conversion: "int" (toInt), "float" (toFloat), "bool" (toBool), "string" (toString),
+// or "date" (dateConvert, honoring 'inputFormat'/'outputFormat'). Failed conversions return nil.
+func castType(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	targetType, _ := getStringParam(params, "type")
+	switch strings.ToLower(targetType) {
+	case "int":
+		return toInt(value, record, params)
+	case "float":
+		return toFloat(value, record, params)
+	case "bool":
+		return toBool(value, record, params)
+	case "string":
+		return toString(value, record, params)
+	case "date":
+		// Use the strict variant so a parse failure yields nil instead of the original value.
+		result := mustDateConvert(value, record, params)
+		if err, isErr := result.(error); isErr {
+			logging.Logf(logging.Warning, "castType: %v; returning nil", err)
+			return nil
+		}
+		return result
+	default:
+		logging.Logf(logging.Warning, "castType: invalid or missing 'type' parameter '%s'; returning nil", targetType)
+		return nil
+	}
 }
 
-// parseParamAsInt is a convenience wrapper for validating integer parameters.
-func parseParamAsInt(v interface{}) (int, bool) {
-	i64, ok := parseValueAsInt64(v)
-	if !ok {
-		return 0, false
+// sumDelimited splits a string on 'separator' (default ",") and returns the float64 sum of its numeric elements
+// (e.g., "10,20,30" -> 60). Blank elements are ignored and invalid elements are skipped with a warning.
+// An empty string sums to 0; nil returns nil. Numeric (non-string) input is returned as a float64.
+func sumDelimited(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
 	}
-	const maxInt = int(^uint(0) >> 1)
-	const minInt = -maxInt - 1
-	if i64 >= int64(minInt) && i64 <= int64(maxInt) {
-		return int(i64), true
+	sum, invalid, err := sumDelimitedValues(value, params)
+	if err != nil {
+		logging.Logf(logging.Warning, "sumDelimited: %v; returning nil", err)
+		return nil
 	}
-	return 0, false
+	if len(invalid) > 0 {
+		logging.Logf(logging.Warning, "sumDelimited: skipped non-numeric elements %q in '%v'", invalid, value)
+	}
+	return sum
 }
 
-// CompareValues attempts to compare two values of potentially different types.
-func CompareValues(a, b interface{}) (int, error) {
-	if a == nil && b == nil {
-		return 0, nil
+// configLookup maps the value through a key/value table loaded from an external YAML or JSON file.
+// Params: 'file' (string, required; environment variables are expanded), 'default' (any, optional).
+// The file is loaded once per path and cached. Returns 'default' (or nil) on a miss, nil input, or load failure.
+func configLookup(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	defaultVal := params["default"]
+	filePath, _ := params["file"].(string)
+	if filePath == "" {
+		logging.Logf(logging.Warning, "configLookup: missing or invalid 'file' parameter")
+		return defaultVal
 	}
-	if a == nil {
-		return -1, nil
+	table, err := loadLookupTable(util.ExpandEnvUniversal(filePath))
+	if err != nil {
+		logging.Logf(logging.Warning, "configLookup: %v", err)
+		return defaultVal
 	}
-	if b == nil {
-		return 1, nil
+	if value == nil {
+		return defaultVal
 	}
+	if mapped, ok := table[fmt.Sprintf("%v", value)]; ok {
+		return mapped
+	}
+	return defaultVal
+}
 
-	aFloat, aIsNum := parseValueAsFloat64(a)
-	bFloat, bIsNum := parseValueAsFloat64(b)
-
-	if aIsNum && bIsNum {
-		if aFloat < bFloat {
-			return -1, nil
+// severityMap maps severity codes to labels (e.g., 3 -> "ERROR"). 'codes' (map, optional) lists exact code -> label
+// pairs; codes are matched on the value's string form (e.g., 3, 3.0 and " 3 " all match key 3). 'bands' (list,
+// optional) maps numeric values that miss 'codes' to labels by range: each band is {min, max, label}, matching
+// min <= value < max (a missing bound is open), and the first matching band wins. With 'reverse' (bool) set, a label is
+// mapped back to its code instead (labels compared case-insensitively; bands are not used). 'default' (any, optional)
+// is returned for nil input or unknown codes.
+func severityMap(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	defaultVal := params["default"]
+	if value == nil {
+		return defaultVal
+	}
+	codes := severityCodes(params["codes"])
+	if reverse, _ := getBoolParam(params, "reverse"); reverse {
+		label := strings.TrimSpace(fmt.Sprintf("%v", value))
+		keys := make([]string, 0, len(codes))
+		for key := range codes {
+			keys = append(keys, key)
 		}
-		if aFloat > bFloat {
-			return 1, nil
+		sort.Strings(keys)
+		for _, key := range keys {
+			if strings.EqualFold(fmt.Sprintf("%v", codes[key].label), label) {
+				return codes[key].code
+			}
 		}
-		return 0, nil
+		return defaultVal
 	}
 
-	// Only compare if types are compatible beyond numeric
-	typeA := reflect.TypeOf(a)
-	typeB := reflect.TypeOf(b)
-
-	if typeA != typeB {
-		return 0, fmt.Errorf("type mismatch: cannot compare %T with %T", a, b)
+	if entry, ok := codes[strings.TrimSpace(fmt.Sprintf("%v", value))]; ok {
+		return entry.label
 	}
-
-	// Handle specific comparable types
-	switch a.(type) {
-	case string:
-		return strings.Compare(a.(string), b.(string)), nil
+	num, isNum := parseValueAsFloat64(value)
+	if !isNum {
+		return defaultVal
+	}
+	bands, _ := params["bands"].([]interface{})
+	for i, rawBand := range bands {
+		band, isMap := rawBand.(map[string]interface{})
+		if !isMap {
+			logging.Logf(logging.Warning, "severityMap: band %d is not a map; skipping", i)
+			continue
+		}
+		if minRaw, hasMin := band["min"]; hasMin {
+			if minVal, ok := parseParamAsNumber(minRaw); !ok || num < minVal {
+				continue
+			}
+		}
+		if maxRaw, hasMax := band["max"]; hasMax {
+			if maxVal, ok := parseParamAsNumber(maxRaw); !ok || num >= maxVal {
+				continue
+			}
+		}
+		return band["label"]
+	}
+	return defaultVal
+}
+
+// shard returns a deterministic bucket number in [0, buckets) for the value, computed as
+// FNV-1a 32-bit hash of the stringified value modulo 'buckets' (int, required, > 0).
+// Returns nil for nil input or an invalid 'buckets' parameter.
+func shard(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	buckets, ok := getIntParam(params, "buckets")
+	if !ok || buckets <= 0 {
+		logging.Logf(logging.Warning, "shard: missing or invalid 'buckets' parameter (must be a positive integer)")
+		return nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%v", value)))
+	return int(h.Sum32() % uint32(buckets))
+}
+
+// truncateTime floors a timestamp to a multiple of 'interval' (Go duration string, required, e.g. "15m", "1h").
+// Optional params: 'inputFormat' and 'outputFormat' (Go layouts, default RFC3339) and 'timezone' (IANA name).
+// Truncation is aligned to the wall clock of the timezone (or the parsed offset when no timezone is given),
+// so "24h" floors to local midnight. Accepts strings or time.Time; returns the original value on failure.
+func truncateTime(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	intervalStr, _ := getStringParam(params, "interval")
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		logging.Logf(logging.Warning, "truncateTime: missing or invalid 'interval' parameter '%s'", intervalStr)
+		return value
+	}
+
+	var t time.Time
+	switch v := value.(type) {
 	case time.Time:
-		tA, _ := a.(time.Time)
-		tB, _ := b.(time.Time)
-		if tA.Before(tB) {
-			return -1, nil
+		t = v
+	case string:
+		inputFormat, _ := getStringParam(params, "inputFormat")
+		if inputFormat == "" {
+			inputFormat = time.RFC3339
 		}
-		if tA.After(tB) {
-			return 1, nil
+		if t, err = time.Parse(inputFormat, v); err != nil {
+			logging.Logf(logging.Warning, "truncateTime: failed to parse '%s' with format '%s': %v", v, inputFormat, err)
+			return value
 		}
-		return 0, nil
+	default:
+		logging.Logf(logging.Warning, "truncateTime: input value is not a string or time.Time (type %T)", value)
+		return value
+	}
+
+	loc := t.Location()
+	if tzName, ok := getStringParam(params, "timezone"); ok && tzName != "" {
+		if loc, err = time.LoadLocation(tzName); err != nil {
+			logging.Logf(logging.Warning, "truncateTime: invalid 'timezone' parameter '%s': %v", tzName, err)
+			return value
+		}
+		t = t.In(loc)
+	}
+
+	// Truncate the wall-clock time so buckets align with local boundaries rather than UTC.
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC).Truncate(interval)
+	truncated := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+
+	outputFormat, _ := getStringParam(params, "outputFormat")
+	if outputFormat == "" {
+		outputFormat = time.RFC3339
+	}
+	return truncated.Format(outputFormat)
+}
+
+// execTransform pipes the current record, encoded as JSON, to an external command's stdin and returns the
+// JSON value the command writes to stdout. Params: 'command' (string, required), 'args' (array of strings,
+// optional), 'timeout' (Go duration, optional, default 30s). Requires exec to be enabled via SetExecEnabled.
+// Returns an error if exec is disabled, the command fails or times out, or stdout is not valid JSON.
+func execTransform(_ interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	if !IsExecEnabled() {
+		return fmt.Errorf("exec: external commands are disabled; run with -allow-exec to enable")
+	}
+	command, ok := getStringParam(params, "command")
+	if !ok || command == "" {
+		return fmt.Errorf("exec: missing or invalid 'command' parameter")
+	}
+	var args []string
+	if argsRaw, exists := params["args"]; exists {
+		argsSlice, isSlice := argsRaw.([]interface{})
+		if !isSlice {
+			return fmt.Errorf("exec: 'args' parameter must be an array of strings")
+		}
+		for i, a := range argsSlice {
+			argStr, isStr := a.(string)
+			if !isStr {
+				return fmt.Errorf("exec: 'args' item at index %d is not a string", i)
+			}
+			args = append(args, argStr)
+		}
+	}
+	timeout := defaultExecTimeout
+	if timeoutStr, exists := getStringParam(params, "timeout"); exists && timeoutStr != "" {
+		parsed, err := time.ParseDuration(timeoutStr)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("exec: invalid 'timeout' parameter '%s'", timeoutStr)
+		}
+		timeout = parsed
+	}
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("exec: failed to encode record as JSON: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("exec: command '%s' timed out after %v", command, timeout)
+		}
+		return fmt.Errorf("exec: command '%s' failed: %v (stderr: %s)", command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return fmt.Errorf("exec: command '%s' did not write valid JSON to stdout: %w", command, err)
+	}
+	return result
+}
+
+// parseQueryString decodes a URL query string (or the query portion of a full URL) into a map of fields.
+// Params: 'repeated' (string, optional: "first" (default) keeps the first value of a repeated key,
+// "join" joins all values with 'separator' (default ",")).
+// Malformed pairs are skipped with a warning. Returns nil for nil input and the original value for non-strings.
+func parseQueryString(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	strVal, ok := value.(string)
+	if !ok {
+		logging.Logf(logging.Warning, "parseQueryString: input value is not a string (type %T)", value)
+		return value
+	}
+	query := strings.TrimSpace(strVal)
+	if idx := strings.Index(query, "#"); idx >= 0 {
+		query = query[:idx]
+	}
+	if idx := strings.Index(query, "?"); idx >= 0 {
+		query = query[idx+1:]
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		// ParseQuery still returns every pair it could decode; keep those.
+		logging.Logf(logging.Warning, "parseQueryString: malformed query string '%s': %v", strVal, err)
+	}
+
+	repeated, _ := getStringParam(params, "repeated")
+	separator, sepOk := getStringParam(params, "separator")
+	if !sepOk {
+		separator = ","
+	}
+	result := make(map[string]interface{}, len(values))
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		if strings.EqualFold(repeated, "join") {
+			result[key] = strings.Join(vals, separator)
+		} else {
+			result[key] = vals[0]
+		}
+	}
+	return result
+}
+
+// urlParse extracts a single component from a URL string.
+// Params: 'component' (string, required: "scheme", "host", "path", "query", "fragment" or "port").
+// "host" excludes the port; "query" is the raw (still encoded) query string. Returns nil for nil input
+// or an unparseable URL, and the original value for non-string input.
+func urlParse(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	strVal, ok := value.(string)
+	if !ok {
+		logging.Logf(logging.Warning, "urlParse: input value is not a string (type %T)", value)
+		return value
+	}
+	component, _ := getStringParam(params, "component")
+	u, err := url.Parse(strings.TrimSpace(strVal))
+	if err != nil {
+		logging.Logf(logging.Debug, "urlParse: failed to parse URL '%s': %v", strVal, err)
+		return nil
+	}
+	switch strings.ToLower(component) {
+	case "scheme":
+		return u.Scheme
+	case "host":
+		return u.Hostname()
+	case "path":
+		return u.Path
+	case "query":
+		return u.RawQuery
+	case "fragment":
+		return u.Fragment
+	case "port":
+		return u.Port()
+	default:
+		logging.Logf(logging.Warning, "urlParse: missing or invalid 'component' parameter '%s'", component)
+		return nil
+	}
+}
+
+// applyMask formats the value using a mask in which each '#' consumes the next input character and every
+// other character is copied literally, e.g. "1234567890" with "(###) ###-####" gives "(123) 456-7890".
+// Params: 'mask' (string, required), 'extra' (string, optional: "drop" (default) discards input left over
+// after the mask, "append" appends it). Output stops at the first '#' once the input is exhausted.
+// Numbers are formatted without exponent before masking. Returns nil for nil input.
+func applyMask(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	mask, ok := getStringParam(params, "mask")
+	if !ok || mask == "" {
+		logging.Logf(logging.Warning, "applyMask: missing or empty 'mask' parameter")
+		return value
+	}
+	var input string
+	switch v := value.(type) {
+	case string:
+		input = v
+	case float64:
+		input = strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		input = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		input = fmt.Sprintf("%v", v)
+	}
+
+	in := []rune(input)
+	pos, consumedLen := 0, 0
+	var sb strings.Builder
+	for _, m := range mask {
+		if m != '#' {
+			sb.WriteRune(m)
+			continue
+		}
+		if pos >= len(in) {
+			// Input exhausted: drop the literals written since the last consumed character.
+			return sb.String()[:consumedLen]
+		}
+		sb.WriteRune(in[pos])
+		pos++
+		consumedLen = sb.Len()
+	}
+	if pos < len(in) {
+		extra, _ := getStringParam(params, "extra")
+		if strings.EqualFold(extra, "append") {
+			sb.WriteString(string(in[pos:]))
+		}
+	}
+	return sb.String()
+}
+
+// lagField returns the value the record field 'field' had on the previous record (nil for the first record).
+// This transform is STATEFUL: it depends on records being processed one at a time, in input order.
+// Params: 'field' (string, required) names the record field to lag (the mapping's source value is ignored),
+// 'partitionBy' (string, optional) keeps a separate history per value of that field, and 'delta' (bool, optional)
+// returns current - previous as a float64 instead (nil if either value is not numeric).
+// Several lagField rules over the same field share one history, so each sees the same previous value.
+func lagField(_ interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	field, ok := getStringParam(params, "field")
+	if !ok || field == "" {
+		logging.Logf(logging.Warning, "lagField: missing or invalid 'field' parameter")
+		return nil
+	}
+	key := field
+	if partitionBy, hasPartition := getStringParam(params, "partitionBy"); hasPartition && partitionBy != "" {
+		key += "\x00" + partitionBy + "\x00" + ValueToStringForHash(record[partitionBy])
+	}
+
+	current := record[field]
+	previous, hasPrevious := advanceLagState(key, record, current)
+	if !hasPrevious {
+		return nil
+	}
+	if delta, _ := params["delta"].(bool); delta {
+		cur, curOk := parseValueAsFloat64(current)
+		prev, prevOk := parseValueAsFloat64(previous)
+		if !curOk || !prevOk {
+			return nil
+		}
+		return cur - prev
+	}
+	return previous
+}
+
+// normalizeTimestamp parses a flexible date/time value and returns it as an RFC3339 UTC string with fixed precision.
+// Params: 'inputFormat' (Go layout, optional; default RFC3339 plus the dateConvert fallbacks and naive
+// "2006-01-02T15:04:05"), 'precision' (string, optional: "seconds" (default), "millis", "micros" or "nanos").
+// Timestamps without an offset are assumed to be UTC; extra fractional digits are truncated.
+// Returns the original value on failure.
+func normalizeTimestamp(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	result, err := normalizeTimestampValue(value, params)
+	if err != nil {
+		logging.Logf(logging.Warning, "normalizeTimestamp: %v", err)
+		return value
+	}
+	return result
+}
+
+// completeness returns true if every record field listed in 'fields' (array of strings, required) is present
+// and non-empty according to validateRequired (non-nil and not an empty/whitespace string), otherwise false.
+// The input value is ignored.
+func completeness(_ interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	fieldsSlice, ok := params["fields"].([]interface{})
+	if !ok || len(fieldsSlice) == 0 {
+		logging.Logf(logging.Warning, "completeness: missing or empty 'fields' array parameter")
+		return false
+	}
+	for i, fieldInterface := range fieldsSlice {
+		fieldName, isStr := fieldInterface.(string)
+		if !isStr {
+			logging.Logf(logging.Warning, "completeness: field name at index %d is not a string: %v", i, fieldInterface)
+			return false
+		}
+		if _, isErr := validateRequired(record[fieldName], nil, nil).(error); isErr {
+			return false
+		}
+	}
+	return true
+}
+
+// pseudonymFirstNames and pseudonymLastNames are the word lists used by pseudonymize's "name" format.
+var (
+	pseudonymFirstNames = []string{
+		"Alex", "Blake", "Casey", "Dana", "Eden", "Finley", "Gray", "Harper",
+		"Indigo", "Jordan", "Kai", "Logan", "Morgan", "Noel", "Oakley", "Parker",
+		"Quinn", "Riley", "Sage", "Taylor", "Umber", "Val", "Wren", "Xen",
+		"Yael", "Zion", "Avery", "Bailey", "Cameron", "Drew", "Emery", "Frankie",
+	}
+	pseudonymLastNames = []string{
+		"Abbott", "Barker", "Carver", "Dalton", "Ellison", "Fletcher", "Garner", "Hayes",
+		"Ingram", "Jensen", "Keller", "Lawson", "Mercer", "Nash", "Oliver", "Pruitt",
+		"Quimby", "Rhodes", "Sawyer", "Thornton", "Underwood", "Vance", "Walker", "Xiong",
+		"Yates", "Zeller", "Ashford", "Bennett", "Calloway", "Donovan", "Everett", "Foster",
+	}
+)
+
+// pseudonymize replaces the value with a stable pseudonym derived from an HMAC-SHA256 of the value keyed by
+// 'key' (string, required, environment variables expanded). The same value and key always yield the same output.
+// 'format' selects the output: "token" (default, lowercase hex of 'length' characters, 1-64, default 16) or
+// "name" (a "First Last" name from a fixed word list; distinct inputs may share a name).
+// Returns nil for nil input or invalid parameters.
+func pseudonymize(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	key, ok := getStringParam(params, "key")
+	if !ok || key == "" {
+		logging.Logf(logging.Warning, "pseudonymize: missing or empty 'key' string parameter")
+		return nil
+	}
+	mac := hmac.New(sha256.New, []byte(util.ExpandEnvUniversal(key)))
+	mac.Write([]byte(ValueToStringForHash(value)))
+	sum := mac.Sum(nil)
+
+	format, _ := getStringParam(params, "format")
+	switch strings.ToLower(format) {
+	case "", "token":
+		length := 16
+		if _, exists := params["length"]; exists {
+			l, isInt := getIntParam(params, "length")
+			if !isInt || l < 1 || l > hex.EncodedLen(len(sum)) {
+				logging.Logf(logging.Warning, "pseudonymize: 'length' must be an integer between 1 and %d, got %v", hex.EncodedLen(len(sum)), params["length"])
+				return nil
+			}
+			length = l
+		}
+		return hex.EncodeToString(sum)[:length]
+	case "name":
+		first := pseudonymFirstNames[binary.BigEndian.Uint32(sum[0:4])%uint32(len(pseudonymFirstNames))]
+		last := pseudonymLastNames[binary.BigEndian.Uint32(sum[4:8])%uint32(len(pseudonymLastNames))]
+		return first + " " + last
+	default:
+		logging.Logf(logging.Warning, "pseudonymize: unknown 'format' '%s' (must be token or name)", format)
+		return nil
+	}
+}
+
+// detectType returns the inferred type name of the value for profiling: "null" (nil or blank string), "int",
+// "float", "bool", "date", "array", "object", or "string". Strings are checked in that order, so "1" is "int"
+// rather than "bool"; dates are recognized using the dateConvert fallback layouts and RFC3339.
+// Native values are classified by their Go type (whole floats, as decoded from JSON, count as "int").
+func detectType(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	return DetectType(value)
+}
+
+// DetectType returns the type name detectType would report for the value.
+func DetectType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
 	case bool:
-		bA, _ := a.(bool)
-		bB, _ := b.(bool)
-		if bA == bB {
-			return 0, nil
+		return "bool"
+	case time.Time:
+		return "date"
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return "null"
 		}
-		if bA { // true > false
-			return 1, nil
+		if !strings.ContainsAny(trimmed, ".eE") {
+			if _, ok := parseValueAsInt64(trimmed); ok {
+				return "int"
+			}
 		}
-		return -1, nil // false < true
+		if f, ok := parseValueAsFloat64(trimmed); ok && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return "float"
+		}
+		if _, ok := parseBoolString(trimmed); ok {
+			return "bool"
+		}
+		for _, layout := range append([]string{time.RFC3339Nano}, dateFallbackFormats...) {
+			if _, err := time.Parse(layout, trimmed); err == nil {
+				return "date"
+			}
+		}
+		return "string"
+	}
+	if _, ok := parseValueAsInt64(value); ok {
+		return "int"
+	}
+	if _, ok := parseValueAsFloat64(value); ok {
+		return "float"
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "string"
+}
+
+// digitsOnly removes every character except the ASCII digits 0-9 from a string (e.g., "(555) 123-4567" -> "5551234567").
+// With 'keepLeadingPlus' (bool, optional) a '+' that is the first non-space character is kept ("+1 555" -> "+1555").
+// Non-string input is returned unchanged.
+func digitsOnly(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
 	}
+	keepPlus, _ := params["keepLeadingPlus"].(bool)
+	var sb strings.Builder
+	sb.Grow(len(str))
+	if keepPlus && strings.HasPrefix(strings.TrimSpace(str), "+") {
+		sb.WriteByte('+')
+	}
+	for _, r := range str {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
 
-	// Use DeepEqual for non-ordered types like maps/slices ONLY for equality check
-	if reflect.DeepEqual(a, b) {
-		return 0, nil
+// alphaNumericOnly removes every character except letters and digits from a string (e.g., "AB-12 c/3" -> "AB12c3").
+// Letters include non-ASCII letters. Non-string input is returned unchanged.
+func alphaNumericOnly(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
 	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, str)
+}
 
-	// Cannot determine order for other non-primitive, non-time types
-	return 0, fmt.Errorf("unsupported comparison ordering for type %T", a)
+// stripControl removes control characters (e.g., NUL, ESC, tab, newline, DEL) from a string.
+// 'keepWhitespace' (bool, optional) preserves tab, newline and carriage return; 'asciiOnly' (bool, optional) also
+// removes every character outside printable ASCII. Non-string input is returned unchanged.
+func stripControl(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	keepWhitespace, _ := getBoolParam(params, "keepWhitespace")
+	asciiOnly, _ := getBoolParam(params, "asciiOnly")
+	return strings.Map(func(r rune) rune {
+		if keepWhitespace && (r == '\t' || r == '\n' || r == '\r') {
+			return r
+		}
+		if unicode.IsControl(r) || (asciiOnly && (r < 0x20 || r > 0x7E)) {
+			return -1
+		}
+		return r
+	}, str)
+}
+
+// wordCount returns the number of whitespace-separated words in a string as an int (e.g., "  a  b c " -> 3).
+// Non-string input returns nil.
+func wordCount(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	return len(strings.Fields(str))
+}
+
+// charCount returns the number of characters (runes, not bytes) in a string as an int (e.g., "café" -> 4).
+// Non-string input returns nil.
+func charCount(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	return utf8.RuneCountInString(str)
+}
+
+// reverseString reverses a string by runes so multibyte characters stay intact (e.g., "héllo" -> "olléh").
+// Non-string input is returned unchanged.
+func reverseString(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	runes := []rune(str)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// initials returns the upper-cased first character of each whitespace-separated word, e.g. "John Michael Smith" ->
+// "JMS". 'count' (int, optional; default all words) limits how many words are used, and 'separator' (string,
+// optional; default "") is placed between initials (e.g., "." gives "J.M.S"). Non-string input is returned unchanged.
+func initials(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	words := strings.Fields(str)
+	if count, hasCount := getIntParam(params, "count"); hasCount && count >= 0 && count < len(words) {
+		words = words[:count]
+	}
+	separator, _ := getStringParam(params, "separator")
+	letters := make([]string, 0, len(words))
+	for _, word := range words {
+		first, _ := utf8.DecodeRuneInString(word)
+		letters = append(letters, string(unicode.ToUpper(first)))
+	}
+	return strings.Join(letters, separator)
+}
+
+// normalizeMAC parses a MAC address in colon ("00:1a:2b:3c:4d:5e"), hyphen ("00-1A-2B-3C-4D-5E"), dotted
+// ("001a.2b3c.4d5e"), or bare ("001A2B3C4D5E") form and formats it canonically. 'separator' (string, optional) is
+// ":" (default), "-", "." (groups of four hex digits), or "" (bare); 'uppercase' (bool, optional) uses upper-case
+// hex digits. Invalid or non-string input returns nil.
+func normalizeMAC(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	hw, err := parseMACAddress(str)
+	if err != nil {
+		logging.Logf(logging.Debug, "normalizeMAC: %v", err)
+		return nil
+	}
+	separator := ":"
+	if sep, exists := getStringParam(params, "separator"); exists {
+		separator = sep
+	}
+	uppercase, _ := getBoolParam(params, "uppercase")
+	return formatMACAddress(hw, separator, uppercase)
+}
+
+// base32Encode encodes a string with standard, padded base32 (RFC 4648, e.g., "hi" -> "NBUQ===="). Non-string
+// values are formatted with %v first; nil input returns nil.
+func base32Encode(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		str = fmt.Sprintf("%v", value)
+	}
+	return base32.StdEncoding.EncodeToString([]byte(str))
+}
 
-}
\ No newline at end of file
+// base32Decode decodes standard base32 text (RFC 4648) into a string. Case, surrounding whitespace and padding
+// are ignored. Returns nil for nil, non-string, or invalid input, or if the decoded bytes are not valid UTF-8.
+func base32Decode(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	result, err := base32DecodeValue(value)
+	if err != nil {
+		logging.Logf(logging.Debug, "base32Decode: %v", err)
+		return nil
+	}
+	return result
+}
+
+// rot13 rotates ASCII letters by 13 places (e.g., "Hello" -> "Uryyb"); applying it twice restores the input.
+// This is obfuscation, not encryption. Other characters and non-string input are returned unchanged.
+func rot13(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, str)
+}
+
+// bitFlag reports whether bit 'bit' (0-indexed from the least significant bit, 0-63) is set in an integer bitmask.
+// Several rules over the same status field can explode it into boolean columns. Integer strings and whole-number
+// floats are accepted; negative values use their two's complement bits. Other input logs a warning and returns nil.
+func bitFlag(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	bit, ok := getIntParam(params, "bit")
+	if !ok || bit < 0 || bit > 63 {
+		logging.Logf(logging.Warning, "bitFlag: 'bit' must be an integer between 0 and 63, got %v", params["bit"])
+		return nil
+	}
+	if value == nil {
+		return nil
+	}
+	mask, ok := parseValueAsInt64(value)
+	if !ok {
+		logging.Logf(logging.Warning, "bitFlag: could not parse input '%v' (type %T) as an integer bitmask", value, value)
+		return nil
+	}
+	return uint64(mask)&(uint64(1)<<uint(bit)) != 0
+}
+
+// convertUnit converts a numeric value between units of the same dimension ('from' and 'to', required,
+// case-insensitive): length (mm, cm, m, km, in, ft, yd, mi, nmi), weight (mg, g, kg, t, oz, lb, st) or
+// temperature (c, f, k). Numeric strings are accepted. The result is rounded to 12 significant digits, which
+// removes floating-point noise from the unit factors (1 ft -> 12 in, not 12.000000000000002). Returns a float64,
+// or nil (with a warning) if the input is not numeric or the unit pair is unsupported.
+func convertUnit(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	fromName, _ := getStringParam(params, "from")
+	toName, _ := getStringParam(params, "to")
+	from, fromOk := unitDefinitions[strings.ToLower(fromName)]
+	to, toOk := unitDefinitions[strings.ToLower(toName)]
+	if !fromOk || !toOk || from.dimension != to.dimension {
+		logging.Logf(logging.Warning, "convertUnit: unsupported conversion from '%s' to '%s'", fromName, toName)
+		return nil
+	}
+	if value == nil {
+		return nil
+	}
+	num, ok := parseValueAsFloat64(value)
+	if !ok {
+		logging.Logf(logging.Warning, "convertUnit: could not parse input '%v' (type %T) as a number", value, value)
+		return nil
+	}
+	base := (num - from.offset) * from.factor
+	result, _ := strconv.ParseFloat(strconv.FormatFloat(base/to.factor+to.offset, 'g', 12, 64), 64)
+	return result
+}
+
+// checkDigit computes or verifies the check digit of a product or account code using 'scheme' (required):
+// "ean13", "isbn10" or "luhn". 'mode' (optional) is "append" (default), which returns the code with its check
+// digit appended, or "verify", which returns whether the code's last digit is correct. Spaces and hyphens are
+// ignored. Malformed input (wrong length or non-digits) logs a warning and returns nil.
+func checkDigit(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	result, err := checkDigitValue(value, params)
+	if err != nil {
+		logging.Logf(logging.Warning, "checkDigit: %v", err)
+		return nil
+	}
+	return result
+}
+
+// timezoneInfo returns the UTC offset of the IANA zone 'timezone' (required) at the date/time in the input value,
+// so daylight saving time is taken into account. 'output' (optional) selects "offset" (default, e.g. "-04:00"),
+// "abbreviation" (e.g. "EDT") or "seconds" (int, e.g. -14400). Input may be a time.Time or a string parsed with
+// 'inputFormat' (Go layout; default RFC3339, then "2006-01-02T15:04:05" and "2006-01-02"); values without an
+// offset are read as wall-clock time in the zone. Returns nil on failure.
+func timezoneInfo(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	tzName, _ := getStringParam(params, "timezone")
+	loc, err := time.LoadLocation(tzName)
+	if tzName == "" || err != nil {
+		logging.Logf(logging.Warning, "timezoneInfo: missing or invalid 'timezone' parameter '%s'", tzName)
+		return nil
+	}
+	if value == nil {
+		return nil
+	}
+
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		layouts := []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+		if inputFormat, _ := getStringParam(params, "inputFormat"); inputFormat != "" {
+			layouts = []string{inputFormat}
+		}
+		parsed := false
+		for _, layout := range layouts {
+			if t, err = time.ParseInLocation(layout, strings.TrimSpace(v), loc); err == nil {
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			logging.Logf(logging.Warning, "timezoneInfo: failed to parse '%s' as a date/time", v)
+			return nil
+		}
+	default:
+		logging.Logf(logging.Warning, "timezoneInfo: input value is not a string or time.Time (type %T)", value)
+		return nil
+	}
+
+	abbreviation, offset := t.In(loc).Zone()
+	output, _ := getStringParam(params, "output")
+	switch strings.ToLower(output) {
+	case "", "offset":
+		return t.In(loc).Format("-07:00")
+	case "abbreviation":
+		return abbreviation
+	case "seconds":
+		return offset
+	default:
+		logging.Logf(logging.Warning, "timezoneInfo: invalid 'output' parameter '%s'", output)
+		return nil
+	}
+}
+
+// renderTemplate renders the Go text/template in the 'template' param against the record, producing a string
+// (e.g., "{{.first}} {{.last}} <{{.email}}>"). Fields are referenced as {{.field}}, or {{index . "field name"}} for
+// names that are not identifiers. Missing and nil fields render as empty text. Parse or execution errors log a
+// warning and return nil. Parsed templates are cached.
+func renderTemplate(_ interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	text, ok := getStringParam(params, "template")
+	if !ok || text == "" {
+		logging.Logf(logging.Warning, "template: missing or empty 'template' string parameter.")
+		return nil
+	}
+	tmpl, err := loadTemplate(text)
+	if err != nil {
+		logging.Logf(logging.Warning, "template: %v", err)
+		return nil
+	}
+	data := record
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		logging.Logf(logging.Warning, "template: failed to render: %v", err)
+		return nil
+	}
+	return out.String()
+}
+
+// dynamicField treats the input value as a field name and returns that field's value from the record
+// (e.g., with {"metric": "temp", "temp": 21.5}, the value "temp" yields 21.5). Useful for pivoted data.
+// Returns nil if the named field is absent; non-string input logs a warning and returns nil.
+func dynamicField(value interface{}, record map[string]interface{}, _ map[string]interface{}) interface{} {
+	fieldName, ok := value.(string)
+	if !ok {
+		if value != nil {
+			logging.Logf(logging.Warning, "dynamicField: input value is not a string field name (type %T)", value)
+		}
+		return nil
+	}
+	fieldValue, exists := record[fieldName]
+	if !exists {
+		logging.Logf(logging.Debug, "dynamicField: field '%s' not found in record", fieldName)
+		return nil
+	}
+	return fieldValue
+}
+
+// flattenMap flattens a nested map/slice value into dotted keys (e.g., {"address": {"city": "Oslo"}} ->
+// "address.city") that the processor merges into the output record; the rule's target is not written.
+// Slice elements use their index as the key ("tags.0"). 'prefix' (optional) is prepended to every key and
+// 'separator' (optional, default ".") joins the key parts. Nil input returns nil; other non-map/slice input
+// logs a warning and returns nil.
+func flattenMap(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	separator, ok := getStringParam(params, "separator")
+	if !ok || separator == "" {
+		separator = "."
+	}
+	prefix, _ := getStringParam(params, "prefix")
+	switch value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}, []map[string]interface{}:
+	default:
+		logging.Logf(logging.Warning, "flattenMap: input value is not a map or list (type %T); returning nil", value)
+		return nil
+	}
+	fields := make(MergeFields)
+	flattenInto(fields, prefix, separator, value)
+	return fields
+}
+
+// ordinal formats an integer with its English ordinal suffix, e.g. 1 -> "1st", 22 -> "22nd", 113 -> "113th"
+// (11, 12, and 13 always take "th"). Input is parsed like toInt, so "3" and 3.0 work; non-integer input returns nil.
+func ordinal(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	n, ok := parseValueAsInt64(value)
+	if !ok {
+		return nil
+	}
+	abs := n % 100
+	if abs < 0 {
+		abs = -abs
+	}
+	suffix := "th"
+	if abs < 11 || abs > 13 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.FormatInt(n, 10) + suffix
+}
+
+// numberToWords spells an integer in English words, e.g. 1234 -> "one thousand two hundred thirty-four" and
+// -7 -> "minus seven". Input is parsed like toInt; non-integer input returns nil. With 'currency' (bool, optional)
+// true, the value is rounded to cents and spelled check-style, e.g. 12.5 -> "twelve dollars and fifty cents";
+// amounts of a quadrillion or more return nil in this mode.
+func numberToWords(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if currency, _ := getBoolParam(params, "currency"); currency {
+		amount, ok := parseValueAsFloat64(value)
+		if !ok || math.IsNaN(amount) || math.Abs(amount) >= 1e15 {
+			logging.Logf(logging.Debug, "numberToWords: cannot spell '%v' as a currency amount", value)
+			return nil
+		}
+		totalCents := uint64(math.Round(math.Abs(amount) * 100))
+		dollars, cents := totalCents/100, totalCents%100
+		words := fmt.Sprintf("%s %s and %s %s", spellInteger(dollars), pluralUnit(dollars, "dollar"), spellInteger(cents), pluralUnit(cents, "cent"))
+		if amount < 0 && totalCents > 0 {
+			words = "minus " + words
+		}
+		return words
+	}
+	n, ok := parseValueAsInt64(value)
+	if !ok {
+		return nil
+	}
+	if n < 0 {
+		return "minus " + spellInteger(uint64(-(n+1))+1) // Avoids overflow for math.MinInt64
+	}
+	return spellInteger(uint64(n))
+}
+
+// numberFormat renders a number (or numeric string) with fixed decimals and grouped thousands, e.g. 1234567.5 ->
+// "1,234,567.50". 'decimals' (int, optional; default 2) sets the number of decimals (rounded half away from zero),
+// 'thousandsSep' (string, optional; default ",", "" disables grouping) separates digit groups, and 'decimalSep'
+// (string, optional; default ".") separates the fraction, e.g. "." and "," for European "1.234.567,50".
+// Non-numeric input is returned unchanged.
+func numberFormat(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	num, ok := parseValueAsFloat64(value)
+	if !ok || math.IsNaN(num) || math.IsInf(num, 0) {
+		logging.Logf(logging.Debug, "numberFormat: input '%v' (type %T) is not numeric; returning original value", value, value)
+		return value
+	}
+	decimals := 2
+	if d, exists := getIntParam(params, "decimals"); exists && d >= 0 {
+		decimals = d
+	}
+	thousandsSep, decimalSep := ",", "."
+	if sep, exists := getStringParam(params, "thousandsSep"); exists {
+		thousandsSep = sep
+	}
+	if sep, exists := getStringParam(params, "decimalSep"); exists && sep != "" {
+		decimalSep = sep
+	}
+
+	formatted := roundDecimalString(strconv.FormatFloat(math.Abs(num), 'f', -1, 64), decimals)
+	intPart, fracPart, hasFrac := strings.Cut(formatted, ".")
+	var sb strings.Builder
+	if num < 0 && strings.Trim(formatted, "0.") != "" { // No sign when the value rounds to zero
+		sb.WriteByte('-')
+	}
+	sb.WriteString(groupThousands(intPart, thousandsSep))
+	if hasFrac {
+		sb.WriteString(decimalSep)
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// constantValue ignores the input and returns the 'value' param (any type, required). String values have
+// environment variables ($VAR, ${VAR}, %VAR%) expanded, e.g. "batch-${BATCH_ID}"; unset variables expand to "".
+func constantValue(_ interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	val, exists := params["value"]
+	if !exists {
+		logging.Logf(logging.Warning, "constant: missing 'value' parameter; returning nil")
+		return nil
+	}
+	if str, isStr := val.(string); isStr {
+		return util.ExpandEnvUniversal(str)
+	}
+	return val
+}
+
+// envValue ignores the input and returns the environment variable named by 'name' (string, required). An unset
+// variable returns 'default' (any type, optional) if given, otherwise nil; a variable set to "" returns "".
+func envValue(_ interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	name, _ := getStringParam(params, "name")
+	if name == "" {
+		logging.Logf(logging.Warning, "env: missing or empty 'name' parameter; returning nil")
+		return nil
+	}
+	if val, ok := os.LookupEnv(name); ok {
+		return val
+	}
+	logging.Logf(logging.Debug, "env: environment variable '%s' is not set", name)
+	return params["default"]
+}
+
+// isWeekend reports whether the date in the input value falls on a Saturday or Sunday. Input may be a time.Time or a
+// string parsed with 'inputFormat' (optional; default RFC3339 plus the dateConvert fallbacks). 'timezone' (optional,
+// IANA name) converts the time before the day is taken, so late-evening UTC timestamps land on the local day.
+// Returns nil for nil or non-date input.
+func isWeekend(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	t, ok := weekdayDate(value, params, "isWeekend")
+	if !ok {
+		return nil
+	}
+	day := t.Weekday()
+	return day == time.Saturday || day == time.Sunday
+}
+
+// weekdayName returns the name of the day of the week of the input date in 'locale' (optional; "en" (default),
+// "de", "es", "fr", "it", "nl", or "pt"; region suffixes such as "en-US" or "pt_BR" are accepted). Input handling,
+// 'inputFormat', and 'timezone' are as for isWeekend. Returns nil for nil or non-date input or an unknown locale.
+func weekdayName(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	locale, _ := getStringParam(params, "locale")
+	names, ok := weekdayNamesForLocale(locale)
+	if !ok {
+		logging.Logf(logging.Warning, "weekdayName: unsupported 'locale' parameter '%s'", locale)
+		return nil
+	}
+	t, ok := weekdayDate(value, params, "weekdayName")
+	if !ok {
+		return nil
+	}
+	return names[t.Weekday()]
+}
+
+// --- Strict Transformation Variants (Return error on failure) ---
+
+// mustToInt ensures conversion to int64, returns error on failure. Accepts the toInt separator params.
+func mustToInt(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if i, ok := parseValueAsInt64(normalizeNumberSeparators(value, params)); ok {
+		return i
+	}
+	return fmt.Errorf("mustToInt: conversion failed for input '%v' (type %T)", value, value)
+}
+
+// mustToFloat ensures conversion to float64, returns error on failure. Accepts the toFloat separator params.
+func mustToFloat(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if f, ok := parseValueAsFloat64(normalizeNumberSeparators(value, params)); ok {
+		return f
+	}
+	return fmt.Errorf("mustToFloat: conversion failed for input '%v' (type %T)", value, value)
+}
+
+// mustToBool ensures conversion to bool, returns error on failure or ambiguity.
+func mustToBool(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	if value == nil {
+		return fmt.Errorf("mustToBool: input is nil")
+	}
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		lower := strings.ToLower(strings.TrimSpace(v))
+		switch lower {
+		case "true", "1", "yes", "t", "y":
+			return true
+		case "false", "0", "no", "f", "n":
+			return false
+		default:
+			return fmt.Errorf("mustToBool: unrecognized or ambiguous string value '%s'", v)
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return rv.Int() != 0
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return rv.Uint() != 0
+		default:
+			return fmt.Errorf("mustToBool: internal error handling numeric type %T", value)
+		}
+	case float32, float64:
+		numVal, _ := parseValueAsFloat64(v)
+		return numVal != 0.0
+	default:
+		return fmt.Errorf("mustToBool: conversion received unsupported type '%T'", value)
+	}
+}
+
+// mustEpochToDate ensures conversion, returns error on failure.
+func mustEpochToDate(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	var epoch int64
+	parsed := false
+
+	if fVal, ok := parseValueAsFloat64(value); ok {
+		epoch = int64(math.Trunc(fVal))
+		parsed = true
+	}
+	if !parsed {
+		if iVal, ok := parseValueAsInt64(value); ok {
+			epoch = iVal
+			parsed = true
+		}
+	}
+
+	if !parsed {
+		return fmt.Errorf("mustEpochToDate: could not parse input '%v' (type %T) as epoch seconds", value, value)
+	}
+
+	t := time.Unix(epoch, 0).UTC()
+	return t.Format("2006-01-02")
+}
+
+// mustDateConvert ensures date string conversion, returns error on failure.
+func mustDateConvert(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, isString := value.(string)
+	tVal, isTime := value.(time.Time)
+
+	if !isString && !isTime {
+		return fmt.Errorf("mustDateConvert: input value is not a string or time.Time (type %T)", value)
+	}
+	outputFormat, _ := getStringParam(params, "outputFormat")
+	if outputFormat == "" {
+		outputFormat = time.RFC3339
+	}
+	if isTime {
+		return tVal.Format(outputFormat)
+	}
+
+	inputFormat, _ := getStringParam(params, "inputFormat")
+	originalInputFormat := inputFormat
+	if inputFormat == "" {
+		inputFormat = time.RFC3339
+	}
+
+	var t time.Time
+	var err error
+
+	t, err = time.Parse(inputFormat, strVal)
+	if err != nil && originalInputFormat == "" {
+		parsed := false
+		var tLoop time.Time
+		var errLoop error
+		for _, fbFormat := range dateFallbackFormats {
+			tLoop, errLoop = time.Parse(fbFormat, strVal)
+			if errLoop == nil {
+				t = tLoop
+				err = nil
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return fmt.Errorf("mustDateConvert: failed to parse '%s' with default format or common fallbacks", strVal)
+		}
+	} else if err != nil {
+		return fmt.Errorf("mustDateConvert: failed to parse '%s' with specified format '%s': %w", strVal, inputFormat, err)
+	}
+	return t.Format(outputFormat)
+}
+
+// mustToBoolCustom ensures conversion to bool using custom vocabularies, returns error if the value matches neither set.
+func mustToBoolCustom(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	result, err := parseCustomBool(value, params)
+	if err != nil {
+		return fmt.Errorf("mustToBoolCustom: %v", err)
+	}
+	return result
+}
+
+// mustSumDelimited is the strict version of sumDelimited. It returns an error for nil input,
+// unsupported types, or any non-blank element that is not a number.
+func mustSumDelimited(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return fmt.Errorf("mustSumDelimited: input is nil")
+	}
+	sum, invalid, err := sumDelimitedValues(value, params)
+	if err != nil {
+		return fmt.Errorf("mustSumDelimited: %v", err)
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("mustSumDelimited: non-numeric elements %q in '%v'", invalid, value)
+	}
+	return sum
+}
+
+// mustNormalizeTimestamp is the strict version of normalizeTimestamp. Returns an error on failure.
+func mustNormalizeTimestamp(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	result, err := normalizeTimestampValue(value, params)
+	if err != nil {
+		return fmt.Errorf("mustNormalizeTimestamp: %w", err)
+	}
+	return result
+}
+
+// mustCheckDigit is the strict version of checkDigit. Returns an error for malformed input.
+// In "verify" mode an incorrect check digit still returns false rather than an error.
+func mustCheckDigit(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	result, err := checkDigitValue(value, params)
+	if err != nil {
+		return fmt.Errorf("mustCheckDigit: %w", err)
+	}
+	return result
+}
+
+// mustBase32Decode is the strict version of base32Decode. Returns an error for nil, non-string, or invalid input.
+func mustBase32Decode(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	result, err := base32DecodeValue(value)
+	if err != nil {
+		return fmt.Errorf("mustBase32Decode: %w", err)
+	}
+	return result
+}
+
+// mustNormalizePercent is the strict version of normalizePercent. Returns an error for nil or unparseable input,
+// or for an out-of-range value when 'clamp' is not set.
+func mustNormalizePercent(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	if value == nil {
+		return fmt.Errorf("mustNormalizePercent: input is nil")
+	}
+	result, err := normalizePercentValue(value, params)
+	if err != nil {
+		return fmt.Errorf("mustNormalizePercent: %w", err)
+	}
+	return result
+}
+
+// mustJsonParse is the strict version of jsonParse. Returns an error for nil, non-string, or invalid JSON input.
+func mustJsonParse(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	result, err := jsonParseValue(value)
+	if err != nil {
+		return fmt.Errorf("mustJsonParse: %w", err)
+	}
+	return result
+}
+
+// --- Validation Function Implementations (Return error on failure) ---
+
+// validateRequired checks if a value is present (non-nil and non-empty/whitespace string).
+func validateRequired(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	if value == nil {
+		return fmt.Errorf("required value is missing (nil)")
+	}
+	if strVal, ok := value.(string); ok {
+		if strings.TrimSpace(strVal) == "" {
+			return fmt.Errorf("required string value is empty or whitespace")
+		}
+	}
+	return value
+}
+
+// validateRegex checks if a string value matches a regex pattern.
+func validateRegex(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, ok := value.(string)
+	if !ok {
+		// Allow non-strings to pass if not required implicitly
+		// Return the original value if it's not a string
+		return value
+		// If non-strings should fail, use:
+		// return fmt.Errorf("input value type %T is not a string, cannot validate with regex", value)
+	}
+	pattern, ok := getStringParam(params, "pattern")
+	if !ok || pattern == "" {
+		return fmt.Errorf("missing or empty 'pattern' string parameter for validateRegex")
+	}
+
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regex pattern '%s': %w", pattern, err)
+	}
+
+	if !re.MatchString(strVal) {
+		return fmt.Errorf("value %s does not match required pattern '%s'", strconv.Quote(strVal), pattern)
+	}
+
+	return value
+}
+
+// validateNumericRange checks if a numeric value falls within a specified min/max range.
+func validateNumericRange(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	numVal, ok := parseValueAsFloat64(value)
+	if !ok {
+		// Allow non-numerics to pass validation by default
+		// Return the original value if it's not a valid number
+		return value
+		// If non-numerics should fail, use:
+		// return fmt.Errorf("input value '%v' (type %T) is not a valid number for range check", value, value)
+	}
+
+	_, minKeyExists := params["min"]
+	_, maxKeyExists := params["max"]
+
+	if !minKeyExists && !maxKeyExists {
+		return fmt.Errorf("requires at least 'min' or 'max' parameter for validateNumericRange")
+	}
+
+	var minVal float64
+	var minOK bool
+	if minKeyExists {
+		minVal, minOK = parseParamAsNumber(params["min"])
+		if !minOK {
+			return fmt.Errorf("invalid 'min' parameter: '%v' is not a valid number", params["min"])
+		}
+	}
+
+	var maxVal float64
+	var maxOK bool
+	if maxKeyExists {
+		maxVal, maxOK = parseParamAsNumber(params["max"])
+		if !maxOK {
+			return fmt.Errorf("invalid 'max' parameter: '%v' is not a valid number", params["max"])
+		}
+	}
+
+	if minKeyExists && minOK && numVal < minVal {
+		return fmt.Errorf("value %v is less than minimum allowed %v", numVal, minVal)
+	}
+	if maxKeyExists && maxOK && numVal > maxVal {
+		return fmt.Errorf("value %v is greater than maximum allowed %v", numVal, maxVal)
+	}
+
+	return value
+}
+
+// validateInteger checks that a numeric value has no fractional part, so 10 and 10.0 (or "10.0") pass but "10.5"
+// fails; NaN and infinities fail too. Non-numeric values pass validation unchanged, as in validateNumericRange.
+func validateInteger(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	numVal, ok := parseValueAsFloat64(value)
+	if !ok {
+		return value
+	}
+	if math.IsNaN(numVal) || math.IsInf(numVal, 0) || numVal != math.Trunc(numVal) {
+		return fmt.Errorf("value %v is not an integer", value)
+	}
+	return value
+}
+
+// validateDateRange checks that a date falls within 'min' and/or 'max' (inclusive; at least one is required).
+// Bounds are dates in RFC3339 or one of the dateConvert fallback layouts (e.g., "1900-01-01"), or "now" / "today"
+// (the current time / start of the current UTC day), so max: now rejects future dates. The value may be a time.Time
+// or a string parsed with 'inputFormat' (optional; default RFC3339 plus the dateConvert fallbacks). Values that are
+// not dates pass through unchanged; otherwise the original value is returned when it is in range.
+func validateDateRange(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		inputFormat, _ := getStringParam(params, "inputFormat")
+		parsed, ok := parseDateWithFallbacks(v, inputFormat)
+		if !ok {
+			return value
+		}
+		t = parsed
+	default:
+		return value
+	}
+
+	minRaw, hasMin := params["min"]
+	maxRaw, hasMax := params["max"]
+	if !hasMin && !hasMax {
+		return fmt.Errorf("requires at least 'min' or 'max' parameter for validateDateRange")
+	}
+	if hasMin {
+		minDate, ok := parseDateBound(minRaw)
+		if !ok {
+			return fmt.Errorf("invalid 'min' parameter: '%v' is not a valid date", minRaw)
+		}
+		if t.Before(minDate) {
+			return fmt.Errorf("date %v is before minimum allowed %v", value, minRaw)
+		}
+	}
+	if hasMax {
+		maxDate, ok := parseDateBound(maxRaw)
+		if !ok {
+			return fmt.Errorf("invalid 'max' parameter: '%v' is not a valid date", maxRaw)
+		}
+		if t.After(maxDate) {
+			return fmt.Errorf("date %v is after maximum allowed %v", value, maxRaw)
+		}
+	}
+	return value
+}
+
+// validateAllowedValues checks if a value is present in a predefined list.
+func validateAllowedValues(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	allowedValuesRaw, ok := params["values"]
+	if !ok {
+		return fmt.Errorf("missing 'values' array parameter for validateAllowedValues")
+	}
+	allowedValuesSlice, ok := allowedValuesRaw.([]interface{})
+	if !ok || len(allowedValuesSlice) == 0 {
+		return fmt.Errorf("'values' parameter is not a non-empty array for validateAllowedValues")
+	}
+
+	found := false
+	for _, allowed := range allowedValuesSlice {
+		cmp, err := CompareValues(value, allowed)
+		if err == nil && cmp == 0 {
+			found = true
+			break
+		} else if err != nil {
+			logging.Logf(logging.Debug, "validateAllowedValues: Could not compare input '%v' (%T) with allowed value '%v' (%T): %v", value, value, allowed, allowed, err)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("value '%v' is not in the list of allowed values", value)
+	}
+
+	return value
+}
+
+// validateInFile checks that the value exists in the 'keyField' column of a reference file ('file', required;
+// environment variables are expanded). CSV files (with a header row) and JSON/YAML arrays of objects are supported,
+// chosen by file extension. Values are compared by their string form. The file is loaded once and cached.
+// Nil input passes through. Returns an error if the value is absent or the file cannot be loaded.
+func validateInFile(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	filePath, _ := getStringParam(params, "file")
+	keyField, _ := getStringParam(params, "keyField")
+	if filePath == "" || keyField == "" {
+		return fmt.Errorf("missing or empty 'file' or 'keyField' string parameter for validateInFile")
+	}
+	if value == nil {
+		return nil
+	}
+	keys, err := loadReferenceKeys(util.ExpandEnvUniversal(filePath), keyField)
+	if err != nil {
+		return err
+	}
+	if _, found := keys[fmt.Sprintf("%v", value)]; !found {
+		return fmt.Errorf("value '%v' not found in column '%s' of reference file '%s'", value, keyField, filePath)
+	}
+	return value
+}
+
+// validateMonotonic checks that the record field 'field' never decreases relative to the previous record with the
+// same 'keyField' value (e.g., event timestamps per device). Values are compared with CompareValues, so numbers
+// compare numerically and strings (such as ISO 8601 timestamps) lexically. 'strict' (bool, optional) also rejects
+// equal consecutive values. This validation is STATEFUL: records must be processed one at a time, in input order.
+// Only passing values advance the history, so a rejected value never becomes the baseline for the next record; nil
+// values pass through without advancing it. The mapping's value is returned unchanged.
+func validateMonotonic(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	field, _ := getStringParam(params, "field")
+	keyField, _ := getStringParam(params, "keyField")
+	if field == "" || keyField == "" {
+		return fmt.Errorf("missing or empty 'field' or 'keyField' string parameter for validateMonotonic")
+	}
+	current := record[field]
+	if current == nil {
+		return value
+	}
+	keyValue := ValueToStringForHash(record[keyField])
+	stateKey := "\x00validateMonotonic\x00" + field + "\x00" + keyField + "\x00" + keyValue
+	previous, hasPrevious := peekLagState(stateKey, record)
+	if !hasPrevious {
+		advanceLagState(stateKey, record, current)
+		return value
+	}
+	cmp, err := CompareValues(current, previous)
+	if err != nil {
+		return fmt.Errorf("cannot compare '%s' value '%v' with previous value '%v' for %s '%s': %w", field, current, previous, keyField, keyValue, err)
+	}
+	if strict, _ := params["strict"].(bool); strict && cmp == 0 {
+		return fmt.Errorf("'%s' value '%v' does not increase from previous value '%v' for %s '%s'", field, current, previous, keyField, keyValue)
+	}
+	if cmp < 0 {
+		return fmt.Errorf("'%s' value '%v' decreased from previous value '%v' for %s '%s'", field, current, previous, keyField, keyValue)
+	}
+	advanceLagState(stateKey, record, current)
+	return value
+}
+
+// validatePrintable checks that a string contains no control characters (e.g., NUL, ESC, tab or newline).
+// If 'asciiOnly' (bool, optional) is true, any character outside printable ASCII (0x20-0x7E) is also rejected.
+// Non-string values pass validation.
+func validatePrintable(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	strVal, ok := value.(string)
+	if !ok {
+		return value
+	}
+	asciiOnly, _ := getBoolParam(params, "asciiOnly")
+	for i, r := range strVal {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("value %s contains control character %U at byte %d", strconv.Quote(strVal), r, i)
+		}
+		if asciiOnly && (r < 0x20 || r > 0x7E) {
+			return fmt.Errorf("value %s contains non-ASCII character %U at byte %d", strconv.Quote(strVal), r, i)
+		}
+	}
+	return value
+}
+
+// validateMAC checks that a string is a MAC address in any form accepted by normalizeMAC (colon, hyphen, dotted,
+// or bare hex). Returns the original value if valid; non-string values pass through.
+func validateMAC(value interface{}, _ map[string]interface{}, _ map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if _, err := parseMACAddress(str); err != nil {
+		return fmt.Errorf("value %s is not a valid MAC address", strconv.Quote(str))
+	}
+	return value
+}
+
+// validateHash recomputes the hash of 'fields' with 'algorithm' exactly as the hash transform does and checks it
+// against the hex digest stored in the record field named by 'expectedField' (compared case-insensitively, ignoring
+// surrounding whitespace). Returns an error on mismatch or when the expected field is missing or empty; otherwise
+// returns the original value.
+func validateHash(value interface{}, record map[string]interface{}, params map[string]interface{}) interface{} {
+	expectedField, ok := getStringParam(params, "expectedField")
+	if !ok || expectedField == "" {
+		return fmt.Errorf("missing or empty 'expectedField' string parameter for validateHash")
+	}
+	computed := hashTransform(nil, record, params)
+	if err, isErr := computed.(error); isErr {
+		return err
+	}
+	expectedRaw, found := record[expectedField]
+	expected := ""
+	if found && expectedRaw != nil {
+		expected = strings.TrimSpace(fmt.Sprintf("%v", expectedRaw))
+	}
+	if expected == "" {
+		return fmt.Errorf("expected hash field '%s' is missing or empty", expectedField)
+	}
+	if !strings.EqualFold(expected, computed.(string)) {
+		return fmt.Errorf("hash mismatch: field '%s' has %s, computed %s", expectedField, expected, computed)
+	}
+	return value
+}
+
+// validateCharset checks that every character of a string belongs to 'charset' (string, required): a named ASCII
+// class ("alnum", "alpha", "digit", "upper", "lower", "hex"; case-insensitive) or a custom set of characters with
+// optional ranges, e.g. "A-Z0-9_" (a '-' at the start or end is literal). Returns the original value if valid;
+// non-string values pass through.
+func validateCharset(value interface{}, _ map[string]interface{}, params map[string]interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	spec, _ := getStringParam(params, "charset")
+	allowed, err := parseCharset(spec)
+	if err != nil {
+		return fmt.Errorf("validateCharset: %w", err)
+	}
+	for i, r := range str {
+		if !allowed(r) {
+			return fmt.Errorf("value %s contains disallowed character %q at byte %d (allowed charset: %s)", strconv.Quote(str), r, i, spec)
+		}
+	}
+	return value
+}
+
+// --- Helper Functions ---
+
+// getStringParam retrieves a string value from the parameters map.
+func getStringParam(params map[string]interface{}, key string) (string, bool) {
+	val, ok := params[key]
+	if !ok {
+		return "", false
+	}
+	strVal, ok := val.(string)
+	return strVal, ok
+}
+
+// getIntParam retrieves an integer value from the parameters map.
+func getIntParam(params map[string]interface{}, key string) (int, bool) {
+	val, ok := params[key]
+	if !ok {
+		return 0, false
+	}
+	return parseParamAsInt(val)
+}
+
+// parseValueAsInt64 attempts to parse various input types into int64.
+func parseValueAsInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		if uint64(v) > uint64(math.MaxInt64) {
+			return 0, false
+		}
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		if v > uint64(math.MaxInt64) {
+			return 0, false
+		}
+		return int64(v), true
+	case float32:
+		if float32(int64(v)) == v && v >= float32(math.MinInt64) && v <= float32(math.MaxInt64) {
+			return int64(v), true
+		}
+		return 0, false
+	case float64:
+		if float64(int64(v)) == v && v >= float64(math.MinInt64) && v <= float64(math.MaxInt64) {
+			return int64(v), true
+		}
+		return 0, false
+	case string:
+		cleanV := strings.TrimSpace(v)
+		if cleanV == "" {
+			return 0, false
+		}
+		i, err := strconv.ParseInt(cleanV, 10, 64)
+		if err == nil {
+			return i, true
+		}
+		f, errF := strconv.ParseFloat(cleanV, 64)
+		if errF == nil && f == math.Trunc(f) && f >= float64(math.MinInt64) && f <= float64(math.MaxInt64) {
+			if float64(int64(f)) == f {
+				return int64(f), true
+			}
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+// parseValueAsFloat64 attempts to parse various input types into float64.
+func parseValueAsFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64:
+		rv := reflect.ValueOf(v)
+		return float64(rv.Int()), true
+	case uint, uint8, uint16, uint32, uint64:
+		rv := reflect.ValueOf(v)
+		return float64(rv.Uint()), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		cleanV := strings.TrimSpace(v)
+		if cleanV == "" {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(cleanV, 64)
+		if err == nil {
+			return f, true
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+// normalizeNumberSeparators rewrites a locale-style numeric string, e.g. "1.234,56" with thousandsSep "." and
+// decimalSep ",", to the "1234.56" form parseValueAsFloat64 and parseValueAsInt64 accept. It only applies when
+// the 'thousandsSep' or 'decimalSep' param is present; otherwise, and for non-string values, the value is
+// returned unchanged. decimalSep defaults to "." and thousandsSep to none. Thousands separators must split the
+// integer part into groups of three digits, and a "." that is not one of the separators is rejected, so
+// "1,234.56" read with European separators fails instead of becoming 1.23456. Rejected strings are returned as
+// an empty string, which the parsers treat as invalid.
+func normalizeNumberSeparators(value interface{}, params map[string]interface{}) interface{} {
+	strVal, isString := value.(string)
+	_, hasThousands := params["thousandsSep"]
+	_, hasDecimal := params["decimalSep"]
+	if !isString || (!hasThousands && !hasDecimal) {
+		return value
+	}
+	thousandsSep, _ := getStringParam(params, "thousandsSep")
+	decimalSep, _ := getStringParam(params, "decimalSep")
+	if decimalSep == "" {
+		decimalSep = "."
+	}
+	if thousandsSep == decimalSep {
+		return ""
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(strings.TrimSpace(strVal), decimalSep)
+	if hasFrac && (strings.Contains(fracPart, decimalSep) || (thousandsSep != "" && strings.Contains(fracPart, thousandsSep))) {
+		return ""
+	}
+	if thousandsSep != "" && strings.Contains(intPart, thousandsSep) {
+		groups := strings.Split(intPart, thousandsSep)
+		lead := strings.TrimLeft(groups[0], "+-")
+		if len(lead) == 0 || len(lead) > 3 {
+			return ""
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return ""
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if decimalSep != "." && (strings.Contains(intPart, ".") || strings.Contains(fracPart, ".")) {
+		return ""
+	}
+	if hasFrac {
+		return intPart + "." + fracPart
+	}
+	return intPart
+}
+
+// ParseNumber converts numeric types and numeric strings to float64, reporting whether it succeeded.
+func ParseNumber(value interface{}) (float64, bool) {
+	return parseValueAsFloat64(value)
+}
+
+// parseParamAsNumber is a convenience wrapper for validating numeric parameters.
+func parseParamAsNumber(v interface{}) (float64, bool) {
+	return parseValueAsFloat64(v)
+}
+
+// parseParamAsInt is a convenience wrapper for validating integer parameters.
+func parseParamAsInt(v interface{}) (int, bool) {
+	i64, ok := parseValueAsInt64(v)
+	if !ok {
+		return 0, false
+	}
+	const maxInt = int(^uint(0) >> 1)
+	const minInt = -maxInt - 1
+	if i64 >= int64(minInt) && i64 <= int64(maxInt) {
+		return int(i64), true
+	}
+	return 0, false
+}
+
+// CompareValues attempts to compare two values of potentially different types.
+func CompareValues(a, b interface{}) (int, error) {
+	if a == nil && b == nil {
+		return 0, nil
+	}
+	if a == nil {
+		return -1, nil
+	}
+	if b == nil {
+		return 1, nil
+	}
+
+	aFloat, aIsNum := parseValueAsFloat64(a)
+	bFloat, bIsNum := parseValueAsFloat64(b)
+
+	if aIsNum && bIsNum {
+		if aFloat < bFloat {
+			return -1, nil
+		}
+		if aFloat > bFloat {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	// Only compare if types are compatible beyond numeric
+	typeA := reflect.TypeOf(a)
+	typeB := reflect.TypeOf(b)
+
+	if typeA != typeB {
+		return 0, fmt.Errorf("type mismatch: cannot compare %T with %T", a, b)
+	}
+
+	// Handle specific comparable types
+	switch a.(type) {
+	case string:
+		return strings.Compare(a.(string), b.(string)), nil
+	case time.Time:
+		tA, _ := a.(time.Time)
+		tB, _ := b.(time.Time)
+		if tA.Before(tB) {
+			return -1, nil
+		}
+		if tA.After(tB) {
+			return 1, nil
+		}
+		return 0, nil
+	case bool:
+		bA, _ := a.(bool)
+		bB, _ := b.(bool)
+		if bA == bB {
+			return 0, nil
+		}
+		if bA { // true > false
+			return 1, nil
+		}
+		return -1, nil // false < true
+	}
+
+	// Use DeepEqual for non-ordered types like maps/slices ONLY for equality check
+	if reflect.DeepEqual(a, b) {
+		return 0, nil
+	}
+
+	// Cannot determine order for other non-primitive, non-time types
+	return 0, fmt.Errorf("unsupported comparison ordering for type %T", a)
+
+}
+
+// CompareTimes compares two date/time values chronologically. Strings are parsed with RFC3339 and the dateConvert
+// fallback layouts; time.Time values are used as-is. Nil sorts before any time, as in CompareValues.
+func CompareTimes(a, b interface{}) (int, error) {
+	if a == nil || b == nil {
+		return CompareValues(a, b)
+	}
+	tA, err := parseTimeValue(a)
+	if err != nil {
+		return 0, err
+	}
+	tB, err := parseTimeValue(b)
+	if err != nil {
+		return 0, err
+	}
+	return tA.Compare(tB), nil
+}
+
+// parseTimeValue converts a time.Time or date string to a time.Time for CompareTimes.
+func parseTimeValue(v interface{}) (time.Time, error) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, nil
+	case string:
+		if t, ok := parseDateWithFallbacks(val, ""); ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("cannot parse '%s' as a date/time", val)
+	}
+	return time.Time{}, fmt.Errorf("cannot compare %T as a date/time", v)
+}
+
+// getBoolParam retrieves a boolean value from the parameters map.
+func getBoolParam(params map[string]interface{}, key string) (bool, bool) {
+	val, ok := params[key]
+	if !ok {
+		return false, false
+	}
+	boolVal, ok := val.(bool)
+	return boolVal, ok
+}
+
+// parseCustomBool matches the string form of a value against the 'truthy' and 'falsy' parameter arrays.
+// Surrounding whitespace is ignored; case is ignored when the 'caseInsensitive' parameter is true.
+func parseCustomBool(value interface{}, params map[string]interface{}) (bool, error) {
+	if value == nil {
+		return false, fmt.Errorf("input is nil")
+	}
+	if b, ok := value.(bool); ok {
+		return b, nil
+	}
+	truthy, truthyOK := params["truthy"].([]interface{})
+	falsy, falsyOK := params["falsy"].([]interface{})
+	if !truthyOK || !falsyOK || len(truthy) == 0 || len(falsy) == 0 {
+		return false, fmt.Errorf("requires non-empty 'truthy' and 'falsy' array parameters")
+	}
+	caseInsensitive, _ := getBoolParam(params, "caseInsensitive")
+
+	normalize := func(v interface{}) string {
+		s := strings.TrimSpace(fmt.Sprintf("%v", v))
+		if caseInsensitive {
+			s = strings.ToLower(s)
+		}
+		return s
+	}
+
+	needle := normalize(value)
+	for _, candidate := range truthy {
+		if normalize(candidate) == needle {
+			return true, nil
+		}
+	}
+	for _, candidate := range falsy {
+		if normalize(candidate) == needle {
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("value '%v' is not in the truthy or falsy sets", value)
+}
+
+// levenshteinDistance returns the number of single-rune insertions, deletions, or substitutions
+// required to turn a into b.
+func levenshteinDistance(a, b []rune) int {
+	if len(a) == 0 {
+		return len(b)
+	}
+	if len(b) == 0 {
+		return len(a)
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// stringSimilarity returns a Levenshtein-based similarity score between 0 (completely different) and 1 (identical).
+func stringSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	maxLen := max(len(ra), len(rb))
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshteinDistance(ra, rb))/float64(maxLen)
+}
+
+// getListSeparator returns the 'separator' parameter used by the delimited-list transforms, defaulting to ",".
+func getListSeparator(params map[string]interface{}) string {
+	if separator, ok := getStringParam(params, "separator"); ok && separator != "" {
+		return separator
+	}
+	return ","
+}
+
+// sumDelimitedValues sums the numeric elements of a delimited string, returning the sum and any
+// non-blank elements that could not be parsed. Numeric input is returned as-is.
+func sumDelimitedValues(value interface{}, params map[string]interface{}) (float64, []string, error) {
+	strVal, isString := value.(string)
+	if !isString {
+		if num, ok := parseValueAsFloat64(value); ok {
+			return num, nil, nil
+		}
+		return 0, nil, fmt.Errorf("unsupported input type %T", value)
+	}
+	var sum float64
+	var invalid []string
+	for _, part := range strings.Split(strVal, getListSeparator(params)) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		num, ok := parseValueAsFloat64(part)
+		if !ok {
+			invalid = append(invalid, part)
+			continue
+		}
+		sum += num
+	}
+	return sum, invalid, nil
+}
+
+// lookupTableEntry caches the parsed contents (or load error) of a configLookup file.
+type lookupTableEntry struct {
+	table map[string]interface{}
+	err   error
+}
+
+var (
+	lookupTableMu    sync.Mutex
+	lookupTableCache = make(map[string]lookupTableEntry)
+)
+
+// loadLookupTable reads a YAML or JSON map from path, caching the result so each file is parsed only once.
+// Keys are normalized to their string form so numeric keys match stringified input values.
+func loadLookupTable(path string) (map[string]interface{}, error) {
+	lookupTableMu.Lock()
+	defer lookupTableMu.Unlock()
+	if entry, ok := lookupTableCache[path]; ok {
+		return entry.table, entry.err
+	}
+
+	entry := lookupTableEntry{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		entry.err = fmt.Errorf("failed to read lookup file '%s': %w", path, err)
+	} else {
+		var raw map[interface{}]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			entry.err = fmt.Errorf("failed to parse lookup file '%s': %w", path, err)
+		} else {
+			entry.table = make(map[string]interface{}, len(raw))
+			for k, v := range raw {
+				entry.table[fmt.Sprintf("%v", k)] = v
+			}
+		}
+	}
+	lookupTableCache[path] = entry
+	return entry.table, entry.err
+}
+
+// referenceKeysEntry caches the key set (or load error) of one validateInFile file and column.
+type referenceKeysEntry struct {
+	keys map[string]struct{}
+	err  error
+}
+
+var (
+	referenceKeysMu    sync.Mutex
+	referenceKeysCache = make(map[string]referenceKeysEntry)
+)
+
+// loadReferenceKeys returns the set of stringified values in the keyField column of a CSV, JSON, or YAML
+// reference file, caching the result per path and column. Nil values are not included.
+func loadReferenceKeys(path, keyField string) (map[string]struct{}, error) {
+	cacheKey := path + "\x00" + keyField
+	referenceKeysMu.Lock()
+	defer referenceKeysMu.Unlock()
+	if entry, ok := referenceKeysCache[cacheKey]; ok {
+		return entry.keys, entry.err
+	}
+
+	entry := referenceKeysEntry{}
+	entry.keys, entry.err = readReferenceKeys(path, keyField)
+	referenceKeysCache[cacheKey] = entry
+	return entry.keys, entry.err
+}
+
+// readReferenceKeys reads the keyField column of the reference file without caching.
+func readReferenceKeys(path, keyField string) (map[string]struct{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reference file '%s': %w", path, err)
+	}
+	keys := make(map[string]struct{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reference file '%s': %w", path, err)
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("reference file '%s' has no header row", path)
+		}
+		column := -1
+		for i, header := range rows[0] {
+			if strings.TrimSpace(header) == keyField {
+				column = i
+				break
+			}
+		}
+		if column < 0 {
+			return nil, fmt.Errorf("key field '%s' not found in header of reference file '%s'", keyField, path)
+		}
+		for _, row := range rows[1:] {
+			if column < len(row) {
+				keys[row[column]] = struct{}{}
+			}
+		}
+	case ".json", ".yaml", ".yml":
+		var records []map[string]interface{}
+		if err := yaml.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse reference file '%s' as an array of objects: %w", path, err)
+		}
+		fieldSeen := false
+		for _, record := range records {
+			if v, ok := record[keyField]; ok {
+				fieldSeen = true
+				if v != nil {
+					keys[fmt.Sprintf("%v", v)] = struct{}{}
+				}
+			}
+		}
+		if !fieldSeen && len(records) > 0 {
+			return nil, fmt.Errorf("key field '%s' not found in any record of reference file '%s'", keyField, path)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported reference file extension for '%s' (must be .csv, .json, .yaml, or .yml)", path)
+	}
+	return keys, nil
+}
+
+// unitDefinition converts a unit to its dimension's base unit (meters, kilograms, degrees Celsius):
+// base = (value - offset) * factor. Measuring temperatures from their own zero points keeps 32 F -> 0 C exact.
+type unitDefinition struct {
+	dimension string
+	factor    float64
+	offset    float64
+}
+
+// unitDefinitions is the built-in convertUnit table. Keep in sync with knownUnitDimensions in config validation.
+var unitDefinitions = map[string]unitDefinition{
+	"mm": {"length", 0.001, 0}, "cm": {"length", 0.01, 0}, "m": {"length", 1, 0}, "km": {"length", 1000, 0}, "nmi": {"length", 1852, 0},
+	"in": {"length", 0.0254, 0}, "ft": {"length", 0.3048, 0}, "yd": {"length", 0.9144, 0}, "mi": {"length", 1609.344, 0},
+	"mg": {"weight", 1e-6, 0}, "g": {"weight", 0.001, 0}, "kg": {"weight", 1, 0}, "t": {"weight", 1000, 0},
+	"oz": {"weight", 0.028349523125, 0}, "lb": {"weight", 0.45359237, 0}, "st": {"weight", 6.35029318, 0},
+	"c": {"temperature", 1, 0}, "f": {"temperature", 5.0 / 9.0, 32}, "k": {"temperature", 1, 273.15},
+}
+
+// lagEntry is the history kept by lagField (and validateMonotonic) for one field (and partition).
+type lagEntry struct {
+	record      map[string]interface{} // record that last advanced this entry
+	current     interface{}            // field value on that record
+	previous    interface{}            // field value on the record before it
+	hasPrevious bool
+}
+
+var (
+	lagStateMu sync.Mutex
+	lagStates  = make(map[string]*lagEntry)
+)
+
+// advanceLagState records value as the latest value for key and returns the value from the previous record.
+// Repeated calls for the same record (e.g., several rules sharing a key) do not advance the history again.
+func advanceLagState(key string, record map[string]interface{}, value interface{}) (interface{}, bool) {
+	lagStateMu.Lock()
+	defer lagStateMu.Unlock()
+	entry, exists := lagStates[key]
+	if !exists {
+		entry = &lagEntry{}
+		lagStates[key] = entry
+	}
+	if entry.record == nil || reflect.ValueOf(entry.record).Pointer() != reflect.ValueOf(record).Pointer() {
+		entry.previous, entry.hasPrevious = entry.current, entry.record != nil
+		entry.current, entry.record = value, record
+	}
+	return entry.previous, entry.hasPrevious
+}
+
+// peekLagState returns the value advanceLagState would return for record without changing the history.
+func peekLagState(key string, record map[string]interface{}) (interface{}, bool) {
+	lagStateMu.Lock()
+	defer lagStateMu.Unlock()
+	entry, exists := lagStates[key]
+	if !exists {
+		return nil, false
+	}
+	if entry.record != nil && reflect.ValueOf(entry.record).Pointer() == reflect.ValueOf(record).Pointer() {
+		return entry.previous, entry.hasPrevious
+	}
+	return entry.current, entry.record != nil
+}
+
+// ResetLagState clears the history kept by lagField and validateMonotonic. Call it before processing a new sequence of records.
+func ResetLagState() {
+	lagStateMu.Lock()
+	defer lagStateMu.Unlock()
+	lagStates = make(map[string]*lagEntry)
+}
+
+// timestampPrecisionLayouts maps normalizeTimestamp precisions to UTC output layouts.
+var timestampPrecisionLayouts = map[string]string{
+	"seconds": "2006-01-02T15:04:05Z",
+	"millis":  "2006-01-02T15:04:05.000Z",
+	"micros":  "2006-01-02T15:04:05.000000Z",
+	"nanos":   "2006-01-02T15:04:05.000000000Z",
+}
+
+// normalizeTimestampValue implements normalizeTimestamp and mustNormalizeTimestamp.
+func normalizeTimestampValue(value interface{}, params map[string]interface{}) (string, error) {
+	precision, _ := getStringParam(params, "precision")
+	if precision == "" {
+		precision = "seconds"
+	}
+	layout, ok := timestampPrecisionLayouts[strings.ToLower(precision)]
+	if !ok {
+		return "", fmt.Errorf("invalid 'precision' parameter '%s'", precision)
+	}
+
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		strVal := strings.TrimSpace(v)
+		inputFormat, _ := getStringParam(params, "inputFormat")
+		if inputFormat != "" {
+			parsed, err := time.Parse(inputFormat, strVal)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse '%s' with specified format '%s': %w", v, inputFormat, err)
+			}
+			t = parsed
+			break
+		}
+		parsed, ok := parseDateWithFallbacks(strVal, "")
+		if !ok {
+			return "", fmt.Errorf("failed to parse '%s' as a timestamp", v)
+		}
+		t = parsed
+	default:
+		return "", fmt.Errorf("input value is not a string or time.Time (type %T)", value)
+	}
+	return t.UTC().Format(layout), nil
+}
+
+// checkDigitValue implements checkDigit and mustCheckDigit.
+func checkDigitValue(value interface{}, params map[string]interface{}) (interface{}, error) {
+	scheme, _ := getStringParam(params, "scheme")
+	scheme = strings.ToLower(scheme)
+	mode, _ := getStringParam(params, "mode")
+	verify := strings.EqualFold(mode, "verify")
+	if mode != "" && !verify && !strings.EqualFold(mode, "append") {
+		return nil, fmt.Errorf("invalid 'mode' parameter '%s'", mode)
+	}
+
+	code := strings.NewReplacer(" ", "", "-", "").Replace(fmt.Sprintf("%v", value))
+	if value == nil || code == "" {
+		return nil, fmt.Errorf("input '%v' is empty", value)
+	}
+	payload, given := code, ""
+	if verify {
+		payload, given = code[:len(code)-1], strings.ToUpper(code[len(code)-1:])
+	}
+	for _, r := range payload {
+		if r < '0' || r > '9' {
+			return nil, fmt.Errorf("input '%v' contains non-digit characters", value)
+		}
+	}
+
+	var check string
+	switch scheme {
+	case "ean13":
+		if len(payload) != 12 {
+			return nil, fmt.Errorf("input '%v' must have %d digits for ean13", value, 12+len(given))
+		}
+		sum := 0
+		for i, r := range payload {
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(r-'0') * weight
+		}
+		check = strconv.Itoa((10 - sum%10) % 10)
+	case "isbn10":
+		if len(payload) != 9 {
+			return nil, fmt.Errorf("input '%v' must have %d characters for isbn10", value, 9+len(given))
+		}
+		sum := 0
+		for i, r := range payload {
+			sum += int(r-'0') * (10 - i)
+		}
+		digit := (11 - sum%11) % 11
+		check = strconv.Itoa(digit)
+		if digit == 10 {
+			check = "X"
+		}
+	case "luhn":
+		if payload == "" {
+			return nil, fmt.Errorf("input '%v' is too short for luhn", value)
+		}
+		sum := 0
+		for i := len(payload) - 1; i >= 0; i-- {
+			d := int(payload[i] - '0')
+			if (len(payload)-1-i)%2 == 0 { // Double every second digit, starting next to the check digit
+				d *= 2
+				if d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		check = strconv.Itoa((10 - sum%10) % 10)
+	default:
+		return nil, fmt.Errorf("invalid 'scheme' parameter '%s'", scheme)
+	}
+
+	if verify {
+		return given == check, nil
+	}
+	return payload + check, nil
+}
+
+var (
+	templateMu    sync.Mutex
+	templateCache = make(map[string]*template.Template)
+)
+
+// loadTemplate parses a template transform's text, caching the result so each template is parsed only once.
+func loadTemplate(text string) (*template.Template, error) {
+	templateMu.Lock()
+	defer templateMu.Unlock()
+	if tmpl, ok := templateCache[text]; ok {
+		return tmpl, nil
+	}
+	tmpl, err := template.New("template").Option("missingkey=zero").Funcs(template.FuncMap{"emptyIfNil": emptyIfNil}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			pipeOutputThroughEmptyIfNil(t.Tree.Root)
+		}
+	}
+	templateCache[text] = tmpl
+	return tmpl, nil
+}
+
+// emptyIfNil replaces a nil template value with "". text/template prints missing (with missingkey=zero) and nil
+// map entries as "<no value>", so every printed action is piped through it.
+func emptyIfNil(v interface{}) interface{} {
+	if v == nil {
+		return ""
+	}
+	return v
+}
+
+// pipeOutputThroughEmptyIfNil appends "| emptyIfNil" to each action under node that prints its result, so missing
+// and nil fields render as empty text without touching literal "<no value>" text in the template or record data.
+func pipeOutputThroughEmptyIfNil(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			pipeOutputThroughEmptyIfNil(child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) == 0 { // Assignments such as {{$x := .a}} print nothing
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{NodeType: parse.NodeCommand, Pos: n.Pos, Args: []parse.Node{parse.NewIdentifier("emptyIfNil").SetPos(n.Pos)}})
+		}
+	case *parse.IfNode:
+		pipeOutputThroughEmptyIfNil(n.List)
+		pipeOutputThroughEmptyIfNil(n.ElseList)
+	case *parse.RangeNode:
+		pipeOutputThroughEmptyIfNil(n.List)
+		pipeOutputThroughEmptyIfNil(n.ElseList)
+	case *parse.WithNode:
+		pipeOutputThroughEmptyIfNil(n.List)
+		pipeOutputThroughEmptyIfNil(n.ElseList)
+	}
+}
+
+// regexCacheEntry is a compiled pattern, or the compile error for an invalid one, so bad patterns are not
+// recompiled for every record either.
+type regexCacheEntry struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// regexCache maps pattern strings to *regexCacheEntry. Patterns come from the configuration, so it stays small;
+// sync.Map suits this write-once, read-many use and keeps lookups lock-free across processing workers.
+var regexCache sync.Map
+
+// compileRegex returns the compiled pattern, compiling it only on first use. Regexps are safe for concurrent use.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		entry := cached.(*regexCacheEntry)
+		return entry.re, entry.err
+	}
+	re, err := regexp.Compile(pattern)
+	cached, _ := regexCache.LoadOrStore(pattern, &regexCacheEntry{re: re, err: err})
+	entry := cached.(*regexCacheEntry)
+	return entry.re, entry.err
+}
+
+// flattenInto writes the leaves of a nested map/slice value into fields, joining key parts with separator.
+// Empty maps and slices produce no keys.
+func flattenInto(fields MergeFields, key, separator string, value interface{}) {
+	join := func(part string) string {
+		if key == "" {
+			return part
+		}
+		return key + separator + part
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenInto(fields, join(k), separator, child)
+		}
+	case map[interface{}]interface{}:
+		for k, child := range v {
+			flattenInto(fields, join(fmt.Sprintf("%v", k)), separator, child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenInto(fields, join(strconv.Itoa(i)), separator, child)
+		}
+	case []map[string]interface{}:
+		for i, child := range v {
+			flattenInto(fields, join(strconv.Itoa(i)), separator, child)
+		}
+	default:
+		fields[key] = v
+	}
+}
+
+// parseMACAddress parses a MAC address with net.ParseMAC after trimming whitespace, also accepting bare hex
+// digits without separators (e.g., "001A2B3C4D5E") for the address lengths net.ParseMAC supports.
+func parseMACAddress(s string) (net.HardwareAddr, error) {
+	s = strings.TrimSpace(s)
+	if n := len(s); n == 12 || n == 16 || n == 40 {
+		if _, err := hex.DecodeString(s); err == nil {
+			pairs := make([]string, 0, n/2)
+			for i := 0; i < n; i += 2 {
+				pairs = append(pairs, s[i:i+2])
+			}
+			s = strings.Join(pairs, ":")
+		}
+	}
+	return net.ParseMAC(s)
+}
+
+// formatMACAddress formats hw with separator between octets, or between groups of two octets when separator is ".".
+func formatMACAddress(hw net.HardwareAddr, separator string, uppercase bool) string {
+	digits := hex.EncodeToString(hw)
+	if uppercase {
+		digits = strings.ToUpper(digits)
+	}
+	groupSize := 2
+	if separator == "." {
+		groupSize = 4
+	}
+	groups := make([]string, 0, len(digits)/groupSize)
+	for i := 0; i < len(digits); i += groupSize {
+		groups = append(groups, digits[i:i+groupSize])
+	}
+	return strings.Join(groups, separator)
+}
+
+// base32DecodeValue implements base32Decode and mustBase32Decode: it decodes standard base32 text, ignoring case,
+// surrounding whitespace and padding, and requires the decoded bytes to be valid UTF-8.
+func base32DecodeValue(value interface{}) (string, error) {
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("input must be a string, got %T", value)
+	}
+	normalized := strings.TrimRight(strings.ToUpper(strings.TrimSpace(str)), "=")
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(normalized)
+	if err != nil {
+		return "", fmt.Errorf("invalid base32 input %s: %v", strconv.Quote(str), err)
+	}
+	if !utf8.Valid(decoded) {
+		return "", fmt.Errorf("decoded base32 input %s is not valid UTF-8 text", strconv.Quote(str))
+	}
+	return string(decoded), nil
+}
+
+// severityCode is one entry of the severityMap 'codes' parameter: the code as written in the config and its label.
+type severityCode struct {
+	code  interface{}
+	label interface{}
+}
+
+// severityCodes indexes the severityMap 'codes' parameter by the string form of each code. YAML decodes maps with
+// numeric keys as map[interface{}]interface{}, so both map types are accepted; anything else yields an empty index.
+func severityCodes(raw interface{}) map[string]severityCode {
+	codes := make(map[string]severityCode)
+	switch m := raw.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			codes[strings.TrimSpace(k)] = severityCode{code: k, label: v}
+		}
+	case map[interface{}]interface{}:
+		for k, v := range m {
+			codes[strings.TrimSpace(fmt.Sprintf("%v", k))] = severityCode{code: k, label: v}
+		}
+	}
+	return codes
+}
+
+// parseDateWithFallbacks parses a date string with layout, or when layout is empty with RFC3339, the naive
+// "2006-01-02T15:04:05", and the dateConvert fallback layouts. Surrounding whitespace is ignored.
+func parseDateWithFallbacks(value, layout string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if layout != "" {
+		t, err := time.Parse(layout, value)
+		return t, err == nil
+	}
+	for _, l := range append([]string{time.RFC3339Nano, "2006-01-02T15:04:05"}, dateFallbackFormats...) {
+		if t, err := time.Parse(l, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDateBound parses a validateDateRange bound: a time.Time (YAML decodes unquoted dates this way), "now",
+// "today" (start of the current UTC day), or a date string accepted by parseDateWithFallbacks.
+func parseDateBound(raw interface{}) (time.Time, bool) {
+	switch v := raw.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "now":
+			return time.Now(), true
+		case "today":
+			return time.Now().UTC().Truncate(24 * time.Hour), true
+		}
+		return parseDateWithFallbacks(v, "")
+	}
+	return time.Time{}, false
+}
+
+// padString implements padLeft and padRight, counting length in runes so multibyte input pads correctly.
+func padString(value interface{}, params map[string]interface{}, left bool, name string) interface{} {
+	strVal, ok := value.(string)
+	if !ok {
+		return value
+	}
+	length, ok := getIntParam(params, "length")
+	if !ok {
+		logging.Logf(logging.Warning, "%s: missing or invalid 'length' integer parameter", name)
+		return value
+	}
+	pad := " "
+	if padParam, exists := getStringParam(params, "pad"); exists {
+		pad = padParam
+	}
+	if utf8.RuneCountInString(pad) != 1 {
+		logging.Logf(logging.Warning, "%s: 'pad' parameter %q must be a single character", name, pad)
+		return value
+	}
+	missing := length - utf8.RuneCountInString(strVal)
+	if missing <= 0 {
+		return strVal
+	}
+	if left {
+		return strings.Repeat(pad, missing) + strVal
+	}
+	return strVal + strings.Repeat(pad, missing)
+}
+
+// affixString implements ensurePrefix, ensureSuffix, stripPrefix, and stripSuffix. The affix is compared rune by rune
+// so 'ignoreCase' matching works for multibyte characters.
+func affixString(value interface{}, params map[string]interface{}, prefix, strip bool, name string) interface{} {
+	strVal, ok := value.(string)
+	if !ok {
+		return value
+	}
+	affix, _ := getStringParam(params, "value")
+	if affix == "" {
+		logging.Logf(logging.Warning, "%s: missing or empty 'value' parameter", name)
+		return value
+	}
+	ignoreCase, _ := getBoolParam(params, "ignoreCase")
+
+	runes, n := []rune(strVal), utf8.RuneCountInString(affix)
+	present := false
+	if len(runes) >= n {
+		part := string(runes[len(runes)-n:])
+		if prefix {
+			part = string(runes[:n])
+		}
+		present = part == affix || (ignoreCase && strings.EqualFold(part, affix))
+	}
+	switch {
+	case strip && present && prefix:
+		return string(runes[n:])
+	case strip && present:
+		return string(runes[:len(runes)-n])
+	case strip || present:
+		return strVal
+	case prefix:
+		return affix + strVal
+	default:
+		return strVal + affix
+	}
+}
+
+// percentValue implements the parsing shared by parsePercent and normalizePercent: a percentage such as "45%",
+// "45" or a number, scaled by the 'scale' param ("fraction" (default) divides by 100, "whole" keeps it). It also
+// returns the upper bound of the valid range for that scale (1 or 100).
+func percentValue(value interface{}, params map[string]interface{}) (float64, float64, error) {
+	var num float64
+	if strVal, isString := value.(string); isString {
+		cleaned := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strVal), "%"))
+		f, err := strconv.ParseFloat(cleaned, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not parse '%s' as a percentage", strVal)
+		}
+		num = f
+	} else {
+		f, ok := parseValueAsFloat64(value)
+		if !ok {
+			return 0, 0, fmt.Errorf("unsupported input '%v' (type %T)", value, value)
+		}
+		num = f
+	}
+
+	scale, _ := getStringParam(params, "scale")
+	switch strings.ToLower(scale) {
+	case "", "fraction":
+		return num / 100, 1, nil
+	case "whole":
+		return num, 100, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown scale '%s' (expected 'fraction' or 'whole')", scale)
+	}
+}
+
+// normalizePercentValue implements normalizePercent and mustNormalizePercent. NaN and infinite percentages are
+// rejected even with 'clamp', since they have no meaningful nearest bound.
+func normalizePercentValue(value interface{}, params map[string]interface{}) (float64, error) {
+	num, upper, err := percentValue(value, params)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		return 0, fmt.Errorf("percentage '%v' is not a finite number", value)
+	}
+	if num >= 0 && num <= upper {
+		return num, nil
+	}
+	if clamp, _ := getBoolParam(params, "clamp"); clamp {
+		return math.Max(0, math.Min(upper, num)), nil
+	}
+	return 0, fmt.Errorf("percentage '%v' is outside the range 0-%v", value, upper)
+}
+
+var (
+	numberWordsSmall  = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	numberWordsTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	numberWordsScales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+)
+
+// spellInteger spells n in English words using short-scale names (e.g., 2000001 -> "two million one").
+func spellInteger(n uint64) string {
+	if n == 0 {
+		return numberWordsSmall[0]
+	}
+	var groups []string
+	for scale := 0; n > 0; scale++ {
+		if group := n % 1000; group > 0 {
+			words := spellHundreds(group)
+			if numberWordsScales[scale] != "" {
+				words += " " + numberWordsScales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		n /= 1000
+	}
+	return strings.Join(groups, " ")
+}
+
+// spellHundreds spells 1 <= n <= 999, e.g. 342 -> "three hundred forty-two".
+func spellHundreds(n uint64) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, numberWordsSmall[n/100]+" hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		parts = append(parts, numberWordsSmall[n])
+	case n%10 == 0:
+		parts = append(parts, numberWordsTens[n/10])
+	default:
+		parts = append(parts, numberWordsTens[n/10]+"-"+numberWordsSmall[n%10])
+	}
+	return strings.Join(parts, " ")
+}
+
+// pluralUnit returns unit, with an "s" appended unless count is exactly one.
+func pluralUnit(count uint64, unit string) string {
+	if count == 1 {
+		return unit
+	}
+	return unit + "s"
+}
+
+// jsonParseValue decodes value, which must be a string, as a single JSON document.
+func jsonParseValue(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("input must be a string, got %T", value)
+	}
+	var result interface{}
+	if err := json.Unmarshal([]byte(str), &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON '%s': %w", str, err)
+	}
+	return result, nil
+}
+
+// marshalJSONText encodes value as JSON without HTML escaping and without a trailing newline. Map keys are
+// sorted. A non-empty indent pretty-prints nested values with that indent per level.
+func marshalJSONText(value interface{}, indent string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if indent != "" {
+		encoder.SetIndent("", indent)
+	}
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// charsetClasses are the named character classes accepted by validateCharset.
+var charsetClasses = map[string]string{
+	"alnum": "A-Za-z0-9",
+	"alpha": "A-Za-z",
+	"digit": "0-9",
+	"upper": "A-Z",
+	"lower": "a-z",
+	"hex":   "0-9A-Fa-f",
+}
+
+// parseCharset returns a membership test for a validateCharset 'charset': a named class or a custom set of
+// characters and "lo-hi" ranges.
+func parseCharset(spec string) (func(rune) bool, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("'charset' parameter is required")
+	}
+	if class, ok := charsetClasses[strings.ToLower(spec)]; ok {
+		spec = class
+	}
+	singles := make(map[rune]bool)
+	var ranges [][2]rune
+	runes := []rune(spec)
+	for i := 0; i < len(runes); i++ {
+		if i+2 < len(runes) && runes[i+1] == '-' {
+			if runes[i] > runes[i+2] {
+				return nil, fmt.Errorf("invalid range '%c-%c' in charset '%s'", runes[i], runes[i+2], spec)
+			}
+			ranges = append(ranges, [2]rune{runes[i], runes[i+2]})
+			i += 2
+			continue
+		}
+		singles[runes[i]] = true
+	}
+	return func(r rune) bool {
+		if singles[r] {
+			return true
+		}
+		for _, rg := range ranges {
+			if r >= rg[0] && r <= rg[1] {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// roundDecimalString rounds an unsigned decimal string (e.g., "1234.565") to exactly decimals fraction digits,
+// rounding half away from zero on the decimal digits themselves, so 2.675 -> "2.68" despite its binary value.
+func roundDecimalString(s string, decimals int) string {
+	intPart, frac, _ := strings.Cut(s, ".")
+	if len(frac) <= decimals {
+		if decimals == 0 {
+			return intPart
+		}
+		return intPart + "." + frac + strings.Repeat("0", decimals-len(frac))
+	}
+	digits := []byte(intPart + frac[:decimals])
+	if frac[decimals] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	if decimals == 0 {
+		return string(digits)
+	}
+	intLen := len(digits) - decimals
+	return string(digits[:intLen]) + "." + string(digits[intLen:])
+}
+
+// groupThousands inserts sep between each group of three digits of an unsigned integer string, e.g.
+// ("1234567", ",") -> "1,234,567". An empty sep returns digits unchanged.
+func groupThousands(digits, sep string) string {
+	if sep == "" || len(digits) <= 3 {
+		return digits
+	}
+	var sb strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		sb.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
+
+// weekdayNames holds the day names for weekdayName per language, indexed by time.Weekday (Sunday first).
+var weekdayNames = map[string][7]string{
+	"en": {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	"de": {"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	"es": {"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	"fr": {"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	"it": {"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+	"nl": {"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+	"pt": {"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+}
+
+// weekdayNamesForLocale returns the day names for a locale such as "fr" or "en-US" (only the language is used).
+// An empty locale selects English.
+func weekdayNamesForLocale(locale string) ([7]string, bool) {
+	lang := util.LocaleLanguage(locale)
+	if lang == "" {
+		lang = "en"
+	}
+	names, ok := weekdayNames[lang]
+	return names, ok
+}
+
+// weekdayDate converts the input of isWeekend/weekdayName to a time: a time.Time, or a string parsed with
+// 'inputFormat' (or the fallbacks of parseDateWithFallbacks), converted to 'timezone' when given.
+func weekdayDate(value interface{}, params map[string]interface{}, name string) (time.Time, bool) {
+	var t time.Time
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false
+	case time.Time:
+		t = v
+	case string:
+		inputFormat, _ := getStringParam(params, "inputFormat")
+		parsed, ok := parseDateWithFallbacks(v, inputFormat)
+		if !ok {
+			logging.Logf(logging.Debug, "%s: failed to parse '%s' as a date", name, v)
+			return time.Time{}, false
+		}
+		t = parsed
+	default:
+		logging.Logf(logging.Debug, "%s: input value is not a string or time.Time (type %T)", name, value)
+		return time.Time{}, false
+	}
+	if tzName, ok := getStringParam(params, "timezone"); ok && tzName != "" {
+		loc, err := time.LoadLocation(tzName)
+		if err != nil {
+			logging.Logf(logging.Warning, "%s: invalid 'timezone' parameter '%s': %v", name, tzName, err)
+			return time.Time{}, false
+		}
+		t = t.In(loc)
+	}
+	return t, true
+}
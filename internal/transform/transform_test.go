@@ -4,8 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -310,6 +315,38 @@ func TestRegexExtract(t *testing.T) {
 	}
 }
 
+// TestCompileRegexCache verifies patterns are compiled once and reused, and invalid patterns keep failing the same way.
+func TestCompileRegexCache(t *testing.T) {
+	first, err := compileRegex(`cache-(\d+)`)
+	if err != nil {
+		t.Fatalf("compileRegex() error = %v", err)
+	}
+	second, err := compileRegex(`cache-(\d+)`)
+	if err != nil || second != first {
+		t.Errorf("compileRegex() second call = %p, %v; want cached %p", second, err, first)
+	}
+
+	_, firstErr := compileRegex(`cache-(`)
+	if firstErr == nil {
+		t.Fatal("compileRegex() with invalid pattern returned no error")
+	}
+	re, secondErr := compileRegex(`cache-(`)
+	if re != nil || secondErr != firstErr {
+		t.Errorf("compileRegex() invalid pattern second call = %v, %v; want nil and cached error %v", re, secondErr, firstErr)
+	}
+
+	// Transforms behave the same on repeated use of a cached invalid pattern.
+	params := map[string]interface{}{"pattern": `cache-(`}
+	for i := 0; i < 2; i++ {
+		resultsMatch(t, regexExtract("cache-1", nil, params), nil)
+		resultsMatch(t, regexRedact("cache-1", nil, params), "cache-1")
+		err, ok := validateRegex("cache-1", nil, params).(error)
+		if !ok || !strings.Contains(err.Error(), "invalid regex pattern 'cache-('") {
+			t.Errorf("validateRegex() with invalid pattern = %v, want invalid regex pattern error", err)
+		}
+	}
+}
+
 // TestTrim tests the trim transformation.
 func TestTrim(t *testing.T) {
 	testCases := []struct {
@@ -700,6 +737,60 @@ func TestMustToFloat(t *testing.T) {
 	}
 }
 
+// TestNumberSeparators tests the 'thousandsSep' and 'decimalSep' params of toInt, toFloat and their strict variants.
+func TestNumberSeparators(t *testing.T) {
+	european := map[string]interface{}{"thousandsSep": ".", "decimalSep": ","}
+	testCases := []struct {
+		name      string
+		input     interface{}
+		params    map[string]interface{}
+		wantFloat interface{} // float64 or nil
+		wantInt   interface{} // int64 or nil
+	}{
+		{name: "european decimal", input: "1.234,56", params: european, wantFloat: 1234.56, wantInt: nil},
+		{name: "european whole", input: "1.234.567", params: european, wantFloat: float64(1234567), wantInt: int64(1234567)},
+		{name: "european whole with zero fraction", input: "-1.234,00", params: european, wantFloat: float64(-1234), wantInt: int64(-1234)},
+		{name: "european without grouping", input: " 1234,5 ", params: european, wantFloat: 1234.5, wantInt: nil},
+		{name: "european plain integer", input: "42", params: european, wantFloat: float64(42), wantInt: int64(42)},
+		{name: "decimal comma only", input: "0,25", params: map[string]interface{}{"decimalSep": ","}, wantFloat: 0.25, wantInt: nil},
+		{name: "space thousands", input: "12 345,6", params: map[string]interface{}{"thousandsSep": " ", "decimalSep": ","}, wantFloat: 12345.6, wantInt: nil},
+		{name: "apostrophe thousands", input: "1'234'567.5", params: map[string]interface{}{"thousandsSep": "'"}, wantFloat: 1234567.5, wantInt: nil},
+		{name: "us thousands", input: "+1,234", params: map[string]interface{}{"thousandsSep": ","}, wantFloat: float64(1234), wantInt: int64(1234)},
+		{name: "us format read as european", input: "1,234.56", params: european, wantFloat: nil, wantInt: nil},
+		{name: "european format read as us", input: "1.234,56", params: map[string]interface{}{"thousandsSep": ",", "decimalSep": "."}, wantFloat: nil, wantInt: nil},
+		{name: "misplaced thousands separator", input: "12.34,5", params: european, wantFloat: nil, wantInt: nil},
+		{name: "two decimal separators", input: "1,2,3", params: european, wantFloat: nil, wantInt: nil},
+		{name: "thousands separator in fraction", input: "1,234.5", params: european, wantFloat: nil, wantInt: nil},
+		{name: "stray period with decimal comma", input: "1.5", params: map[string]interface{}{"decimalSep": ","}, wantFloat: nil, wantInt: nil},
+		{name: "empty thousands group", input: "1..234", params: european, wantFloat: nil, wantInt: nil},
+		{name: "same separators", input: "1,5", params: map[string]interface{}{"thousandsSep": ",", "decimalSep": ","}, wantFloat: nil, wantInt: nil},
+		{name: "non-string input", input: 1234.5, params: european, wantFloat: 1234.5, wantInt: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, toFloat(tc.input, nil, tc.params), tc.wantFloat)
+			resultsMatch(t, toInt(tc.input, nil, tc.params), tc.wantInt)
+			if _, isErr := mustToFloat(tc.input, nil, tc.params).(error); isErr != (tc.wantFloat == nil) {
+				t.Errorf("mustToFloat(%v) error = %v, want error %v", tc.input, isErr, tc.wantFloat == nil)
+			}
+			if _, isErr := mustToInt(tc.input, nil, tc.params).(error); isErr != (tc.wantInt == nil) {
+				t.Errorf("mustToInt(%v) error = %v, want error %v", tc.input, isErr, tc.wantInt == nil)
+			}
+		})
+	}
+
+	// Without the params, strings are parsed exactly as before, whatever other params are given.
+	for _, input := range []interface{}{"12345", "-100.000", "99.5", " 1.23 ", "1.5e3", "1,234", "1.234,56", "", "abc", nil, true, uint64(math.MaxUint64), 3.14} {
+		for _, params := range []map[string]interface{}{{}, {"other": ","}} {
+			resultsMatch(t, toFloat(input, nil, params), toFloat(input, nil, nil))
+			resultsMatch(t, toInt(input, nil, params), toInt(input, nil, nil))
+			resultsMatch(t, mustToFloat(input, nil, params), mustToFloat(input, nil, nil))
+			resultsMatch(t, mustToInt(input, nil, params), mustToInt(input, nil, nil))
+		}
+	}
+}
+
 // TestToBool tests the permissive toBool transformation.
 func TestToBool(t *testing.T) {
 	testCases := []struct {
@@ -808,6 +899,37 @@ func TestToString(t *testing.T) {
 	}
 }
 
+// TestToJSONString tests the toJSONString transformation.
+func TestToJSONString(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "array", input: []interface{}{"a", "b", "c"}, want: `["a","b","c"]`},
+		{name: "empty array", input: []interface{}{}, want: `[]`},
+		{name: "typed slice", input: []int{1, 2}, want: `[1,2]`},
+		{name: "map with sorted keys", input: map[string]interface{}{"b": 2, "a": "x"}, want: `{"a":"x","b":2}`},
+		{name: "nested structure", input: map[string]interface{}{"tags": []interface{}{"x", map[string]interface{}{"k": nil}}, "n": 1.5}, want: `{"n":1.5,"tags":["x",{"k":null}]}`},
+		{name: "string scalar", input: `say "hi" <b>`, want: `"say \"hi\" <b>"`},
+		{name: "int scalar", input: 42, want: `42`},
+		{name: "bool scalar", input: true, want: `true`},
+		{name: "time scalar", input: time.Date(2023, 3, 15, 10, 0, 0, 0, time.UTC), want: `"2023-03-15T10:00:00Z"`},
+		{name: "indent", input: map[string]interface{}{"b": 2, "a": []interface{}{1}}, params: map[string]interface{}{"indent": "  "}, want: "{\n  \"a\": [\n    1\n  ],\n  \"b\": 2\n}"},
+		{name: "indent scalar", input: "x", params: map[string]interface{}{"indent": "\t"}, want: `"x"`},
+		{name: "nil input", input: nil, want: nil},
+		{name: "unserializable", input: math.NaN(), want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := toJSONString(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
 // TestReplaceAll tests the replaceAll transformation.
 func TestReplaceAll(t *testing.T) {
 	testCases := []struct {
@@ -834,6 +956,37 @@ func TestReplaceAll(t *testing.T) {
 	}
 }
 
+// TestPadLeftRight tests the padLeft and padRight transformations.
+func TestPadLeftRight(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     interface{}
+		params    map[string]interface{}
+		wantLeft  interface{}
+		wantRight interface{}
+	}{
+		{name: "default space pad", input: "Ann", params: map[string]interface{}{"length": 6}, wantLeft: "   Ann", wantRight: "Ann   "},
+		{name: "zero pad", input: "42", params: map[string]interface{}{"length": 5, "pad": "0"}, wantLeft: "00042", wantRight: "42000"},
+		{name: "already at length", input: "12345", params: map[string]interface{}{"length": 5, "pad": "0"}, wantLeft: "12345", wantRight: "12345"},
+		{name: "longer than length", input: "123456", params: map[string]interface{}{"length": 3}, wantLeft: "123456", wantRight: "123456"},
+		{name: "multibyte input counts runes", input: "café", params: map[string]interface{}{"length": 6, "pad": "*"}, wantLeft: "**café", wantRight: "café**"},
+		{name: "multibyte pad rune", input: "ab", params: map[string]interface{}{"length": 4, "pad": "·"}, wantLeft: "··ab", wantRight: "ab··"},
+		{name: "empty input", input: "", params: map[string]interface{}{"length": 2, "pad": "0"}, wantLeft: "00", wantRight: "00"},
+		{name: "length as float", input: "7", params: map[string]interface{}{"length": 3.0, "pad": "0"}, wantLeft: "007", wantRight: "700"},
+		{name: "multi-character pad", input: "7", params: map[string]interface{}{"length": 3, "pad": "ab"}, wantLeft: "7", wantRight: "7"},
+		{name: "missing length", input: "7", params: map[string]interface{}{"pad": "0"}, wantLeft: "7", wantRight: "7"},
+		{name: "non-string input", input: 42, params: map[string]interface{}{"length": 5, "pad": "0"}, wantLeft: 42, wantRight: 42},
+		{name: "nil input", input: nil, params: map[string]interface{}{"length": 5}, wantLeft: nil, wantRight: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, padLeft(tc.input, nil, tc.params), tc.wantLeft)
+			resultsMatch(t, padRight(tc.input, nil, tc.params), tc.wantRight)
+		})
+	}
+}
+
 // TestSubstring tests the substring transformation.
 func TestSubstring(t *testing.T) {
 	testCases := []struct {
@@ -952,6 +1105,42 @@ func TestValidateRegex(t *testing.T) {
 	}
 }
 
+// TestValidateInteger tests that numeric values with a fractional part fail and everything else passes unchanged.
+func TestValidateInteger(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		want  interface{} // Expect original value or error
+	}{
+		{name: "int", input: 10, want: 10},
+		{name: "negative int64", input: int64(-42), want: int64(-42)},
+		{name: "uint", input: uint(7), want: uint(7)},
+		{name: "zero", input: 0, want: 0},
+		{name: "integer-valued float", input: 10.0, want: 10.0},
+		{name: "integer-valued float32", input: float32(-3), want: float32(-3)},
+		{name: "large integer-valued float", input: 1e18, want: 1e18},
+		{name: "fractional float", input: 10.5, want: errors.New("value 10.5 is not an integer")},
+		{name: "small fraction", input: -0.001, want: errors.New("value -0.001 is not an integer")},
+		{name: "integer string", input: "10", want: "10"},
+		{name: "integer-valued float string", input: " 10.0 ", want: " 10.0 "},
+		{name: "scientific notation string", input: "1.5e3", want: "1.5e3"},
+		{name: "fractional string", input: "10.5", want: errors.New("value 10.5 is not an integer")},
+		{name: "NaN string", input: "NaN", want: errors.New("value NaN is not an integer")},
+		{name: "infinite float", input: math.Inf(1), want: errors.New("value +Inf is not an integer")},
+		{name: "non-numeric string passes", input: "abc", want: "abc"},
+		{name: "empty string passes", input: "", want: ""},
+		{name: "bool passes", input: true, want: true},
+		{name: "nil passes", input: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateInteger(tc.input, nil, nil)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
 // TestValidateNumericRange tests the validateNumericRange validation.
 func TestValidateNumericRange(t *testing.T) {
 	testCases := []struct {
@@ -1096,6 +1285,40 @@ func TestCompareValues(t *testing.T) {
 	}
 }
 
+// TestCompareTimes tests chronological comparison of date strings and time.Time values.
+func TestCompareTimes(t *testing.T) {
+	time1 := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		inputA  interface{}
+		inputB  interface{}
+		want    int
+		wantErr bool
+	}{
+		{name: "RFC3339 less", inputA: "2024-03-01T08:00:00Z", inputB: "2024-03-02T08:00:00Z", want: -1},
+		{name: "offsets compared as instants", inputA: "2024-03-01T10:00:00+05:00", inputB: "2024-03-01T08:00:00Z", want: -1}, // Lexicographically greater
+		{name: "same instant, different zones", inputA: "2024-03-01T13:00:00+05:00", inputB: "2024-03-01T08:00:00Z", want: 0},
+		{name: "fractional seconds", inputA: "2024-03-01T08:00:00.5Z", inputB: "2024-03-01T08:00:00Z", want: 1},
+		{name: "fallback date layout", inputA: "2024-03-10", inputB: "2024-03-09", want: 1},
+		{name: "time.Time vs string", inputA: time1, inputB: "2024-03-01T08:00:00Z", want: 0},
+		{name: "nil less than time", inputA: nil, inputB: "2024-03-01", want: -1},
+		{name: "unparsable string", inputA: "yesterday", inputB: "2024-03-01", wantErr: true},
+		{name: "non-date type", inputA: 20240301, inputB: "2024-03-01", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CompareTimes(tc.inputA, tc.inputB)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("CompareTimes(%v, %v) error = %v, wantErr %v", tc.inputA, tc.inputB, err, tc.wantErr)
+			} else if !tc.wantErr && got != tc.want {
+				t.Errorf("CompareTimes(%v, %v) = %d, want %d", tc.inputA, tc.inputB, got, tc.want)
+			}
+		})
+	}
+}
+
 // TestHashTransform tests the hash generation logic using canonical string representations.
 func TestHashTransform(t *testing.T) {
 	record := map[string]interface{}{
@@ -1275,3 +1498,2147 @@ func TestHashTransform(t *testing.T) {
 		})
 	}
 }
+
+// TestToBoolCustom tests the toBoolCustom and mustToBoolCustom transformations.
+func TestToBoolCustom(t *testing.T) {
+	ynParams := map[string]interface{}{"truthy": []interface{}{"Y", "ON", "SI"}, "falsy": []interface{}{"N", "OFF", "NO"}}
+	ciParams := map[string]interface{}{"truthy": []interface{}{"Y", "ON", "SI"}, "falsy": []interface{}{"N", "OFF", "NO"}, "caseInsensitive": true}
+	intParams := map[string]interface{}{"truthy": []interface{}{1, 2}, "falsy": []interface{}{0}}
+	testCases := []struct {
+		name     string
+		input    interface{}
+		params   map[string]interface{}
+		want     interface{}
+		wantMust interface{}
+	}{
+		{name: "truthy match", input: "Y", params: ynParams, want: true, wantMust: true},
+		{name: "falsy match", input: "OFF", params: ynParams, want: false, wantMust: false},
+		{name: "whitespace trimmed", input: " SI ", params: ynParams, want: true, wantMust: true},
+		{name: "case sensitive by default", input: "on", params: ynParams, want: nil, wantMust: errors.New("mustToBoolCustom: value 'on' is not in the truthy or falsy sets")},
+		{name: "case insensitive option", input: "on", params: ciParams, want: true, wantMust: true},
+		{name: "case insensitive falsy", input: "No", params: ciParams, want: false, wantMust: false},
+		{name: "integer codes", input: 2, params: intParams, want: true, wantMust: true},
+		{name: "integer string code", input: "0", params: intParams, want: false, wantMust: false},
+		{name: "bool passthrough", input: true, params: ynParams, want: true, wantMust: true},
+		{name: "unmatched", input: "MAYBE", params: ynParams, want: nil, wantMust: errors.New("mustToBoolCustom: value 'MAYBE' is not in the truthy or falsy sets")},
+		{name: "nil input", input: nil, params: ynParams, want: nil, wantMust: errors.New("mustToBoolCustom: input is nil")},
+		{name: "missing falsy", input: "Y", params: map[string]interface{}{"truthy": []interface{}{"Y"}}, want: nil, wantMust: errors.New("mustToBoolCustom: requires non-empty 'truthy' and 'falsy' array parameters")},
+		{name: "empty truthy", input: "Y", params: map[string]interface{}{"truthy": []interface{}{}, "falsy": []interface{}{"N"}}, want: nil, wantMust: errors.New("mustToBoolCustom: requires non-empty 'truthy' and 'falsy' array parameters")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, toBoolCustom(tc.input, nil, tc.params), tc.want)
+			resultsMatch(t, mustToBoolCustom(tc.input, nil, tc.params), tc.wantMust)
+		})
+	}
+}
+
+// TestParsePercent tests the parsePercent transformation.
+// TestNormalizePercent tests the normalizePercent and mustNormalizePercent transformations.
+func TestNormalizePercent(t *testing.T) {
+	whole := map[string]interface{}{"scale": "whole"}
+	wholeClamp := map[string]interface{}{"scale": "whole", "clamp": true}
+	testCases := []struct {
+		name     string
+		input    interface{}
+		params   map[string]interface{}
+		want     interface{}
+		wantMust interface{}
+	}{
+		{name: "mid-range fraction default", input: "45%", want: 0.45, wantMust: 0.45},
+		{name: "mid-range whole", input: " 45.5 % ", params: whole, want: 45.5, wantMust: 45.5},
+		{name: "no percent sign", input: "12", params: whole, want: 12.0, wantMust: 12.0},
+		{name: "numeric input", input: 50, want: 0.5, wantMust: 0.5},
+		{name: "bounds are valid", input: "100%", params: whole, want: 100.0, wantMust: 100.0},
+		{name: "zero is valid", input: "0%", want: 0.0, wantMust: 0.0},
+		{name: "over 100 clamped whole", input: "110%", params: wholeClamp, want: 100.0, wantMust: 100.0},
+		{name: "over 100 clamped fraction", input: "110%", params: map[string]interface{}{"clamp": true}, want: 1.0, wantMust: 1.0},
+		{name: "negative clamped", input: "-5%", params: wholeClamp, want: 0.0, wantMust: 0.0},
+		{name: "over 100 not clamped", input: "110%", params: whole, want: nil, wantMust: errors.New("mustNormalizePercent: percentage '110%' is outside the range 0-100")},
+		{name: "negative not clamped", input: "-5%", want: nil, wantMust: errors.New("mustNormalizePercent: percentage '-5%' is outside the range 0-1")},
+		{name: "not a number", input: "abc%", want: nil, wantMust: errors.New("mustNormalizePercent: could not parse 'abc%' as a percentage")},
+		{name: "unknown scale", input: "45%", params: map[string]interface{}{"scale": "basis"}, want: nil, wantMust: errors.New("mustNormalizePercent: unknown scale 'basis' (expected 'fraction' or 'whole')")},
+		{name: "nil input", input: nil, want: nil, wantMust: errors.New("mustNormalizePercent: input is nil")},
+		{name: "NaN clamped", input: "NaN%", params: wholeClamp, want: nil, wantMust: errors.New("mustNormalizePercent: percentage 'NaN%' is not a finite number")},
+		{name: "NaN not clamped", input: "NaN", want: nil, wantMust: errors.New("mustNormalizePercent: percentage 'NaN' is not a finite number")},
+		{name: "infinity clamped", input: "+Inf%", params: map[string]interface{}{"clamp": true}, want: nil, wantMust: errors.New("mustNormalizePercent: percentage '+Inf%' is not a finite number")},
+		{name: "negative infinite float clamped", input: math.Inf(-1), params: wholeClamp, want: nil, wantMust: errors.New("mustNormalizePercent: percentage '-Inf' is not a finite number")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, normalizePercent(tc.input, nil, tc.params), tc.want)
+			resultsMatch(t, mustNormalizePercent(tc.input, nil, tc.params), tc.wantMust)
+		})
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	whole := map[string]interface{}{"scale": "whole"}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "percent sign fraction default", input: "45%", want: 0.45},
+		{name: "percent sign explicit fraction", input: "45%", params: map[string]interface{}{"scale": "fraction"}, want: 0.45},
+		{name: "percent sign whole", input: "45%", params: whole, want: 45.0},
+		{name: "no percent sign", input: "45", want: 0.45},
+		{name: "no percent sign whole", input: "45", params: whole, want: 45.0},
+		{name: "negative percent", input: "-12.5%", want: -0.125},
+		{name: "negative percent whole", input: "-12.5%", params: whole, want: -12.5},
+		{name: "surrounding whitespace", input: "  45 %  ", want: 0.45},
+		{name: "numeric input", input: 50, want: 0.5},
+		{name: "float input whole", input: 12.5, params: whole, want: 12.5},
+		{name: "not a number", input: "abc%", want: nil},
+		{name: "empty string", input: "", want: nil},
+		{name: "only percent sign", input: "%", want: nil},
+		{name: "nil input", input: nil, want: nil},
+		{name: "unknown scale", input: "45%", params: map[string]interface{}{"scale": "basis"}, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parsePercent(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestParseCurrency tests the parseCurrency transformation.
+func TestParseCurrency(t *testing.T) {
+	european := map[string]interface{}{"decimalSeparator": ",", "groupSeparator": "."}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "US symbol prefix", input: "$1,234.56", want: 1234.56},
+		{name: "US code prefix", input: "USD 1234.56", want: 1234.56},
+		{name: "US code suffix", input: "1,234.56 USD", want: 1234.56},
+		{name: "European symbol suffix", input: "1.234,56 €", params: european, want: 1234.56},
+		{name: "European code prefix", input: "EUR 1.234.567,8", params: european, want: 1234567.8},
+		{name: "pound prefix", input: "£99", want: 99.0},
+		{name: "negative sign", input: "-$5.25", want: -5.25},
+		{name: "accounting negative", input: "($1,000.00)", want: -1000.0},
+		{name: "space group separator", input: "1 234,50", params: map[string]interface{}{"decimalSeparator": ",", "groupSeparator": " "}, want: 1234.5},
+		{name: "custom symbols", input: "R$ 10,50", params: map[string]interface{}{"decimalSeparator": ",", "groupSeparator": ".", "symbols": []interface{}{"R$"}}, want: 10.5},
+		{name: "numeric input", input: 12.5, want: 12.5},
+		{name: "currency output from code", input: "1234.56 CAD", params: map[string]interface{}{"output": "currency"}, want: "CAD"},
+		{name: "currency output from symbol", input: "1.234,56 €", params: map[string]interface{}{"output": "currency"}, want: "EUR"},
+		{name: "currency output none found", input: "1234.56", params: map[string]interface{}{"output": "currency"}, want: nil},
+		{name: "European format with default separators", input: "1.234,56", want: nil},
+		{name: "European symbol suffix with default separators", input: "1.234,56 €", want: nil},
+		{name: "misplaced group separator", input: "$12,34.50", want: nil},
+		{name: "group separator in fraction", input: "1.234,5", params: map[string]interface{}{"decimalSeparator": "."}, want: nil},
+		{name: "not a number", input: "$abc", want: nil},
+		{name: "only symbol", input: "$", want: nil},
+		{name: "nil input", input: nil, want: nil},
+		{name: "unsupported type", input: true, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseCurrency(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestCountryCode tests the countryCode transformation.
+func TestCountryCode(t *testing.T) {
+	toAlpha2 := map[string]interface{}{"direction": "toAlpha2"}
+	toName := map[string]interface{}{"direction": "toName"}
+	toAlpha3 := map[string]interface{}{"direction": "alpha2ToAlpha3"}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "name to alpha2", input: "United States", params: toAlpha2, want: "US"},
+		{name: "name to alpha2 case-insensitive", input: "  germany ", params: toAlpha2, want: "DE"},
+		{name: "alias to alpha2", input: "UK", params: toAlpha2, want: "GB"},
+		{name: "alpha3 to alpha2", input: "fra", params: toAlpha2, want: "FR"},
+		{name: "alpha2 to alpha2 normalizes case", input: "jp", params: toAlpha2, want: "JP"},
+		{name: "alpha2 to name case-insensitive", input: "us", params: toName, want: "United States"},
+		{name: "alpha3 to name", input: "GBR", params: toName, want: "United Kingdom"},
+		{name: "alpha2 to alpha3", input: "US", params: toAlpha3, want: "USA"},
+		{name: "name rejected for alpha2ToAlpha3", input: "Canada", params: toAlpha3, want: nil},
+		{name: "direction case-insensitive", input: "Canada", params: map[string]interface{}{"direction": "TOALPHA2"}, want: "CA"},
+		{name: "unknown country", input: "Atlantis", params: toAlpha2, want: nil},
+		{name: "unknown code", input: "ZZ", params: toName, want: nil},
+		{name: "non-string input", input: 840, params: toAlpha2, want: nil},
+		{name: "unknown direction", input: "US", params: map[string]interface{}{"direction": "toNumeric"}, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := countryCode(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestRegexRedact tests the regexRedact transformation.
+func TestRegexRedact(t *testing.T) {
+	emailPattern := `[\w.+-]+@[\w-]+\.[\w.]+`
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "multiple matches default replacement", input: "contact a@x.com or b.c@y.org today", params: map[string]interface{}{"pattern": emailPattern}, want: "contact [REDACTED] or [REDACTED] today"},
+		{name: "custom replacement", input: "call 555-1234 or 555-9876", params: map[string]interface{}{"pattern": `\d{3}-\d{4}`, "replacement": "***"}, want: "call *** or ***"},
+		{name: "empty replacement deletes", input: "a1b22c333", params: map[string]interface{}{"pattern": `\d+`, "replacement": ""}, want: "abc"},
+		{name: "replacement is literal", input: "id=42", params: map[string]interface{}{"pattern": `(\d+)`, "replacement": "$1x"}, want: "id=$1x"},
+		{name: "no match unchanged", input: "nothing sensitive here", params: map[string]interface{}{"pattern": emailPattern}, want: "nothing sensitive here"},
+		{name: "non-string passthrough", input: 12345, params: map[string]interface{}{"pattern": `\d`}, want: 12345},
+		{name: "missing pattern", input: "text", params: map[string]interface{}{}, want: "text"},
+		{name: "invalid pattern", input: "text", params: map[string]interface{}{"pattern": "("}, want: "text"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := regexRedact(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+
+	t.Run("shorthand via ApplyTransform", func(t *testing.T) {
+		got := ApplyTransform(`regexRedact:\d{4}`, nil, "pin 1234 and 5678", nil)
+		resultsMatch(t, got, "pin [REDACTED] and [REDACTED]")
+	})
+}
+
+// TestCollapseRepeats tests the collapseRepeats transformation.
+func TestCollapseRepeats(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "collapse any run", input: "aaa---bbb", want: "a-b"},
+		{name: "hyphens and spaces only", input: "aaa---bbb  ccc", params: map[string]interface{}{"chars": "- "}, want: "aaa-bbb ccc"},
+		{name: "single occurrences intact", input: "a-b c-d", params: map[string]interface{}{"chars": "- "}, want: "a-b c-d"},
+		{name: "multibyte runes", input: "日日本本--", params: map[string]interface{}{"chars": "日-"}, want: "日本本-"},
+		{name: "empty string", input: "", want: ""},
+		{name: "non-string passthrough", input: 1100, want: 1100},
+		{name: "nil passthrough", input: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := collapseRepeats(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestFuzzyMatch tests the fuzzyMatch transformation.
+func TestFuzzyMatch(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "exact match", input: "Acme Corp", params: map[string]interface{}{"reference": "Acme Corp", "threshold": 1.0}, want: true},
+		{name: "near match above threshold", input: "Acme Crop", params: map[string]interface{}{"reference": "Acme Corp", "threshold": 0.75}, want: true},  // distance 2, similarity 0.777
+		{name: "near match below threshold", input: "Acme Crop", params: map[string]interface{}{"reference": "Acme Corp", "threshold": 0.8}, want: false}, // distance 2, similarity 0.777
+		{name: "exactly at threshold", input: "kitten", params: map[string]interface{}{"reference": "sitten", "threshold": 5.0 / 6.0}, want: true},
+		{name: "far match", input: "Globex", params: map[string]interface{}{"reference": "Acme Corp", "threshold": 0.5}, want: false},
+		{name: "default threshold", input: "Jonathan", params: map[string]interface{}{"reference": "Jonathon"}, want: true}, // similarity 0.875
+		{name: "case sensitive by default", input: "ACME", params: map[string]interface{}{"reference": "acme", "threshold": 0.9}, want: false},
+		{name: "case insensitive option", input: "ACME", params: map[string]interface{}{"reference": "acme", "threshold": 0.9, "caseInsensitive": true}, want: true},
+		{name: "numeric input stringified", input: 12345, params: map[string]interface{}{"reference": "12346", "threshold": 0.8}, want: true},
+		{name: "both empty", input: "", params: map[string]interface{}{"reference": "", "threshold": 1}, want: true},
+		{name: "nil input", input: nil, params: map[string]interface{}{"reference": "x"}, want: false},
+		{name: "missing reference", input: "x", params: map[string]interface{}{}, want: false},
+		{name: "threshold out of range", input: "x", params: map[string]interface{}{"reference": "x", "threshold": 1.5}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := fuzzyMatch(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestEditDistance tests the editDistance transformation.
+func TestEditDistance(t *testing.T) {
+	record := map[string]interface{}{"name": "kitten", "upper": "KITTEN", "code": 1234, "empty": "", "missing": nil}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "identical", input: "kitten", params: map[string]interface{}{"field": "name"}, want: 0},
+		{name: "one substitution", input: "sitten", params: map[string]interface{}{"field": "name"}, want: 1},
+		{name: "one insertion", input: "kittens", params: map[string]interface{}{"field": "name"}, want: 1},
+		{name: "one deletion", input: "kiten", params: map[string]interface{}{"field": "name"}, want: 1},
+		{name: "completely different", input: "abcdfg", params: map[string]interface{}{"field": "name"}, want: 6},
+		{name: "against empty", input: "abc", params: map[string]interface{}{"field": "empty"}, want: 3},
+		{name: "multi-byte runes", input: "kittén", params: map[string]interface{}{"field": "name"}, want: 1},
+		{name: "case sensitive by default", input: "kitten", params: map[string]interface{}{"field": "upper"}, want: 6},
+		{name: "case insensitive option", input: "kitten", params: map[string]interface{}{"field": "upper", "caseInsensitive": true}, want: 0},
+		{name: "numeric values stringified", input: 1243, params: map[string]interface{}{"field": "code"}, want: 2},
+		{name: "nil input", input: nil, params: map[string]interface{}{"field": "name"}, want: nil},
+		{name: "nil field value", input: "x", params: map[string]interface{}{"field": "missing"}, want: nil},
+		{name: "absent field", input: "x", params: map[string]interface{}{"field": "nope"}, want: nil},
+		{name: "missing field param", input: "x", params: map[string]interface{}{}, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := editDistance(tc.input, record, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestDedupList tests the dedupList transformation.
+func TestDedupList(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "duplicates removed preserving order", input: "a,b,a,c,b", params: nil, want: "a,b,c"},
+		{name: "no duplicates", input: "c,a,b", params: nil, want: "c,a,b"},
+		{name: "custom separator", input: "x|y|x", params: map[string]interface{}{"separator": "|"}, want: "x|y"},
+		{name: "multi-char separator", input: "x; y; x", params: map[string]interface{}{"separator": "; "}, want: "x; y"},
+		{name: "whitespace without trim kept distinct", input: "a, a,a", params: nil, want: "a, a"},
+		{name: "whitespace with trim", input: " a , b,a ,c", params: map[string]interface{}{"trim": true}, want: "a,b,c"},
+		{name: "case sensitive by default", input: "A,a,B", params: nil, want: "A,a,B"},
+		{name: "case insensitive keeps first spelling", input: "Red,red,BLUE,blue", params: map[string]interface{}{"caseInsensitive": true}, want: "Red,BLUE"},
+		{name: "single element", input: "only", params: nil, want: "only"},
+		{name: "empty string", input: "", params: nil, want: ""},
+		{name: "repeated empty elements", input: "a,,b,,", params: nil, want: "a,,b"},
+		{name: "non-string passthrough", input: 123, params: nil, want: 123},
+		{name: "nil passthrough", input: nil, params: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := dedupList(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestSortList tests the sortList transformation.
+func TestSortList(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "string sort", input: "c,a,b", params: nil, want: "a,b,c"},
+		{name: "string sort of numbers is lexical", input: "10,9,100", params: nil, want: "10,100,9"},
+		{name: "numeric sort", input: "10,9,100", params: map[string]interface{}{"numeric": true}, want: "9,10,100"},
+		{name: "numeric sort with negatives and decimals", input: "2.5,-1,0,2", params: map[string]interface{}{"numeric": true}, want: "-1,0,2,2.5"},
+		{name: "numeric sort keeps original element text", input: "3, 1,2", params: map[string]interface{}{"numeric": true}, want: " 1,2,3"},
+		{name: "mixed content falls back to string sort", input: "10,b,9,a", params: map[string]interface{}{"numeric": true}, want: "10,9,a,b"},
+		{name: "custom separator", input: "z|x|y", params: map[string]interface{}{"separator": "|"}, want: "x|y|z"},
+		{name: "single element", input: "only", params: nil, want: "only"},
+		{name: "empty string", input: "", params: nil, want: ""},
+		{name: "non-string passthrough", input: 42, params: nil, want: 42},
+		{name: "nil passthrough", input: nil, params: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sortList(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestListCount tests the listCount transformation.
+func TestListCount(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "delimited string", input: "a,b,c", params: nil, want: 3},
+		{name: "custom separator", input: "a|b", params: map[string]interface{}{"separator": "|"}, want: 2},
+		{name: "empty elements counted by default", input: "a,,b,", params: nil, want: 4},
+		{name: "empty elements ignored", input: "a,, ,b,", params: map[string]interface{}{"ignoreEmpty": true}, want: 2},
+		{name: "single element", input: "only", params: nil, want: 1},
+		{name: "empty string", input: "", params: nil, want: 0},
+		{name: "nil input", input: nil, params: nil, want: 0},
+		{name: "interface slice", input: []interface{}{"a", 1, true}, params: nil, want: 3},
+		{name: "string slice", input: []string{"x", "y"}, params: nil, want: 2},
+		{name: "slice with empties ignored", input: []interface{}{"a", nil, "", "b"}, params: map[string]interface{}{"ignoreEmpty": true}, want: 2},
+		{name: "empty slice", input: []interface{}{}, params: nil, want: 0},
+		{name: "single element slice", input: []interface{}{"a"}, params: nil, want: 1},
+		{name: "unsupported type", input: 42, params: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := listCount(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestNthField tests the nthField transformation.
+func TestNthField(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "first element", input: "a,b,c", params: map[string]interface{}{"index": 0}, want: "a"},
+		{name: "middle element", input: "a,b,c", params: map[string]interface{}{"index": 1}, want: "b"},
+		{name: "float index from YAML", input: "a,b,c", params: map[string]interface{}{"index": 2.0}, want: "c"},
+		{name: "custom separator", input: "2024-01-15", params: map[string]interface{}{"separator": "-", "index": 1}, want: "01"},
+		{name: "negative index last", input: "a,b,c", params: map[string]interface{}{"index": -1}, want: "c"},
+		{name: "negative index first", input: "a,b,c", params: map[string]interface{}{"index": -3}, want: "a"},
+		{name: "out of range with default", input: "a,b", params: map[string]interface{}{"index": 5, "default": "n/a"}, want: "n/a"},
+		{name: "negative out of range with default", input: "a,b", params: map[string]interface{}{"index": -3, "default": "n/a"}, want: "n/a"},
+		{name: "out of range without default", input: "a,b", params: map[string]interface{}{"index": 2}, want: nil},
+		{name: "empty element is returned", input: "a,,c", params: map[string]interface{}{"index": 1, "default": "x"}, want: ""},
+		{name: "nil input returns default", input: nil, params: map[string]interface{}{"index": 0, "default": "none"}, want: "none"},
+		{name: "missing index returns default", input: "a,b", params: map[string]interface{}{"default": "d"}, want: "d"},
+		{name: "non-string passthrough", input: 123, params: map[string]interface{}{"index": 0}, want: 123},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := nthField(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestExcelSerialToDate tests the excelSerialToDate transformation.
+func TestExcelSerialToDate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "known serial", input: 44927, params: nil, want: "2023-01-01"},
+		{name: "known serial as string", input: "45366", params: nil, want: "2024-03-15"},
+		{name: "float serial with time", input: 45366.75, params: map[string]interface{}{"outputFormat": "2006-01-02 15:04:05"}, want: "2024-03-15 18:00:00"},
+		{name: "custom output format", input: 44927.0, params: map[string]interface{}{"outputFormat": "01/02/2006"}, want: "01/01/2023"},
+		{name: "serial 1", input: 1, params: nil, want: "1900-01-01"},
+		{name: "serial 59 before leap bug", input: 59, params: nil, want: "1900-02-28"},
+		{name: "serial 60 fictitious leap day", input: 60, params: nil, want: 60},
+		{name: "serial 61 after leap bug", input: 61, params: nil, want: "1900-03-01"},
+		{name: "serial 0 out of range", input: 0, params: nil, want: 0},
+		{name: "1904 date system", input: 0, params: map[string]interface{}{"date1904": true}, want: "1904-01-01"},
+		{name: "1904 date system known serial", input: 43465, params: map[string]interface{}{"date1904": true}, want: "2023-01-01"},
+		{name: "non-numeric returns original", input: "abc", params: nil, want: "abc"},
+		{name: "nil returns nil", input: nil, params: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := excelSerialToDate(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestCastType tests the castType transformation.
+func TestCastType(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "int from string", input: "42", params: map[string]interface{}{"type": "int"}, want: int64(42)},
+		{name: "int failure", input: "abc", params: map[string]interface{}{"type": "int"}, want: nil},
+		{name: "float from string", input: "3.5", params: map[string]interface{}{"type": "float"}, want: 3.5},
+		{name: "float failure", input: "x1", params: map[string]interface{}{"type": "float"}, want: nil},
+		{name: "bool from string", input: "yes", params: map[string]interface{}{"type": "bool"}, want: true},
+		{name: "bool failure", input: "maybe", params: map[string]interface{}{"type": "bool"}, want: nil},
+		{name: "string from int", input: 7, params: map[string]interface{}{"type": "string"}, want: "7"},
+		{name: "type is case insensitive", input: "8", params: map[string]interface{}{"type": "INT"}, want: int64(8)},
+		{name: "date with formats", input: "03/15/2024", params: map[string]interface{}{"type": "date", "inputFormat": "01/02/2006", "outputFormat": "2006-01-02"}, want: "2024-03-15"},
+		{name: "date with fallback parsing", input: "2024-03-15", params: map[string]interface{}{"type": "date", "outputFormat": "02 Jan 2006"}, want: "15 Mar 2024"},
+		{name: "date failure", input: "not a date", params: map[string]interface{}{"type": "date"}, want: nil},
+		{name: "date non-string failure", input: 123, params: map[string]interface{}{"type": "date"}, want: nil},
+		{name: "invalid type", input: "1", params: map[string]interface{}{"type": "decimal"}, want: nil},
+		{name: "missing type", input: "1", params: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := castType(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestSumDelimited tests the sumDelimited and mustSumDelimited transformations.
+func TestSumDelimited(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      interface{}
+		params     map[string]interface{}
+		want       interface{}
+		wantStrict interface{} // nil means an error is expected from the strict variant
+	}{
+		{name: "all numeric", input: "10,20,30", want: 60.0, wantStrict: 60.0},
+		{name: "decimals and negatives", input: "1.5, -0.5 ,2", want: 3.0, wantStrict: 3.0},
+		{name: "custom separator", input: "1|2|3", params: map[string]interface{}{"separator": "|"}, want: 6.0, wantStrict: 6.0},
+		{name: "blank elements ignored", input: "5,,5, ", want: 10.0, wantStrict: 10.0},
+		{name: "mixed content", input: "10,abc,20", want: 30.0, wantStrict: nil},
+		{name: "all invalid", input: "a,b", want: 0.0, wantStrict: nil},
+		{name: "empty string", input: "", want: 0.0, wantStrict: 0.0},
+		{name: "single element", input: "42", want: 42.0, wantStrict: 42.0},
+		{name: "numeric input", input: 7, want: 7.0, wantStrict: 7.0},
+		{name: "nil input", input: nil, want: nil, wantStrict: nil},
+		{name: "unsupported type", input: true, want: nil, wantStrict: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, sumDelimited(tc.input, nil, tc.params), tc.want)
+
+			strict := mustSumDelimited(tc.input, nil, tc.params)
+			if tc.wantStrict == nil {
+				if _, isErr := strict.(error); !isErr {
+					t.Errorf("mustSumDelimited(%v) = %v, want error", tc.input, strict)
+				}
+			} else {
+				resultsMatch(t, strict, tc.wantStrict)
+			}
+		})
+	}
+}
+
+// TestSeverityMap tests the severityMap transformation.
+func TestSeverityMap(t *testing.T) {
+	codes := map[interface{}]interface{}{0: "DEBUG", 1: "INFO", 2: "WARN", 3: "ERROR", "F": "FATAL"}
+	bands := []interface{}{
+		map[string]interface{}{"max": 4, "label": "LOW"},
+		map[string]interface{}{"min": 4, "max": 7, "label": "MEDIUM"},
+		map[string]interface{}{"min": 7, "label": "HIGH"},
+	}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "exact int code", input: 3, params: map[string]interface{}{"codes": codes}, want: "ERROR"},
+		{name: "exact string code", input: " 1 ", params: map[string]interface{}{"codes": codes}, want: "INFO"},
+		{name: "whole float code", input: 2.0, params: map[string]interface{}{"codes": codes}, want: "WARN"},
+		{name: "non-numeric code", input: "F", params: map[string]interface{}{"codes": codes}, want: "FATAL"},
+		{name: "string-keyed codes", input: 5, params: map[string]interface{}{"codes": map[string]interface{}{"5": "NOTICE"}}, want: "NOTICE"},
+		{name: "unknown code", input: 9, params: map[string]interface{}{"codes": codes}, want: nil},
+		{name: "unknown code default", input: 9, params: map[string]interface{}{"codes": codes, "default": "UNKNOWN"}, want: "UNKNOWN"},
+		{name: "band open low", input: -2, params: map[string]interface{}{"bands": bands}, want: "LOW"},
+		{name: "band min inclusive", input: 4, params: map[string]interface{}{"bands": bands}, want: "MEDIUM"},
+		{name: "band max exclusive", input: "6.99", params: map[string]interface{}{"bands": bands}, want: "MEDIUM"},
+		{name: "band open high", input: 100, params: map[string]interface{}{"bands": bands}, want: "HIGH"},
+		{name: "codes before bands", input: 3, params: map[string]interface{}{"codes": codes, "bands": bands}, want: "ERROR"},
+		{name: "bands after code miss", input: 5.5, params: map[string]interface{}{"codes": codes, "bands": bands}, want: "MEDIUM"},
+		{name: "no band matches", input: 10, params: map[string]interface{}{"bands": bands[:2], "default": "OTHER"}, want: "OTHER"},
+		{name: "non-numeric misses bands", input: "high", params: map[string]interface{}{"bands": bands}, want: nil},
+		{name: "reverse label to code", input: "error", params: map[string]interface{}{"codes": codes, "reverse": true}, want: 3},
+		{name: "reverse unknown label", input: "TRACE", params: map[string]interface{}{"codes": codes, "reverse": true, "default": -1}, want: -1},
+		{name: "nil input", input: nil, params: map[string]interface{}{"codes": codes, "default": "UNKNOWN"}, want: "UNKNOWN"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := severityMap(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestValidateDateRange tests the validateDateRange validation function.
+func TestValidateDateRange(t *testing.T) {
+	bounds := map[string]interface{}{"min": "1900-01-01", "max": "2030-12-31"}
+	future := time.Now().Add(48 * time.Hour)
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "in range", input: "1985-06-15", params: bounds, want: "1985-06-15"},
+		{name: "on min bound", input: "1900-01-01", params: bounds, want: "1900-01-01"},
+		{name: "on max bound", input: "2030-12-31T00:00:00Z", params: bounds, want: "2030-12-31T00:00:00Z"},
+		{name: "too early", input: "1899-12-31", params: bounds, want: errors.New("date 1899-12-31 is before minimum allowed 1900-01-01")},
+		{name: "too late", input: "2031-01-01", params: bounds, want: errors.New("date 2031-01-01 is after maximum allowed 2030-12-31")},
+		{name: "fallback layout", input: "06/15/1850", params: bounds, want: errors.New("date 06/15/1850 is before minimum allowed 1900-01-01")},
+		{name: "input format", input: "15.06.1985", params: map[string]interface{}{"min": "1900-01-01", "inputFormat": "02.01.2006"}, want: "15.06.1985"},
+		{name: "time.Time value", input: time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC), params: map[string]interface{}{"min": time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)}, want: errors.New("date 1800-01-01 00:00:00 +0000 UTC is before minimum allowed 1900-01-01 00:00:00 +0000 UTC")},
+		{name: "past date before now", input: "2020-01-01", params: map[string]interface{}{"max": "now"}, want: "2020-01-01"},
+		{name: "future date after now", input: future, params: map[string]interface{}{"max": "now"}, want: fmt.Errorf("date %v is after maximum allowed now", future)},
+		{name: "future date after today", input: future.Format("2006-01-02"), params: map[string]interface{}{"max": "today"}, want: fmt.Errorf("date %s is after maximum allowed today", future.Format("2006-01-02"))},
+		{name: "non-date string passes", input: "unknown", params: bounds, want: "unknown"},
+		{name: "non-string passes", input: 1985, params: bounds, want: 1985},
+		{name: "nil passes", input: nil, params: bounds, want: nil},
+		{name: "no bounds", input: "1985-06-15", params: map[string]interface{}{}, want: errors.New("requires at least 'min' or 'max' parameter for validateDateRange")},
+		{name: "invalid bound", input: "1985-06-15", params: map[string]interface{}{"min": "long ago"}, want: errors.New("invalid 'min' parameter: 'long ago' is not a valid date")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, validateDateRange(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestConfigLookup tests the configLookup transformation with an external YAML map file.
+func TestConfigLookup(t *testing.T) {
+	lookupFile := filepath.Join(t.TempDir(), "regions.yaml")
+	content := "US: North America\nDE: Europe\n100: hundred\n"
+	if err := os.WriteFile(lookupFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write lookup file: %v", err)
+	}
+	missingFile := filepath.Join(t.TempDir(), "missing.yaml")
+
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "hit", input: "US", params: map[string]interface{}{"file": lookupFile}, want: "North America"},
+		{name: "hit with default set", input: "DE", params: map[string]interface{}{"file": lookupFile, "default": "Unknown"}, want: "Europe"},
+		{name: "miss uses default", input: "JP", params: map[string]interface{}{"file": lookupFile, "default": "Unknown"}, want: "Unknown"},
+		{name: "miss without default", input: "JP", params: map[string]interface{}{"file": lookupFile}, want: nil},
+		{name: "numeric key matches stringified input", input: 100, params: map[string]interface{}{"file": lookupFile}, want: "hundred"},
+		{name: "nil input uses default", input: nil, params: map[string]interface{}{"file": lookupFile, "default": "Unknown"}, want: "Unknown"},
+		{name: "missing file uses default", input: "US", params: map[string]interface{}{"file": missingFile, "default": "Unknown"}, want: "Unknown"},
+		{name: "missing file param", input: "US", params: map[string]interface{}{}, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := configLookup(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+
+	// Subsequent edits to the file are not observed because the table is cached on first load.
+	if err := os.WriteFile(lookupFile, []byte("US: Changed\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite lookup file: %v", err)
+	}
+	resultsMatch(t, configLookup("US", nil, map[string]interface{}{"file": lookupFile}), "North America")
+}
+
+// TestShard tests the shard transformation for determinism and bucket distribution.
+func TestShard(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "single bucket", input: "anything", params: map[string]interface{}{"buckets": 1}, want: 0},
+		{name: "nil input", input: nil, params: map[string]interface{}{"buckets": 4}, want: nil},
+		{name: "missing buckets", input: "key", params: map[string]interface{}{}, want: nil},
+		{name: "zero buckets", input: "key", params: map[string]interface{}{"buckets": 0}, want: nil},
+		{name: "negative buckets", input: "key", params: map[string]interface{}{"buckets": -2}, want: nil},
+		{name: "numeric and string forms match", input: 42, params: map[string]interface{}{"buckets": 8}, want: shard("42", nil, map[string]interface{}{"buckets": 8})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := shard(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+
+	t.Run("deterministic and distributed", func(t *testing.T) {
+		params := map[string]interface{}{"buckets": 4.0}
+		counts := make(map[int]int)
+		for i := 0; i < 400; i++ {
+			key := fmt.Sprintf("customer-%d", i)
+			first, ok := shard(key, nil, params).(int)
+			if !ok || first < 0 || first >= 4 {
+				t.Fatalf("shard(%q) = %v, want int in [0, 4)", key, first)
+			}
+			if second := shard(key, nil, params); second != first {
+				t.Fatalf("shard(%q) not deterministic: %v then %v", key, first, second)
+			}
+			counts[first]++
+		}
+		for bucket := 0; bucket < 4; bucket++ {
+			if counts[bucket] < 50 {
+				t.Errorf("bucket %d received %d of 400 keys, expected a roughly even spread (counts: %v)", bucket, counts[bucket], counts)
+			}
+		}
+	})
+}
+
+// TestTruncateTime tests the truncateTime transformation.
+func TestTruncateTime(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "15 minutes", input: "2024-03-10T14:37:22Z", params: map[string]interface{}{"interval": "15m"}, want: "2024-03-10T14:30:00Z"},
+		{name: "1 hour", input: "2024-03-10T14:37:22Z", params: map[string]interface{}{"interval": "1h"}, want: "2024-03-10T14:00:00Z"},
+		{name: "already aligned", input: "2024-03-10T14:45:00Z", params: map[string]interface{}{"interval": "15m"}, want: "2024-03-10T14:45:00Z"},
+		{name: "day boundary floors to midnight", input: "2024-12-31T23:59:59Z", params: map[string]interface{}{"interval": "24h"}, want: "2024-12-31T00:00:00Z"},
+		{name: "just after midnight", input: "2025-01-01T00:04:00Z", params: map[string]interface{}{"interval": "1h"}, want: "2025-01-01T00:00:00Z"},
+		{name: "custom formats", input: "2024-03-10 14:37", params: map[string]interface{}{"interval": "5m", "inputFormat": "2006-01-02 15:04", "outputFormat": "15:04"}, want: "14:35"},
+		{name: "parsed offset preserved", input: "2024-03-10T14:37:00+05:30", params: map[string]interface{}{"interval": "1h"}, want: "2024-03-10T14:00:00+05:30"},
+		{name: "timezone conversion", input: "2024-03-10T02:37:00Z", params: map[string]interface{}{"interval": "24h", "timezone": "America/New_York"}, want: "2024-03-09T00:00:00-05:00"},
+		{name: "time.Time input", input: time.Date(2024, 3, 10, 14, 37, 0, 0, time.UTC), params: map[string]interface{}{"interval": "30m"}, want: "2024-03-10T14:30:00Z"},
+		{name: "nil input", input: nil, params: map[string]interface{}{"interval": "1h"}, want: nil},
+		{name: "unparseable input", input: "not a time", params: map[string]interface{}{"interval": "1h"}, want: "not a time"},
+		{name: "invalid interval", input: "2024-03-10T14:37:22Z", params: map[string]interface{}{"interval": "soon"}, want: "2024-03-10T14:37:22Z"},
+		{name: "invalid timezone", input: "2024-03-10T14:37:22Z", params: map[string]interface{}{"interval": "1h", "timezone": "Mars/Base"}, want: "2024-03-10T14:37:22Z"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := truncateTime(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestExecTransform tests the exec transformation using small shell commands.
+func TestExecTransform(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec tests rely on /bin/sh")
+	}
+	record := map[string]interface{}{"name": "alice", "qty": 3}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		SetExecEnabled(false)
+		got := execTransform(nil, record, map[string]interface{}{"command": "cat"})
+		resultsMatch(t, got, fmt.Errorf("exec: external commands are disabled; run with -allow-exec to enable"))
+	})
+
+	SetExecEnabled(true)
+	defer SetExecEnabled(false)
+
+	script := filepath.Join(t.TempDir(), "upper_name.sh")
+	scriptBody := "#!/bin/sh\nsed -e 's/.*\"name\":\"\\([^\"]*\\)\".*/\"\\1\"/' | tr a-z A-Z\n"
+	if err := os.WriteFile(script, []byte(scriptBody), 0755); err != nil {
+		t.Fatalf("Failed to write helper script: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "echo record", params: map[string]interface{}{"command": "cat"}, want: map[string]interface{}{"name": "alice", "qty": 3.0}},
+		{name: "helper script", params: map[string]interface{}{"command": script}, want: "ALICE"},
+		{name: "command with args", params: map[string]interface{}{"command": "sh", "args": []interface{}{"-c", "echo '{\"ok\": true}'"}}, want: map[string]interface{}{"ok": true}},
+		{name: "command failure", params: map[string]interface{}{"command": "sh", "args": []interface{}{"-c", "echo boom >&2; exit 3"}}, want: fmt.Errorf("exec: command 'sh' failed: exit status 3 (stderr: boom)")},
+		{name: "invalid JSON output", params: map[string]interface{}{"command": "sh", "args": []interface{}{"-c", "echo not-json"}}, want: fmt.Errorf("exec: command 'sh' did not write valid JSON to stdout: invalid character 'o' in literal null (expecting 'u')")},
+		{name: "timeout", params: map[string]interface{}{"command": "sleep", "args": []interface{}{"5"}, "timeout": "100ms"}, want: fmt.Errorf("exec: command 'sleep' timed out after 100ms")},
+		{name: "missing command", params: map[string]interface{}{}, want: fmt.Errorf("exec: missing or invalid 'command' parameter")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := execTransform(nil, record, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestParseQueryString tests the parseQueryString transformation.
+func TestParseQueryString(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "simple", input: "utm_source=x&utm_medium=y", params: nil, want: map[string]interface{}{"utm_source": "x", "utm_medium": "y"}},
+		{name: "leading question mark", input: "?a=1", params: nil, want: map[string]interface{}{"a": "1"}},
+		{name: "full URL with fragment", input: "https://example.com/p?utm_source=news&ref=home#top", params: nil, want: map[string]interface{}{"utm_source": "news", "ref": "home"}},
+		{name: "encoded values", input: "q=hello%20world&email=a%40b.com&plus=a+b", params: nil, want: map[string]interface{}{"q": "hello world", "email": "a@b.com", "plus": "a b"}},
+		{name: "repeated keys take first", input: "tag=a&tag=b&tag=c", params: nil, want: map[string]interface{}{"tag": "a"}},
+		{name: "repeated keys join", input: "tag=a&tag=b&tag=c", params: map[string]interface{}{"repeated": "join"}, want: map[string]interface{}{"tag": "a,b,c"}},
+		{name: "repeated keys join custom separator", input: "tag=a&tag=b", params: map[string]interface{}{"repeated": "join", "separator": "|"}, want: map[string]interface{}{"tag": "a|b"}},
+		{name: "key without value", input: "flag&x=1", params: nil, want: map[string]interface{}{"flag": "", "x": "1"}},
+		{name: "malformed escape skipped", input: "bad=%zz&good=1", params: nil, want: map[string]interface{}{"good": "1"}},
+		{name: "semicolon separator is malformed", input: "a=1;b=2&c=3", params: nil, want: map[string]interface{}{"c": "3"}},
+		{name: "empty string", input: "", params: nil, want: map[string]interface{}{}},
+		{name: "nil input", input: nil, params: nil, want: nil},
+		{name: "non-string input", input: 42, params: nil, want: 42},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseQueryString(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestURLParse tests the urlParse transformation.
+func TestURLParse(t *testing.T) {
+	const fullURL = "https://shop.example.com:8443/products/view?id=42&ref=ad#reviews"
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "scheme", input: fullURL, params: map[string]interface{}{"component": "scheme"}, want: "https"},
+		{name: "host", input: fullURL, params: map[string]interface{}{"component": "host"}, want: "shop.example.com"},
+		{name: "path", input: fullURL, params: map[string]interface{}{"component": "path"}, want: "/products/view"},
+		{name: "query", input: fullURL, params: map[string]interface{}{"component": "query"}, want: "id=42&ref=ad"},
+		{name: "fragment", input: fullURL, params: map[string]interface{}{"component": "fragment"}, want: "reviews"},
+		{name: "port", input: fullURL, params: map[string]interface{}{"component": "port"}, want: "8443"},
+		{name: "component case-insensitive", input: fullURL, params: map[string]interface{}{"component": "Host"}, want: "shop.example.com"},
+		{name: "missing port", input: "http://example.com/a", params: map[string]interface{}{"component": "port"}, want: ""},
+		{name: "IPv6 host", input: "http://[::1]:8080/", params: map[string]interface{}{"component": "host"}, want: "::1"},
+		{name: "invalid URL", input: "http://[::1", params: map[string]interface{}{"component": "host"}, want: nil},
+		{name: "invalid escape", input: "http://example.com/%zz", params: map[string]interface{}{"component": "path"}, want: nil},
+		{name: "invalid component", input: fullURL, params: map[string]interface{}{"component": "user"}, want: nil},
+		{name: "nil input", input: nil, params: map[string]interface{}{"component": "host"}, want: nil},
+		{name: "non-string input", input: 42, params: map[string]interface{}{"component": "host"}, want: 42},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := urlParse(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestApplyMask tests the applyMask transformation.
+func TestApplyMask(t *testing.T) {
+	phoneMask := map[string]interface{}{"mask": "(###) ###-####"}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "phone", input: "1234567890", params: phoneMask, want: "(123) 456-7890"},
+		{name: "SSN", input: "123456789", params: map[string]interface{}{"mask": "###-##-####"}, want: "123-45-6789"},
+		{name: "numeric input", input: 1234567890.0, params: phoneMask, want: "(123) 456-7890"},
+		{name: "int input", input: 123456789, params: map[string]interface{}{"mask": "###-##-####"}, want: "123-45-6789"},
+		{name: "shorter than mask", input: "12345", params: phoneMask, want: "(123) 45"},
+		{name: "shorter ends at literal boundary", input: "123", params: phoneMask, want: "(123"},
+		{name: "empty input", input: "", params: phoneMask, want: ""},
+		{name: "extra dropped by default", input: "123456789012", params: phoneMask, want: "(123) 456-7890"},
+		{name: "extra appended", input: "12345678901234", params: map[string]interface{}{"mask": "(###) ###-####", "extra": "append"}, want: "(123) 456-78901234"},
+		{name: "non-digit characters consumed", input: "AB12", params: map[string]interface{}{"mask": "##-##"}, want: "AB-12"},
+		{name: "unicode input", input: "äöü", params: map[string]interface{}{"mask": "#.#.#"}, want: "ä.ö.ü"},
+		{name: "nil input", input: nil, params: phoneMask, want: nil},
+		{name: "missing mask", input: "123", params: map[string]interface{}{}, want: "123"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := applyMask(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestLagField tests the stateful lagField transformation over ordered record sequences.
+func TestLagField(t *testing.T) {
+	run := func(records []map[string]interface{}, params map[string]interface{}) []interface{} {
+		ResetLagState()
+		var got []interface{}
+		for _, rec := range records {
+			got = append(got, lagField(nil, rec, params))
+		}
+		return got
+	}
+	amounts := []map[string]interface{}{{"amount": 100.0}, {"amount": 130.0}, {"amount": 125.0}, {"amount": "140"}}
+
+	t.Run("previous value", func(t *testing.T) {
+		got := run(amounts, map[string]interface{}{"field": "amount"})
+		resultsMatch(t, got, []interface{}{nil, 100.0, 130.0, 125.0})
+	})
+	t.Run("delta", func(t *testing.T) {
+		got := run(amounts, map[string]interface{}{"field": "amount", "delta": true})
+		resultsMatch(t, got, []interface{}{nil, 30.0, -5.0, 15.0})
+	})
+	t.Run("delta with non-numeric value", func(t *testing.T) {
+		got := run([]map[string]interface{}{{"amount": 1}, {"amount": "n/a"}, {"amount": 4}}, map[string]interface{}{"field": "amount", "delta": true})
+		resultsMatch(t, got, []interface{}{nil, nil, nil})
+	})
+	t.Run("partitioned", func(t *testing.T) {
+		records := []map[string]interface{}{
+			{"acct": "A", "amount": 10}, {"acct": "B", "amount": 5}, {"acct": "A", "amount": 12}, {"acct": "B", "amount": 9}, {"acct": "A", "amount": 11},
+		}
+		got := run(records, map[string]interface{}{"field": "amount", "partitionBy": "acct", "delta": true})
+		resultsMatch(t, got, []interface{}{nil, nil, 2.0, 4.0, -1.0})
+	})
+	t.Run("rules sharing a field see the same previous value", func(t *testing.T) {
+		ResetLagState()
+		var prevs, deltas []interface{}
+		for _, rec := range amounts[:3] {
+			prevs = append(prevs, lagField(nil, rec, map[string]interface{}{"field": "amount"}))
+			deltas = append(deltas, lagField(nil, rec, map[string]interface{}{"field": "amount", "delta": true}))
+		}
+		resultsMatch(t, prevs, []interface{}{nil, 100.0, 130.0})
+		resultsMatch(t, deltas, []interface{}{nil, 30.0, -5.0})
+	})
+	t.Run("missing field param", func(t *testing.T) {
+		resultsMatch(t, lagField(nil, map[string]interface{}{"amount": 1}, map[string]interface{}{}), nil)
+	})
+}
+
+// TestNormalizeTimestamp tests normalizeTimestamp and mustNormalizeTimestamp.
+func TestNormalizeTimestamp(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      interface{}
+		params     map[string]interface{}
+		want       interface{}
+		wantStrict interface{}
+	}{
+		{name: "UTC seconds", input: "2024-05-01T12:30:45Z", want: "2024-05-01T12:30:45Z"},
+		{name: "positive offset", input: "2024-05-01T12:30:45+02:00", want: "2024-05-01T10:30:45Z"},
+		{name: "negative offset crosses day", input: "2024-05-01T22:15:00-05:00", want: "2024-05-02T03:15:00Z"},
+		{name: "fractional truncated to seconds", input: "2024-05-01T12:30:45.987654Z", want: "2024-05-01T12:30:45Z"},
+		{name: "fractional millis", input: "2024-05-01T12:30:45.987654Z", params: map[string]interface{}{"precision": "millis"}, want: "2024-05-01T12:30:45.987Z"},
+		{name: "padded micros", input: "2024-05-01T12:30:45.5+01:00", params: map[string]interface{}{"precision": "micros"}, want: "2024-05-01T11:30:45.500000Z"},
+		{name: "nanos", input: "2024-05-01T12:30:45.123456789Z", params: map[string]interface{}{"precision": "nanos"}, want: "2024-05-01T12:30:45.123456789Z"},
+		{name: "naive assumed UTC", input: "2024-05-01T12:30:45", want: "2024-05-01T12:30:45Z"},
+		{name: "naive with fraction", input: "2024-05-01T12:30:45.25", params: map[string]interface{}{"precision": "millis"}, want: "2024-05-01T12:30:45.250Z"},
+		{name: "space separated fallback", input: "2024-05-01 12:30:45", want: "2024-05-01T12:30:45Z"},
+		{name: "date only fallback", input: "2024-05-01", want: "2024-05-01T00:00:00Z"},
+		{name: "custom input format", input: "01.05.2024 12:30", params: map[string]interface{}{"inputFormat": "02.01.2006 15:04"}, want: "2024-05-01T12:30:00Z"},
+		{name: "time.Time input", input: time.Date(2024, 5, 1, 14, 30, 45, 0, time.FixedZone("CEST", 2*3600)), want: "2024-05-01T12:30:45Z"},
+		{name: "invalid input", input: "not a timestamp", want: "not a timestamp", wantStrict: fmt.Errorf("mustNormalizeTimestamp: failed to parse 'not a timestamp' as a timestamp")},
+		{name: "invalid precision", input: "2024-05-01T12:30:45Z", params: map[string]interface{}{"precision": "minutes"}, want: "2024-05-01T12:30:45Z", wantStrict: fmt.Errorf("mustNormalizeTimestamp: invalid 'precision' parameter 'minutes'")},
+		{name: "non-string input", input: 42, want: 42, wantStrict: fmt.Errorf("mustNormalizeTimestamp: input value is not a string or time.Time (type int)")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, normalizeTimestamp(tc.input, nil, tc.params), tc.want)
+			wantStrict := tc.wantStrict
+			if wantStrict == nil {
+				wantStrict = tc.want
+			}
+			resultsMatch(t, mustNormalizeTimestamp(tc.input, nil, tc.params), wantStrict)
+		})
+	}
+}
+
+// TestCompleteness tests the completeness transformation.
+func TestCompleteness(t *testing.T) {
+	params := map[string]interface{}{"fields": []interface{}{"id", "email", "age"}}
+	testCases := []struct {
+		name   string
+		record map[string]interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "all present", record: map[string]interface{}{"id": "1", "email": "a@b.c", "age": 0}, params: params, want: true},
+		{name: "one missing", record: map[string]interface{}{"id": "1", "age": 30}, params: params, want: false},
+		{name: "one nil", record: map[string]interface{}{"id": "1", "email": nil, "age": 30}, params: params, want: false},
+		{name: "one empty", record: map[string]interface{}{"id": "1", "email": "", "age": 30}, params: params, want: false},
+		{name: "one whitespace", record: map[string]interface{}{"id": "1", "email": "   ", "age": 30}, params: params, want: false},
+		{name: "false boolean counts as present", record: map[string]interface{}{"ok": false}, params: map[string]interface{}{"fields": []interface{}{"ok"}}, want: true},
+		{name: "missing fields param", record: map[string]interface{}{"id": "1"}, params: map[string]interface{}{}, want: false},
+		{name: "non-string field name", record: map[string]interface{}{"id": "1"}, params: map[string]interface{}{"fields": []interface{}{1}}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := completeness(nil, tc.record, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestPseudonymize tests the pseudonymize transformation.
+func TestPseudonymize(t *testing.T) {
+	tokenParams := map[string]interface{}{"key": "secret"}
+	t.Run("stable tokens", func(t *testing.T) {
+		a1, a2 := pseudonymize("alice@example.com", nil, tokenParams), pseudonymize("alice@example.com", nil, tokenParams)
+		if a1 != a2 {
+			t.Errorf("same input gave different tokens: %v vs %v", a1, a2)
+		}
+		if s, ok := a1.(string); !ok || !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(s) {
+			t.Errorf("expected 16 hex chars, got %v", a1)
+		}
+		if b := pseudonymize("bob@example.com", nil, tokenParams); b == a1 {
+			t.Errorf("different inputs gave the same token %v", b)
+		}
+		if other := pseudonymize("alice@example.com", nil, map[string]interface{}{"key": "other"}); other == a1 {
+			t.Errorf("different keys gave the same token %v", other)
+		}
+	})
+	t.Run("stable names", func(t *testing.T) {
+		params := map[string]interface{}{"key": "secret", "format": "name"}
+		n1, n2 := pseudonymize("alice", nil, params), pseudonymize("alice", nil, params)
+		if n1 != n2 {
+			t.Errorf("same input gave different names: %v vs %v", n1, n2)
+		}
+		if s, ok := n1.(string); !ok || len(strings.Fields(s)) != 2 {
+			t.Errorf("expected a two-word name, got %v", n1)
+		}
+		distinct := map[interface{}]bool{}
+		for _, in := range []string{"alice", "bob", "carol", "dave", "erin"} {
+			distinct[pseudonymize(in, nil, params)] = true
+		}
+		if len(distinct) < 2 {
+			t.Errorf("expected different inputs to map to different names, got %v", distinct)
+		}
+	})
+
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "token length", input: 42, params: map[string]interface{}{"key": "k", "length": 8}, want: pseudonymize(42, nil, map[string]interface{}{"key": "k", "length": 64}).(string)[:8]},
+		{name: "numeric and string forms match", input: 42, params: tokenParams, want: pseudonymize("42", nil, tokenParams)},
+		{name: "nil input", input: nil, params: tokenParams, want: nil},
+		{name: "missing key", input: "a", params: map[string]interface{}{}, want: nil},
+		{name: "invalid length", input: "a", params: map[string]interface{}{"key": "k", "length": 65}, want: nil},
+		{name: "unknown format", input: "a", params: map[string]interface{}{"key": "k", "format": "uuid"}, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := pseudonymize(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestDetectType tests the detectType transformation.
+func TestDetectType(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		want  interface{}
+	}{
+		{name: "int string", input: "123", want: "int"},
+		{name: "negative int string", input: " -42 ", want: "int"},
+		{name: "float string", input: "1.5", want: "float"},
+		{name: "whole float string", input: "2.0", want: "float"},
+		{name: "exponent string", input: "1e3", want: "float"},
+		{name: "bool string", input: "true", want: "bool"},
+		{name: "bool word", input: "No", want: "bool"},
+		{name: "one is int not bool", input: "1", want: "int"},
+		{name: "date string", input: "2023-01-01", want: "date"},
+		{name: "RFC3339 string", input: "2023-01-01T10:00:00.5Z", want: "date"},
+		{name: "slash date string", input: "01/02/2023", want: "date"},
+		{name: "plain string", input: "hello", want: "string"},
+		{name: "infinity word", input: "Inf", want: "string"},
+		{name: "blank string", input: "  ", want: "null"},
+		{name: "nil", input: nil, want: "null"},
+		{name: "native int", input: 7, want: "int"},
+		{name: "native whole float", input: 7.0, want: "int"},
+		{name: "native float", input: 7.25, want: "float"},
+		{name: "native bool", input: false, want: "bool"},
+		{name: "time value", input: time.Now(), want: "date"},
+		{name: "slice", input: []interface{}{1}, want: "array"},
+		{name: "map", input: map[string]interface{}{"a": 1}, want: "object"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectType(tc.input, nil, nil)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestValidateInFile tests the validateInFile validation.
+func TestValidateInFile(t *testing.T) {
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "customers.csv")
+	if err := os.WriteFile(csvFile, []byte("customer_id,name\nC1,Ann\nC2,Bob\n42,Num\n"), 0644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	jsonFile := filepath.Join(dir, "products.json")
+	if err := os.WriteFile(jsonFile, []byte(`[{"sku": 100}, {"sku": "A-7"}, {"sku": null}]`), 0644); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	csvParams := map[string]interface{}{"file": csvFile, "keyField": "customer_id"}
+	jsonParams := map[string]interface{}{"file": jsonFile, "keyField": "sku"}
+
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "present key", input: "C2", params: csvParams, want: "C2"},
+		{name: "numeric input matches csv text", input: 42, params: csvParams, want: 42},
+		{name: "absent key", input: "C9", params: csvParams, want: fmt.Errorf("value 'C9' not found in column 'customer_id' of reference file '%s'", csvFile)},
+		{name: "nil input passes", input: nil, params: csvParams, want: nil},
+		{name: "json present key", input: "100", params: jsonParams, want: "100"},
+		{name: "json absent key", input: "B-1", params: jsonParams, want: fmt.Errorf("value 'B-1' not found in column 'sku' of reference file '%s'", jsonFile)},
+		{name: "missing key field column", input: "C1", params: map[string]interface{}{"file": csvFile, "keyField": "id"}, want: fmt.Errorf("key field 'id' not found in header of reference file '%s'", csvFile)},
+		{name: "missing params", input: "C1", params: map[string]interface{}{"file": csvFile}, want: errors.New("missing or empty 'file' or 'keyField' string parameter for validateInFile")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := validateInFile(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestDigitsOnly tests the digitsOnly transformation.
+func TestDigitsOnly(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{name: "phone punctuation and spaces", input: "(555) 123-4567", want: "5551234567"},
+		{name: "letters removed", input: "ID: AB-0042x", want: "0042"},
+		{name: "plus dropped by default", input: "+1 555.010.0100", want: "15550100100"},
+		{name: "leading plus kept", input: " +44 (20) 7946-0958", params: map[string]interface{}{"keepLeadingPlus": true}, want: "+442079460958"},
+		{name: "inner plus not kept", input: "1+2", params: map[string]interface{}{"keepLeadingPlus": true}, want: "12"},
+		{name: "non-ascii digits removed", input: "١٢3", want: "3"},
+		{name: "no digits", input: "n/a", want: ""},
+		{name: "non-string passes", input: 12345, want: 12345},
+		{name: "nil passes", input: nil, want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := digitsOnly(tc.input, nil, tc.params)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestAlphaNumericOnly tests the alphaNumericOnly transformation.
+func TestAlphaNumericOnly(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		want  interface{}
+	}{
+		{name: "punctuation and spaces", input: "AB-12 c/3", want: "AB12c3"},
+		{name: "symbols", input: "#A_1!@$", want: "A1"},
+		{name: "unicode letters kept", input: "Zoë-9", want: "Zoë9"},
+		{name: "empty", input: "", want: ""},
+		{name: "non-string passes", input: 4.5, want: 4.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := alphaNumericOnly(tc.input, nil, nil)
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestValidateMonotonic tests the stateful validateMonotonic validation across two keys.
+func TestValidateMonotonic(t *testing.T) {
+	run := func(records []map[string]interface{}, params map[string]interface{}) []bool {
+		ResetLagState()
+		var failed []bool
+		for _, rec := range records {
+			_, isErr := validateMonotonic(rec["ts"], rec, params).(error)
+			failed = append(failed, isErr)
+		}
+		return failed
+	}
+	params := map[string]interface{}{"field": "ts", "keyField": "device"}
+
+	t.Run("in order across two keys", func(t *testing.T) {
+		records := []map[string]interface{}{
+			{"device": "A", "ts": "2024-01-01T00:00:00Z"}, {"device": "B", "ts": "2024-01-01T00:00:05Z"},
+			{"device": "A", "ts": "2024-01-01T00:01:00Z"}, {"device": "B", "ts": "2024-01-01T00:00:30Z"},
+			{"device": "A", "ts": "2024-01-01T00:01:00Z"},
+		}
+		resultsMatch(t, run(records, params), []bool{false, false, false, false, false})
+	})
+	t.Run("out of order for one key", func(t *testing.T) {
+		records := []map[string]interface{}{
+			{"device": "A", "ts": 10}, {"device": "B", "ts": 5}, {"device": "A", "ts": 8}, {"device": "B", "ts": 6}, {"device": "A", "ts": 9},
+		}
+		resultsMatch(t, run(records, params), []bool{false, false, true, false, true})
+	})
+	t.Run("rejected value does not become the baseline", func(t *testing.T) {
+		records := []map[string]interface{}{{"device": "A", "ts": 10}, {"device": "A", "ts": 5}, {"device": "A", "ts": 8}, {"device": "A", "ts": 10}}
+		resultsMatch(t, run(records, params), []bool{false, true, true, false})
+	})
+	t.Run("repeated calls for one record share the baseline", func(t *testing.T) {
+		ResetLagState()
+		first, second := map[string]interface{}{"device": "A", "ts": 1}, map[string]interface{}{"device": "A", "ts": 2}
+		strict := map[string]interface{}{"field": "ts", "keyField": "device", "strict": true}
+		for _, rec := range []map[string]interface{}{first, first, second, second} {
+			if err, isErr := validateMonotonic(rec["ts"], rec, strict).(error); isErr {
+				t.Errorf("unexpected error for ts %v: %v", rec["ts"], err)
+			}
+		}
+	})
+	t.Run("strict rejects repeats", func(t *testing.T) {
+		records := []map[string]interface{}{{"device": "A", "ts": 1}, {"device": "A", "ts": 1}, {"device": "A", "ts": 2}}
+		resultsMatch(t, run(records, map[string]interface{}{"field": "ts", "keyField": "device", "strict": true}), []bool{false, true, false})
+	})
+	t.Run("nil values are skipped", func(t *testing.T) {
+		records := []map[string]interface{}{{"device": "A", "ts": 5}, {"device": "A", "ts": nil}, {"device": "A", "ts": 4}}
+		resultsMatch(t, run(records, params), []bool{false, false, true})
+	})
+	t.Run("error message and value passthrough", func(t *testing.T) {
+		ResetLagState()
+		first := map[string]interface{}{"device": "A", "ts": 10}
+		resultsMatch(t, validateMonotonic("kept", first, params), "kept")
+		got := validateMonotonic("kept", map[string]interface{}{"device": "A", "ts": 3}, params)
+		resultsMatch(t, got, errors.New("'ts' value '3' decreased from previous value '10' for device 'A'"))
+	})
+	t.Run("missing params", func(t *testing.T) {
+		resultsMatch(t, validateMonotonic(1, map[string]interface{}{"ts": 1}, map[string]interface{}{"field": "ts"}), errors.New("missing or empty 'field' or 'keyField' string parameter for validateMonotonic"))
+	})
+}
+
+// TestBitFlag tests the bitFlag transformation over several bit positions.
+func TestBitFlag(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		bit   interface{}
+		want  interface{}
+	}{
+		{"bit 0 set", 5, 0, true},
+		{"bit 1 clear", 5, 1, false},
+		{"bit 2 set", 5, 2, true},
+		{"high bit", int64(1) << 40, 40, true},
+		{"string input", "12", 3, true},
+		{"float input", 12.0, 0, false},
+		{"negative uses two's complement", -1, 63, true},
+		{"zero", 0, 7, false},
+		{"non-integer input", "abc", 0, nil},
+		{"fractional input", 1.5, 0, nil},
+		{"nil input", nil, 0, nil},
+		{"negative bit", 5, -1, nil},
+		{"bit too large", 5, 64, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, bitFlag(tc.input, nil, map[string]interface{}{"bit": tc.bit}), tc.want)
+		})
+	}
+}
+
+// TestConvertUnit tests the convertUnit transformation for temperature, distance, and weight.
+func TestConvertUnit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    interface{}
+		from, to string
+		want     interface{}
+	}{
+		{"fahrenheit to celsius", 212, "f", "c", 100.0},
+		{"freezing fahrenheit to celsius", 32, "f", "c", 0.0},
+		{"celsius to fahrenheit", "-40", "C", "F", -40.0},
+		{"freezing celsius to fahrenheit", 0, "c", "f", 32.0},
+		{"body temperature", 98.6, "f", "c", 37.0},
+		{"celsius to kelvin", 0, "c", "k", 273.15},
+		{"kelvin to fahrenheit", 0, "k", "f", -459.67},
+		{"fahrenheit to kelvin", 212, "f", "k", 373.15},
+		{"miles to kilometers", 1, "mi", "km", 1.609344},
+		{"feet to inches", 1, "ft", "in", 12.0},
+		{"feet to inches fraction", 2.5, "ft", "in", 30.0},
+		{"inches to centimeters", 1, "in", "cm", 2.54},
+		{"ounces to grams", 16, "oz", "g", 453.59237},
+		{"pounds to kilograms", 10, "lb", "kg", 4.5359237},
+		{"same unit", 7, "m", "m", 7.0},
+		{"cross-dimension pair", 1, "kg", "m", nil},
+		{"unknown unit", 1, "furlong", "m", nil},
+		{"non-numeric input", "abc", "m", "km", nil},
+		{"nil input", nil, "m", "km", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := convertUnit(tc.input, nil, map[string]interface{}{"from": tc.from, "to": tc.to})
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestCheckDigit tests checkDigit and mustCheckDigit for each scheme and mode.
+func TestCheckDigit(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      interface{}
+		scheme     string
+		mode       string
+		want       interface{}
+		wantStrict interface{}
+	}{
+		{name: "ean13 append", input: "400638133393", scheme: "ean13", want: "4006381333931"},
+		{name: "ean13 append integer", input: 501234567890, scheme: "ean13", want: "5012345678900"},
+		{name: "ean13 verify valid", input: "4006381333931", scheme: "ean13", mode: "verify", want: true},
+		{name: "ean13 verify invalid", input: "4006381333932", scheme: "ean13", mode: "verify", want: false},
+		{name: "isbn10 append", input: "0-306-40615", scheme: "isbn10", want: "0306406152"},
+		{name: "isbn10 append X", input: "080442957", scheme: "ISBN10", want: "080442957X"},
+		{name: "isbn10 verify X", input: "0-8044-2957-x", scheme: "isbn10", mode: "verify", want: true},
+		{name: "luhn append", input: "7992739871", scheme: "luhn", want: "79927398713"},
+		{name: "luhn verify", input: "4539 1488 0343 6467", scheme: "luhn", mode: "verify", want: true},
+		{name: "luhn verify invalid", input: "79927398710", scheme: "luhn", mode: "verify", want: false},
+		{name: "ean13 wrong length", input: "12345", scheme: "ean13", want: nil, wantStrict: errors.New("mustCheckDigit: input '12345' must have 12 digits for ean13")},
+		{name: "non-digits", input: "4006381A3393", scheme: "ean13", want: nil, wantStrict: errors.New("mustCheckDigit: input '4006381A3393' contains non-digit characters")},
+		{name: "unknown scheme", input: "123", scheme: "upc", want: nil, wantStrict: errors.New("mustCheckDigit: invalid 'scheme' parameter 'upc'")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := map[string]interface{}{"scheme": tc.scheme}
+			if tc.mode != "" {
+				params["mode"] = tc.mode
+			}
+			resultsMatch(t, checkDigit(tc.input, nil, params), tc.want)
+			wantStrict := tc.wantStrict
+			if wantStrict == nil {
+				wantStrict = tc.want
+			}
+			resultsMatch(t, mustCheckDigit(tc.input, nil, params), wantStrict)
+		})
+	}
+}
+
+// TestTimezoneInfo tests timezoneInfo for several zones across DST boundaries.
+func TestTimezoneInfo(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"new york winter", "2024-01-15", map[string]interface{}{"timezone": "America/New_York"}, "-05:00"},
+		{"new york summer", "2024-07-01T12:00:00", map[string]interface{}{"timezone": "America/New_York"}, "-04:00"},
+		{"new york before spring forward", "2024-03-10T01:59:59", map[string]interface{}{"timezone": "America/New_York", "output": "abbreviation"}, "EST"},
+		{"new york after spring forward", "2024-03-10T03:00:00", map[string]interface{}{"timezone": "America/New_York", "output": "abbreviation"}, "EDT"},
+		{"london before BST (UTC input)", "2024-03-31T00:59:59Z", map[string]interface{}{"timezone": "Europe/London"}, "+00:00"},
+		{"london after BST (UTC input)", "2024-03-31T01:00:00Z", map[string]interface{}{"timezone": "Europe/London"}, "+01:00"},
+		{"sydney summer seconds", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), map[string]interface{}{"timezone": "Australia/Sydney", "output": "seconds"}, 39600},
+		{"sydney winter seconds", time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), map[string]interface{}{"timezone": "Australia/Sydney", "output": "seconds"}, 36000},
+		{"kolkata half hour", "15/08/2024", map[string]interface{}{"timezone": "Asia/Kolkata", "inputFormat": "02/01/2006"}, "+05:30"},
+		{"unparseable input", "not a date", map[string]interface{}{"timezone": "UTC"}, nil},
+		{"invalid timezone", "2024-01-15", map[string]interface{}{"timezone": "Mars/Olympus"}, nil},
+		{"nil input", nil, map[string]interface{}{"timezone": "UTC"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, timezoneInfo(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestCoalesceTransform_Then tests coalesce with a nested 'then' transform applied to the chosen value.
+func TestCoalesceTransform_Then(t *testing.T) {
+	record := map[string]interface{}{"nickname": "", "name": "  ada lovelace  ", "code": "x1"}
+	testCases := []struct {
+		name   string
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"then trim", map[string]interface{}{"fields": []interface{}{"nickname", "name"}, "then": "trim"}, "ada lovelace"},
+		{"then uppercase", map[string]interface{}{"fields": []interface{}{"nickname", "name"}, "then": "toUpperCase"}, "  ADA LOVELACE  "},
+		{"then with params", map[string]interface{}{"fields": []interface{}{"name"}, "then": "replaceAll", "thenParams": map[string]interface{}{"old": " ", "new": "_"}}, "__ada_lovelace__"},
+		{"then with shorthand", map[string]interface{}{"fields": []interface{}{"code"}, "then": "regexExtract:[a-z]([0-9]+)"}, "1"},
+		{"then validation error passes through", map[string]interface{}{"fields": []interface{}{"code"}, "then": "validateRegex", "thenParams": map[string]interface{}{"pattern": "^[0-9]+$"}}, errors.New("value \"x1\" does not match required pattern '^[0-9]+$'")},
+		{"no value skips then", map[string]interface{}{"fields": []interface{}{"nickname", "missing"}, "then": "toUpperCase"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, coalesceTransform(nil, record, tc.params), tc.want)
+		})
+	}
+}
+
+// TestValidatePrintable tests the validatePrintable validation function.
+func TestValidatePrintable(t *testing.T) {
+	asciiOnly := map[string]interface{}{"asciiOnly": true}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"clean text", "Hello, world! 123", nil, "Hello, world! 123"},
+		{"empty string", "", nil, ""},
+		{"unicode allowed by default", "café – naïve", nil, "café – naïve"},
+		{"embedded NUL", "abc\x00def", nil, errors.New(`value "abc\x00def" contains control character U+0000 at byte 3`)},
+		{"embedded escape", "\x1b[31mred", nil, errors.New(`value "\x1b[31mred" contains control character U+001B at byte 0`)},
+		{"newline rejected", "line1\nline2", nil, errors.New(`value "line1\nline2" contains control character U+000A at byte 5`)},
+		{"DEL rejected", "a\x7f", nil, errors.New(`value "a\x7f" contains control character U+007F at byte 1`)},
+		{"ascii only clean", "plain ASCII ~", asciiOnly, "plain ASCII ~"},
+		{"ascii only rejects accent", "café", asciiOnly, errors.New(`value "café" contains non-ASCII character U+00E9 at byte 3`)},
+		{"ascii only false allows accent", "café", map[string]interface{}{"asciiOnly": false}, "café"},
+		{"non-string passes", 42, asciiOnly, 42},
+		{"nil passes", nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, validatePrintable(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestValidateMAC tests the validateMAC validation function.
+func TestValidateMAC(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		want  interface{}
+	}{
+		{"colon", "00:1a:2b:3c:4d:5e", "00:1a:2b:3c:4d:5e"},
+		{"hyphen", "00-1A-2B-3C-4D-5E", "00-1A-2B-3C-4D-5E"},
+		{"dotted", "001a.2b3c.4d5e", "001a.2b3c.4d5e"},
+		{"bare", "001A2B3C4D5E", "001A2B3C4D5E"},
+		{"invalid hex", "00:1a:2b:3c:4d:zz", errors.New(`value "00:1a:2b:3c:4d:zz" is not a valid MAC address`)},
+		{"too short", "00:1a:2b", errors.New(`value "00:1a:2b" is not a valid MAC address`)},
+		{"empty", "", errors.New(`value "" is not a valid MAC address`)},
+		{"non-string passes", 42, 42},
+		{"nil passes", nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, validateMAC(tc.input, nil, nil), tc.want)
+		})
+	}
+}
+
+// TestStripControl tests the stripControl transformation function.
+func TestStripControl(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"clean text unchanged", "Hello, world!", nil, "Hello, world!"},
+		{"removes NUL and escape", "a\x00b\x1b[0mc", nil, "ab[0mc"},
+		{"removes tabs and newlines", "col1\tcol2\r\nnext", nil, "col1col2next"},
+		{"keepWhitespace preserves tabs and newlines", "col1\tcol2\r\nnext\x07", map[string]interface{}{"keepWhitespace": true}, "col1\tcol2\r\nnext"},
+		{"non-ASCII kept by default", "café\x7f", nil, "café"},
+		{"asciiOnly strips non-ASCII", "café – ok\n", map[string]interface{}{"asciiOnly": true}, "caf  ok"},
+		{"asciiOnly with keepWhitespace", "naïve\tpoint\n", map[string]interface{}{"asciiOnly": true, "keepWhitespace": true}, "nave\tpoint\n"},
+		{"empty string", "", nil, ""},
+		{"non-string passes", 12, nil, 12},
+		{"nil passes", nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, stripControl(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestWordCountAndCharCount tests the wordCount and charCount transformation functions.
+func TestWordCountAndCharCount(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     interface{}
+		wantWords interface{}
+		wantChars interface{}
+	}{
+		{"simple sentence", "the quick brown fox", 4, 19},
+		{"empty string", "", 0, 0},
+		{"whitespace only", " \t\n ", 0, 4},
+		{"multiple spaces", "  two   words  ", 2, 15},
+		{"tabs and newlines", "a\tb\nc", 3, 5},
+		{"multibyte characters", "café naïve 日本", 3, 13},
+		{"emoji counted as one rune", "hi 👋", 2, 4},
+		{"non-string returns nil", 123, nil, nil},
+		{"nil returns nil", nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, wordCount(tc.input, nil, nil), tc.wantWords)
+			resultsMatch(t, charCount(tc.input, nil, nil), tc.wantChars)
+		})
+	}
+}
+
+// TestReverseString tests the reverseString transformation function.
+func TestReverseString(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		want  interface{}
+	}{
+		{"ascii", "abc123", "321cba"},
+		{"multibyte", "héllo", "olléh"},
+		{"cjk", "日本語", "語本日"},
+		{"single character", "x", "x"},
+		{"empty string", "", ""},
+		{"non-string passes", 42, 42},
+		{"nil passes", nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, reverseString(tc.input, nil, nil), tc.want)
+		})
+	}
+}
+
+// TestInitials tests the initials transformation function.
+func TestInitials(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"multi-word name", "John Michael Smith", nil, "JMS"},
+		{"single name", "Madonna", nil, "M"},
+		{"extra whitespace", "  john \t  michael\n smith  ", nil, "JMS"},
+		{"lowercase upper-cased", "ada lovelace", nil, "AL"},
+		{"multibyte runes", "Émile Zola", nil, "ÉZ"},
+		{"count limit", "John Michael Smith", map[string]interface{}{"count": 2}, "JM"},
+		{"count above words", "John Smith", map[string]interface{}{"count": 5}, "JS"},
+		{"separator", "John Michael Smith", map[string]interface{}{"separator": "."}, "J.M.S"},
+		{"empty string", "", nil, ""},
+		{"whitespace only", "   ", nil, ""},
+		{"non-string", 42, nil, 42},
+		{"nil", nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, initials(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestNormalizeMAC tests the normalizeMAC transformation function.
+func TestNormalizeMAC(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"colon", "00:1A:2b:3C:4d:5E", nil, "00:1a:2b:3c:4d:5e"},
+		{"hyphen", "00-1A-2B-3C-4D-5E", nil, "00:1a:2b:3c:4d:5e"},
+		{"dotted", "001a.2b3c.4d5e", nil, "00:1a:2b:3c:4d:5e"},
+		{"bare", "001A2B3C4D5E", nil, "00:1a:2b:3c:4d:5e"},
+		{"surrounding whitespace", "  00:1a:2b:3c:4d:5e ", nil, "00:1a:2b:3c:4d:5e"},
+		{"EUI-64", "02:00:5e:10:00:00:00:01", nil, "02:00:5e:10:00:00:00:01"},
+		{"hyphen separator uppercase", "001a.2b3c.4d5e", map[string]interface{}{"separator": "-", "uppercase": true}, "00-1A-2B-3C-4D-5E"},
+		{"dotted separator", "00:1a:2b:3c:4d:5e", map[string]interface{}{"separator": "."}, "001a.2b3c.4d5e"},
+		{"bare separator", "00-1a-2b-3c-4d-5e", map[string]interface{}{"separator": ""}, "001a2b3c4d5e"},
+		{"invalid", "not-a-mac", nil, nil},
+		{"bad bare length", "001A2B3C4D", nil, nil},
+		{"non-string", 42, nil, nil},
+		{"nil", nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, normalizeMAC(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestBase32AndRot13 tests base32Encode, base32Decode, mustBase32Decode, and rot13.
+func TestBase32AndRot13(t *testing.T) {
+	t.Run("base32 round trip", func(t *testing.T) {
+		for _, input := range []string{"", "hi", "Hello, World!", "café ☕", "12345"} {
+			encoded := base32Encode(input, nil, nil)
+			resultsMatch(t, base32Decode(encoded, nil, nil), input)
+			resultsMatch(t, mustBase32Decode(encoded, nil, nil), input)
+		}
+	})
+	t.Run("base32 encode", func(t *testing.T) {
+		resultsMatch(t, base32Encode("hi", nil, nil), "NBUQ====")
+		resultsMatch(t, base32Encode(42, nil, nil), "GQZA====")
+		resultsMatch(t, base32Encode(nil, nil, nil), nil)
+	})
+	t.Run("base32 decode lenient forms", func(t *testing.T) {
+		resultsMatch(t, base32Decode(" nbuq==== ", nil, nil), "hi")
+		resultsMatch(t, base32Decode("NBUQ", nil, nil), "hi")
+	})
+	t.Run("base32 decode invalid", func(t *testing.T) {
+		for _, input := range []interface{}{"NBU1====", "not base32!", "74======", 42, nil} {
+			resultsMatch(t, base32Decode(input, nil, nil), nil)
+		}
+		resultsMatch(t, mustBase32Decode("NBU1====", nil, nil), errors.New(`mustBase32Decode: invalid base32 input "NBU1====": illegal base32 data at input byte 3`))
+		resultsMatch(t, mustBase32Decode("74======", nil, nil), errors.New(`mustBase32Decode: decoded base32 input "74======" is not valid UTF-8 text`))
+		resultsMatch(t, mustBase32Decode(nil, nil, nil), errors.New("mustBase32Decode: input must be a string, got <nil>"))
+	})
+	t.Run("rot13", func(t *testing.T) {
+		resultsMatch(t, rot13("Hello, World! 123", nil, nil), "Uryyb, Jbeyq! 123")
+		resultsMatch(t, rot13(rot13("The quick brown fox; café", nil, nil), nil, nil), "The quick brown fox; café")
+		resultsMatch(t, rot13(42, nil, nil), 42)
+		resultsMatch(t, rot13(nil, nil, nil), nil)
+	})
+}
+
+// TestRenderTemplate tests the template transformation function.
+func TestRenderTemplate(t *testing.T) {
+	record := map[string]interface{}{"first": "Ada", "last": "Lovelace", "age": 36, "title": nil, "home town": "London"}
+	testCases := []struct {
+		name   string
+		record map[string]interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"several fields", record, map[string]interface{}{"template": "{{.last}}, {{.first}} ({{.age}})"}, "Lovelace, Ada (36)"},
+		{"field name with space", record, map[string]interface{}{"template": `{{.first}} of {{index . "home town"}}`}, "Ada of London"},
+		{"missing field renders empty", record, map[string]interface{}{"template": "[{{.middle}}]"}, "[]"},
+		{"nil field renders empty", record, map[string]interface{}{"template": "[{{.title}}]"}, "[]"},
+		{"conditional on missing field", record, map[string]interface{}{"template": "{{if .title}}{{.title}} {{end}}{{.first}}"}, "Ada"},
+		{"nil record", nil, map[string]interface{}{"template": "x{{.first}}y"}, "xy"},
+		{"literal <no value> in record data is kept", map[string]interface{}{"note": "<no value>"}, map[string]interface{}{"template": "[{{.note}}][{{.missing}}]"}, "[<no value>][]"},
+		{"literal <no value> in template is kept", record, map[string]interface{}{"template": "<no value> {{.middle}}"}, "<no value> "},
+		{"missing fields inside with and range", record, map[string]interface{}{"template": "{{with .first}}{{.}}{{$.middle}}{{end}}{{range $i, $v := .tags}}{{$v}}{{else}}{{.title}}-{{end}}"}, "Ada-"},
+		{"variable assignment prints nothing", record, map[string]interface{}{"template": "{{$m := .middle}}[{{$m}}]"}, "[]"},
+		{"defined template", record, map[string]interface{}{"template": `{{define "name"}}{{.first}}{{.middle}}{{end}}<{{template "name" .}}>`}, "<Ada>"},
+		{"missing template param", record, nil, nil},
+		{"syntax error returns nil", record, map[string]interface{}{"template": "{{.first"}, nil},
+		{"execution error returns nil", record, map[string]interface{}{"template": "{{.first.sub}}"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, renderTemplate(nil, tc.record, tc.params), tc.want)
+		})
+	}
+}
+
+// TestDynamicField tests the dynamicField transformation function.
+func TestDynamicField(t *testing.T) {
+	record := map[string]interface{}{"metric": "temp", "temp": 21.5, "humidity": 40, "empty": nil}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		record map[string]interface{}
+		want   interface{}
+	}{
+		{"resolves named field", "temp", record, 21.5},
+		{"resolves another field", "humidity", record, 40},
+		{"field present with nil value", "empty", record, nil},
+		{"missing field returns nil", "pressure", record, nil},
+		{"empty name returns nil", "", record, nil},
+		{"non-string input returns nil", 42, record, nil},
+		{"nil input returns nil", nil, record, nil},
+		{"nil record returns nil", "temp", nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, dynamicField(tc.input, tc.record, nil), tc.want)
+		})
+	}
+}
+
+// TestValidateHash tests the validateHash validation function.
+func TestValidateHash(t *testing.T) {
+	params := map[string]interface{}{"fields": []interface{}{"id", "amount"}, "algorithm": "sha256", "expectedField": "checksum"}
+	base := map[string]interface{}{"id": "A-1", "amount": 42.5}
+	digest, ok := hashTransform(nil, base, params).(string)
+	if !ok {
+		t.Fatalf("hashTransform() did not return a string digest")
+	}
+	withChecksum := func(checksum interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": "A-1", "amount": 42.5, "checksum": checksum}
+	}
+	testCases := []struct {
+		name   string
+		record map[string]interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"matching hash", withChecksum(digest), params, "row"},
+		{"matching hash upper case with spaces", withChecksum("  " + strings.ToUpper(digest) + " "), params, "row"},
+		{"mismatching hash", map[string]interface{}{"id": "A-1", "amount": 43.5, "checksum": digest}, params, fmt.Errorf("hash mismatch: field 'checksum' has %s, computed %s", digest, hashTransform(nil, map[string]interface{}{"id": "A-1", "amount": 43.5}, params))},
+		{"missing expected field", base, params, errors.New("expected hash field 'checksum' is missing or empty")},
+		{"empty expected field", withChecksum(""), params, errors.New("expected hash field 'checksum' is missing or empty")},
+		{"missing expectedField param", withChecksum(digest), map[string]interface{}{"fields": []interface{}{"id"}, "algorithm": "sha256"}, errors.New("missing or empty 'expectedField' string parameter for validateHash")},
+		{"hash parameter error passes through", withChecksum(digest), map[string]interface{}{"fields": []interface{}{"id"}, "algorithm": "crc32", "expectedField": "checksum"}, errors.New("unsupported hash algorithm: crc32")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, validateHash("row", tc.record, tc.params), tc.want)
+		})
+	}
+}
+
+// TestFlattenMap tests the flattenMap transformation function.
+func TestFlattenMap(t *testing.T) {
+	nested := map[string]interface{}{
+		"city": "Oslo",
+		"geo":  map[string]interface{}{"lat": 59.9, "lon": 10.7},
+		"tags": []interface{}{"home", map[string]interface{}{"kind": "work"}},
+		"none": nil,
+		"skip": map[string]interface{}{},
+	}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"nested objects and arrays", nested, nil, MergeFields{"city": "Oslo", "geo.lat": 59.9, "geo.lon": 10.7, "tags.0": "home", "tags.1.kind": "work", "none": nil}},
+		{"with prefix", map[string]interface{}{"city": "Oslo", "geo": map[string]interface{}{"lat": 59.9}}, map[string]interface{}{"prefix": "address"}, MergeFields{"address.city": "Oslo", "address.geo.lat": 59.9}},
+		{"custom separator", map[string]interface{}{"geo": map[string]interface{}{"lat": 59.9}}, map[string]interface{}{"prefix": "address", "separator": "_"}, MergeFields{"address_geo_lat": 59.9}},
+		{"top-level array uses indexes", []interface{}{"a", []interface{}{"b", "c"}}, map[string]interface{}{"prefix": "items"}, MergeFields{"items.0": "a", "items.1.0": "b", "items.1.1": "c"}},
+		{"yaml-style map keys", map[interface{}]interface{}{1: "one"}, map[string]interface{}{"prefix": "n"}, MergeFields{"n.1": "one"}},
+		{"empty map", map[string]interface{}{}, nil, MergeFields{}},
+		{"nil input", nil, nil, nil},
+		{"scalar input", "text", nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := flattenMap(tc.input, nil, tc.params)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("flattenMap() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestOrdinal tests the ordinal transformation function.
+func TestOrdinal(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input interface{}
+		want  interface{}
+	}{
+		{"1", 1, "1st"},
+		{"2", 2, "2nd"},
+		{"3", 3, "3rd"},
+		{"4", 4, "4th"},
+		{"11", 11, "11th"},
+		{"12", 12, "12th"},
+		{"13", 13, "13th"},
+		{"21", 21, "21st"},
+		{"22 string", "22", "22nd"},
+		{"101", 101, "101st"},
+		{"111", 111, "111th"},
+		{"112", int64(112), "112th"},
+		{"zero", 0, "0th"},
+		{"negative", -3, "-3rd"},
+		{"whole float", 2.0, "2nd"},
+		{"fractional float", 2.5, nil},
+		{"non-numeric string", "first", nil},
+		{"nil", nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, ordinal(tc.input, nil, nil), tc.want)
+		})
+	}
+}
+
+// TestNumberToWords tests the numberToWords transformation function.
+func TestNumberToWords(t *testing.T) {
+	currency := map[string]interface{}{"currency": true}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"zero", 0, nil, "zero"},
+		{"single digit", 7, nil, "seven"},
+		{"teen", "13", nil, "thirteen"},
+		{"round tens", 40, nil, "forty"},
+		{"hyphenated tens", 42, nil, "forty-two"},
+		{"hundreds", 305, nil, "three hundred five"},
+		{"thousands", "1234", nil, "one thousand two hundred thirty-four"},
+		{"skips empty groups", 2000001, nil, "two million one"},
+		{"billions", int64(3000000000), nil, "three billion"},
+		{"max int64", int64(math.MaxInt64), nil, "nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+		{"negative", -15, nil, "minus fifteen"},
+		{"min int64", int64(math.MinInt64), nil, "minus nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+		{"whole float", 21.0, nil, "twenty-one"},
+		{"fraction without currency", 12.5, nil, nil},
+		{"non-numeric", "many", nil, nil},
+		{"nil", nil, nil, nil},
+		{"currency", 1234.56, currency, "one thousand two hundred thirty-four dollars and fifty-six cents"},
+		{"currency singular", "1.01", currency, "one dollar and one cent"},
+		{"currency zero", 0, currency, "zero dollars and zero cents"},
+		{"currency rounds cents", 2.999, currency, "three dollars and zero cents"},
+		{"currency negative", -0.5, currency, "minus zero dollars and fifty cents"},
+		{"currency too large", 1e15, currency, nil},
+		{"currency non-numeric", "abc", currency, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, numberToWords(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestJsonParse tests the jsonParse and mustJsonParse transformation functions.
+func TestJsonParse(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      interface{}
+		want       interface{}
+		wantStrict interface{} // Expected mustJsonParse result; nil means an error is expected
+	}{
+		{"object", `{"a": 1, "b": {"c": "x"}}`, map[string]interface{}{"a": 1.0, "b": map[string]interface{}{"c": "x"}}, map[string]interface{}{"a": 1.0, "b": map[string]interface{}{"c": "x"}}},
+		{"array", `[1, "two", null]`, []interface{}{1.0, "two", nil}, []interface{}{1.0, "two", nil}},
+		{"number", "42.5", 42.5, 42.5},
+		{"string", `"hello"`, "hello", "hello"},
+		{"boolean", " true ", true, true},
+		{"invalid JSON", `{"a": 1`, `{"a": 1`, nil},
+		{"trailing data", `{"a": 1} x`, `{"a": 1} x`, nil},
+		{"empty string", "", "", nil},
+		{"already parsed map", map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1}, nil},
+		{"non-string", 7, 7, nil},
+		{"nil", nil, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, jsonParse(tc.input, nil, nil), tc.want)
+			strict := mustJsonParse(tc.input, nil, nil)
+			if tc.wantStrict == nil {
+				if err, ok := strict.(error); !ok || !strings.HasPrefix(err.Error(), "mustJsonParse: ") {
+					t.Errorf("mustJsonParse(%v) = %v, want error", tc.input, strict)
+				}
+				return
+			}
+			resultsMatch(t, strict, tc.wantStrict)
+		})
+	}
+}
+
+// TestFormatBool tests the formatBool transformation function.
+func TestFormatBool(t *testing.T) {
+	yesNo := map[string]interface{}{"trueValue": "Yes", "falseValue": "No"}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"true", true, yesNo, "Yes"},
+		{"false", false, yesNo, "No"},
+		{"string true", "TRUE", yesNo, "Yes"},
+		{"string yes", "yes", yesNo, "Yes"},
+		{"string zero", "0", yesNo, "No"},
+		{"string n", "n", yesNo, "No"},
+		{"integer one", 1, yesNo, "Yes"},
+		{"digit output", false, map[string]interface{}{"trueValue": "1", "falseValue": "0"}, "0"},
+		{"empty output allowed", false, map[string]interface{}{"trueValue": "X", "falseValue": ""}, ""},
+		{"unrecognized string", "maybe", yesNo, nil},
+		{"nil", nil, yesNo, nil},
+		{"missing param", true, map[string]interface{}{"falseValue": "No"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, formatBool(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestJsonStringify tests the jsonStringify transformation function.
+func TestJsonStringify(t *testing.T) {
+	nested := map[string]interface{}{"z": 1, "a": []interface{}{"x", nil}, "m": map[string]interface{}{"b": true, "a": "<&>"}}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"sorted keys", nested, nil, `{"a":["x",null],"m":{"a":"<&>","b":true},"z":1}`},
+		{"indent", map[string]interface{}{"a": 1}, map[string]interface{}{"indent": "  "}, "{\n  \"a\": 1\n}"},
+		{"nil", nil, nil, "null"},
+		{"nil with indent", nil, map[string]interface{}{"indent": "  "}, "null"},
+		{"unserializable", math.NaN(), nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, jsonStringify(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestValidateCharset tests the validateCharset validation function.
+func TestValidateCharset(t *testing.T) {
+	idCharset := map[string]interface{}{"charset": "A-Z0-9_"}
+	testCases := []struct {
+		name    string
+		input   interface{}
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{"compliant custom charset", "ORDER_2024", idCharset, ""},
+		{"lowercase rejected", "Order_2024", idCharset, "disallowed character 'r' at byte 1"},
+		{"space rejected", "ORDER 2024", idCharset, "disallowed character ' ' at byte 5"},
+		{"empty string", "", idCharset, ""},
+		{"literal trailing hyphen", "AB-12", map[string]interface{}{"charset": "A-Z0-9-"}, ""},
+		{"literal characters", "a.b", map[string]interface{}{"charset": "ab."}, ""},
+		{"named alnum", "abc123XYZ", map[string]interface{}{"charset": "alnum"}, ""},
+		{"named alnum with space", "abc 123", map[string]interface{}{"charset": "alnum"}, "disallowed character ' '"},
+		{"named digit", "12a", map[string]interface{}{"charset": "DIGIT"}, "disallowed character 'a' at byte 2"},
+		{"named hex", "00ff", map[string]interface{}{"charset": "hex"}, ""},
+		{"named alpha rejects non-ASCII", "café", map[string]interface{}{"charset": "alpha"}, "disallowed character 'é'"},
+		{"reversed range", "a", map[string]interface{}{"charset": "z-a"}, "invalid range 'z-a'"},
+		{"missing charset", "a", nil, "'charset' parameter is required"},
+		{"non-string passes", 12, map[string]interface{}{"charset": "alpha"}, ""},
+		{"nil passes", nil, idCharset, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validateCharset(tc.input, nil, tc.params)
+			err, isErr := result.(error)
+			if tc.wantErr == "" {
+				if isErr {
+					t.Fatalf("validateCharset(%v) unexpected error: %v", tc.input, err)
+				}
+				resultsMatch(t, result, tc.input)
+				return
+			}
+			if !isErr || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("validateCharset(%v) = %v, want error containing %q", tc.input, result, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestApplyIf tests the applyIf transformation function.
+func TestApplyIf(t *testing.T) {
+	upperIfX := map[string]interface{}{"condition": "type == 'X'", "then": "toUpperCase"}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		record map[string]interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"condition true applies transform", "gadget", map[string]interface{}{"type": "X"}, upperIfX, "GADGET"},
+		{"condition false passes through", "gadget", map[string]interface{}{"type": "Y"}, upperIfX, "gadget"},
+		{"condition on inputValue", "abc", nil, map[string]interface{}{"condition": "inputValue != 'skip'", "then": "toUpperCase"}, "ABC"},
+		{"missing field passes through", "gadget", map[string]interface{}{}, upperIfX, "gadget"},
+		{"non-bool condition passes through", "gadget", map[string]interface{}{"n": 1.0}, map[string]interface{}{"condition": "n + 1", "then": "toUpperCase"}, "gadget"},
+		{"invalid condition passes through", "gadget", map[string]interface{}{"type": "X"}, map[string]interface{}{"condition": "type ==", "then": "toUpperCase"}, "gadget"},
+		{"thenParams", "7", map[string]interface{}{"type": "X"}, map[string]interface{}{"condition": "type == 'X'", "then": "padLeft", "thenParams": map[string]interface{}{"length": 3, "pad": "0"}}, "007"},
+		{"shorthand then", "id-42", map[string]interface{}{"type": "X"}, map[string]interface{}{"condition": "type == 'X'", "then": "regexExtract:([0-9]+)"}, "42"},
+		{"missing then", "gadget", map[string]interface{}{"type": "X"}, map[string]interface{}{"condition": "type == 'X'"}, "gadget"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, applyIf(tc.input, tc.record, tc.params), tc.want)
+		})
+	}
+
+	t.Run("strict then error passes through", func(t *testing.T) {
+		result := applyIf("abc", map[string]interface{}{"type": "X"}, map[string]interface{}{"condition": "type == 'X'", "then": "mustToInt"})
+		if _, isErr := result.(error); !isErr {
+			t.Errorf("applyIf() = %v, want error from mustToInt", result)
+		}
+	})
+}
+
+// TestNumberFormat tests the numberFormat transformation function.
+func TestNumberFormat(t *testing.T) {
+	european := map[string]interface{}{"thousandsSep": ".", "decimalSep": ","}
+	testCases := []struct {
+		name   string
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"default format", 1234567.5, nil, "1,234,567.50"},
+		{"numeric string", "1234.5", nil, "1,234.50"},
+		{"integer", 1000, nil, "1,000.00"},
+		{"small number", 999, nil, "999.00"},
+		{"negative", -1234567.891, nil, "-1,234,567.89"},
+		{"negative rounding to zero", -0.001, nil, "0.00"},
+		{"zero decimals", 1234.5, map[string]interface{}{"decimals": 0}, "1,235"},
+		{"zero decimals negative", -999999.4, map[string]interface{}{"decimals": 0}, "-999,999"},
+		{"more decimals", 0.5, map[string]interface{}{"decimals": 4}, "0.5000"},
+		{"rounds half up on decimal digits", 2.675, nil, "2.68"},
+		{"rounding carries into new group", 999999.995, nil, "1,000,000.00"},
+		{"tiny value", 1e-7, map[string]interface{}{"decimals": 3}, "0.000"},
+		{"european separators", 1234567.5, european, "1.234.567,50"},
+		{"space and comma", 9876543.21, map[string]interface{}{"thousandsSep": " ", "decimalSep": ","}, "9 876 543,21"},
+		{"no grouping", 1234567.5, map[string]interface{}{"thousandsSep": ""}, "1234567.50"},
+		{"very large", 1e20, nil, "100,000,000,000,000,000,000.00"},
+		{"large int64", int64(9007199254740993), map[string]interface{}{"decimals": 0}, "9,007,199,254,740,992"},
+		{"non-numeric", "n/a", nil, "n/a"},
+		{"already grouped string", "1,234", nil, "1,234"},
+		{"nil", nil, nil, nil},
+		{"NaN", math.NaN(), nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := numberFormat(tc.input, nil, tc.params)
+			if f, isFloat := tc.input.(float64); isFloat && math.IsNaN(f) {
+				if g, ok := got.(float64); !ok || !math.IsNaN(g) {
+					t.Errorf("numberFormat(NaN) = %v, want NaN unchanged", got)
+				}
+				return
+			}
+			resultsMatch(t, got, tc.want)
+		})
+	}
+}
+
+// TestConstantAndEnv tests the constant and env transformation functions.
+func TestConstantAndEnv(t *testing.T) {
+	t.Setenv("ETL_TEST_BATCH", "2024-07")
+	t.Setenv("ETL_TEST_EMPTY", "")
+	testCases := []struct {
+		name   string
+		fn     TransformFunc
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"constant string", constantValue, map[string]interface{}{"value": "nightly"}, "nightly"},
+		{"constant number", constantValue, map[string]interface{}{"value": 42}, 42},
+		{"constant nil", constantValue, map[string]interface{}{"value": nil}, nil},
+		{"constant env-expanded", constantValue, map[string]interface{}{"value": "batch-${ETL_TEST_BATCH}"}, "batch-2024-07"},
+		{"constant windows-style env", constantValue, map[string]interface{}{"value": "%ETL_TEST_BATCH%"}, "2024-07"},
+		{"constant unset var", constantValue, map[string]interface{}{"value": "x$ETL_TEST_UNSET_VAR"}, "x"},
+		{"constant missing value", constantValue, nil, nil},
+		{"env set", envValue, map[string]interface{}{"name": "ETL_TEST_BATCH"}, "2024-07"},
+		{"env set but empty", envValue, map[string]interface{}{"name": "ETL_TEST_EMPTY", "default": "d"}, ""},
+		{"env unset", envValue, map[string]interface{}{"name": "ETL_TEST_UNSET_VAR"}, nil},
+		{"env unset with default", envValue, map[string]interface{}{"name": "ETL_TEST_UNSET_VAR", "default": "dev"}, "dev"},
+		{"env missing name", envValue, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, tc.fn("ignored input", nil, tc.params), tc.want)
+		})
+	}
+}
+
+// TestIsWeekendAndWeekdayName tests isWeekend and weekdayName, including fallback layouts, timezones, and locales.
+func TestIsWeekendAndWeekdayName(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       interface{}
+		params      map[string]interface{}
+		wantWeekend interface{}
+		wantName    interface{}
+	}{
+		{"saturday", "2024-06-15", nil, true, "Saturday"},
+		{"sunday", "2024-06-16T10:30:00Z", nil, true, "Sunday"},
+		{"monday", "2024-06-17", nil, false, "Monday"},
+		{"friday fallback layout", "06/14/2024", nil, false, "Friday"},
+		{"wednesday time.Time", time.Date(2024, 6, 12, 8, 0, 0, 0, time.UTC), nil, false, "Wednesday"},
+		{"custom input format", "14.06.2024", map[string]interface{}{"inputFormat": "02.01.2006"}, false, "Friday"},
+		{"timezone moves to saturday", "2024-06-14T23:30:00-04:00", map[string]interface{}{"timezone": "UTC"}, true, "Saturday"},
+		{"timezone moves to friday", "2024-06-15T02:00:00Z", map[string]interface{}{"timezone": "America/Los_Angeles"}, false, "Friday"},
+		{"invalid date", "2024-02-30", nil, nil, nil},
+		{"not a date", "next tuesday", nil, nil, nil},
+		{"non-string input", 20240615, nil, nil, nil},
+		{"nil input", nil, nil, nil, nil},
+		{"invalid timezone", "2024-06-15", map[string]interface{}{"timezone": "Mars/Olympus"}, nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, isWeekend(tc.input, nil, tc.params), tc.wantWeekend)
+			resultsMatch(t, weekdayName(tc.input, nil, tc.params), tc.wantName)
+		})
+	}
+
+	localeCases := []struct {
+		locale string
+		want   interface{}
+	}{
+		{"", "Saturday"},
+		{"de", "Samstag"},
+		{"es", "sábado"},
+		{"fr", "samedi"},
+		{"fr-CA", "samedi"},
+		{"IT", "sabato"},
+		{"nl_BE", "zaterdag"},
+		{"pt-BR", "sábado"},
+		{"xx", nil},
+	}
+	for _, tc := range localeCases {
+		t.Run("locale "+tc.locale, func(t *testing.T) {
+			resultsMatch(t, weekdayName("2024-06-15", nil, map[string]interface{}{"locale": tc.locale}), tc.want)
+		})
+	}
+}
+
+// TestAffixTransforms tests ensurePrefix, ensureSuffix, stripPrefix, and stripSuffix.
+func TestAffixTransforms(t *testing.T) {
+	id := map[string]interface{}{"value": "ID-"}
+	idFold := map[string]interface{}{"value": "ID-", "ignoreCase": true}
+	csv := map[string]interface{}{"value": ".csv"}
+	csvFold := map[string]interface{}{"value": ".CSV", "ignoreCase": true}
+	testCases := []struct {
+		name   string
+		fn     TransformFunc
+		input  interface{}
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"ensurePrefix absent", ensurePrefix, "42", id, "ID-42"},
+		{"ensurePrefix present", ensurePrefix, "ID-42", id, "ID-42"},
+		{"ensurePrefix other case is absent", ensurePrefix, "id-42", id, "ID-id-42"},
+		{"ensurePrefix other case with ignoreCase", ensurePrefix, "id-42", idFold, "id-42"},
+		{"ensurePrefix empty string", ensurePrefix, "", id, "ID-"},
+		{"ensurePrefix multibyte", ensurePrefix, "übung", map[string]interface{}{"value": "Ü", "ignoreCase": true}, "übung"},
+		{"ensureSuffix absent", ensureSuffix, "report", csv, "report.csv"},
+		{"ensureSuffix present", ensureSuffix, "report.csv", csv, "report.csv"},
+		{"ensureSuffix other case with ignoreCase", ensureSuffix, "REPORT.Csv", csvFold, "REPORT.Csv"},
+		{"stripPrefix present", stripPrefix, "ID-42", id, "42"},
+		{"stripPrefix only once", stripPrefix, "ID-ID-42", id, "ID-42"},
+		{"stripPrefix absent", stripPrefix, "42", id, "42"},
+		{"stripPrefix other case is absent", stripPrefix, "id-42", id, "id-42"},
+		{"stripPrefix other case with ignoreCase", stripPrefix, "id-42", idFold, "42"},
+		{"stripPrefix whole value", stripPrefix, "ID-", id, ""},
+		{"stripSuffix present", stripSuffix, "report.csv", csv, "report"},
+		{"stripSuffix absent", stripSuffix, "report.txt", csv, "report.txt"},
+		{"stripSuffix other case with ignoreCase", stripSuffix, "REPORT.csv", csvFold, "REPORT"},
+		{"stripSuffix shorter than affix", stripSuffix, "sv", csv, "sv"},
+		{"non-string passes through", ensurePrefix, 42, id, 42},
+		{"nil passes through", stripSuffix, nil, csv, nil},
+		{"missing value param", ensurePrefix, "42", map[string]interface{}{}, "42"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resultsMatch(t, tc.fn(tc.input, nil, tc.params), tc.want)
+		})
+	}
+}
+
+// BenchmarkRegexExtract compares regexExtract with the compiled-pattern cache against compiling the pattern for
+// every record, over 100k records per iteration.
+func BenchmarkRegexExtract(b *testing.B) {
+	const recordCount = 100000
+	values := make([]string, recordCount)
+	for i := range values {
+		values[i] = fmt.Sprintf("Order ID: %d, Status: Active", i)
+	}
+	params := map[string]interface{}{"pattern": `Order ID: (\d+)`}
+
+	b.Run("cached", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, v := range values {
+				regexExtract(v, nil, params)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, v := range values {
+				re := regexp.MustCompile(params["pattern"].(string))
+				re.FindStringSubmatch(v)
+			}
+		}
+	})
+}
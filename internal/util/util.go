@@ -153,4 +153,14 @@ func MaskSensitiveData(data map[string]interface{}) map[string]interface{} {
 		}
 	}
 	return maskedMap
-}
\ No newline at end of file
+}
+
+// LocaleLanguage returns the lowercase language part of a locale such as "fr", "en-US", or "pt_BR"
+// (e.g., "en" for " en-US "). An empty locale returns "".
+func LocaleLanguage(locale string) string {
+	lang := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
@@ -503,4 +503,23 @@ func TestMaskSensitiveData(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+// TestLocaleLanguage tests extracting the language from a locale.
+func TestLocaleLanguage(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"fr", "fr"},
+		{"en-US", "en"},
+		{"pt_BR", "pt"},
+		{" DE ", "de"},
+		{"", ""},
+	}
+	for _, tc := range testCases {
+		if got := LocaleLanguage(tc.input); got != tc.want {
+			t.Errorf("LocaleLanguage(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}